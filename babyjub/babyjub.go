@@ -0,0 +1,131 @@
+// Package babyjub implements the Baby Jubjub twisted Edwards curve, which is defined over the BN128 scalar field R,
+// so its points can be operated inside the circuits, and the EdDSA signatures over it, both as native Go functions
+// and as circuit gadgets to use from the circuitcompiler.Builder.
+package babyjub
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// BabyJubJub is the data structure of the twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2 over the Finite Field R
+type BabyJubJub struct {
+	F        fields.Fq
+	A        *big.Int
+	D        *big.Int
+	G        [2]*big.Int // generator of the whole curve
+	B8       [2]*big.Int // base point of the prime order subgroup, B8 = 8 * G
+	Order    *big.Int    // order of the curve, Order = 8 * SubOrder
+	SubOrder *big.Int    // order of the subgroup generated by B8
+}
+
+// NewBabyJubJub returns the BabyJubJub curve
+func NewBabyJubJub() (BabyJubJub, error) {
+	var bj BabyJubJub
+	f, err := bn128.NewFqR()
+	if err != nil {
+		return bj, err
+	}
+	bj.F = f
+	bj.A = big.NewInt(int64(168700))
+	bj.D = big.NewInt(int64(168696))
+
+	gx, ok := new(big.Int).SetString("995203441582195749578291179787384436505546430278305826713579947235728471134", 10)
+	if !ok {
+		return bj, errors.New("err with gx")
+	}
+	gy, ok := new(big.Int).SetString("5472060717959818805561601436314318772137091100104008585924551046643952123905", 10)
+	if !ok {
+		return bj, errors.New("err with gy")
+	}
+	bj.G = [2]*big.Int{gx, gy}
+
+	b8x, ok := new(big.Int).SetString("5299619240641551281634865583518297030282874472190772894086521144482721001553", 10)
+	if !ok {
+		return bj, errors.New("err with b8x")
+	}
+	b8y, ok := new(big.Int).SetString("16950150798460657717958625567821834550301663161624707787222815936182638968203", 10)
+	if !ok {
+		return bj, errors.New("err with b8y")
+	}
+	bj.B8 = [2]*big.Int{b8x, b8y}
+
+	subOrder, ok := new(big.Int).SetString("2736030358979909402780800718157159386076813972158567259200215660948447373041", 10)
+	if !ok {
+		return bj, errors.New("err with subOrder")
+	}
+	bj.SubOrder = subOrder
+	bj.Order = new(big.Int).Mul(subOrder, big.NewInt(int64(8)))
+	return bj, nil
+}
+
+// Zero returns the neutral element of the curve, (0, 1)
+func (bj BabyJubJub) Zero() [2]*big.Int {
+	return [2]*big.Int{bj.F.Zero(), bj.F.One()}
+}
+
+// IsZero returns true if the point is the neutral element of the curve
+func (bj BabyJubJub) IsZero(p [2]*big.Int) bool {
+	return bj.Equal(p, bj.Zero())
+}
+
+// Equal returns true if the two points are equal
+func (bj BabyJubJub) Equal(p1, p2 [2]*big.Int) bool {
+	return bj.F.Equal(p1[0], p2[0]) && bj.F.Equal(p1[1], p2[1])
+}
+
+// IsOnCurve returns true if the point satisfies the curve equation
+func (bj BabyJubJub) IsOnCurve(p [2]*big.Int) bool {
+	x2 := bj.F.Square(p[0])
+	y2 := bj.F.Square(p[1])
+	lhs := bj.F.Add(bj.F.Mul(bj.A, x2), y2)
+	rhs := bj.F.Add(bj.F.One(), bj.F.Mul(bj.D, bj.F.Mul(x2, y2)))
+	return bj.F.Equal(lhs, rhs)
+}
+
+// InSubgroup returns true if the point is on the curve and in the prime order subgroup generated by B8
+func (bj BabyJubJub) InSubgroup(p [2]*big.Int) bool {
+	return bj.IsOnCurve(p) && bj.IsZero(bj.MulScalar(p, bj.SubOrder))
+}
+
+// Add adds two points of the curve, the addition law is complete, so it is valid also for the doubling and the neutral element
+func (bj BabyJubJub) Add(p1, p2 [2]*big.Int) [2]*big.Int {
+	// https://en.wikipedia.org/wiki/Twisted_Edwards_curve#Addition_on_twisted_Edwards_curves
+	x1x2 := bj.F.Mul(p1[0], p2[0])
+	y1y2 := bj.F.Mul(p1[1], p2[1])
+	tau := bj.F.Mul(bj.D, bj.F.Mul(x1x2, y1y2))
+
+	x3 := bj.F.Div(
+		bj.F.Add(bj.F.Mul(p1[0], p2[1]), bj.F.Mul(p1[1], p2[0])),
+		bj.F.Add(bj.F.One(), tau))
+	y3 := bj.F.Div(
+		bj.F.Sub(y1y2, bj.F.Mul(bj.A, x1x2)),
+		bj.F.Sub(bj.F.One(), tau))
+	return [2]*big.Int{bj.F.Affine(x3), bj.F.Affine(y3)}
+}
+
+// Double doubles a point of the curve
+func (bj BabyJubJub) Double(p [2]*big.Int) [2]*big.Int {
+	return bj.Add(p, p)
+}
+
+// Neg returns the negation of a point of the curve, (-x, y)
+func (bj BabyJubJub) Neg(p [2]*big.Int) [2]*big.Int {
+	return [2]*big.Int{bj.F.Affine(bj.F.Neg(p[0])), p[1]}
+}
+
+// MulScalar multiplies a point of the curve by the scalar e
+func (bj BabyJubJub) MulScalar(p [2]*big.Int, e *big.Int) [2]*big.Int {
+	// double and add, from the most significant bit
+	q := bj.Zero()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		q = bj.Double(q)
+		if e.Bit(i) == 1 {
+			q = bj.Add(q, p)
+		}
+	}
+	return q
+}
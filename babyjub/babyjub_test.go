@@ -0,0 +1,54 @@
+package babyjub
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBabyJubJub(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	assert.True(t, bj.IsOnCurve(bj.G))
+	assert.True(t, bj.IsOnCurve(bj.B8))
+	assert.True(t, bj.Equal(bj.B8, bj.MulScalar(bj.G, big.NewInt(int64(8)))))
+	assert.True(t, bj.IsZero(bj.MulScalar(bj.G, bj.Order)))
+	assert.True(t, bj.IsZero(bj.MulScalar(bj.B8, bj.SubOrder)))
+	assert.True(t, bj.InSubgroup(bj.B8))
+	assert.False(t, bj.InSubgroup(bj.G))
+
+	// neutral element
+	assert.True(t, bj.IsZero(bj.Add(bj.Zero(), bj.Zero())))
+	assert.True(t, bj.Equal(bj.G, bj.Add(bj.G, bj.Zero())))
+	assert.True(t, bj.IsZero(bj.Add(bj.G, bj.Neg(bj.G))))
+}
+
+func TestBabyJubJubAdd(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	// same values than the circomlib tests
+	x, _ := new(big.Int).SetString("17777552123799933955779906779655732241715742912184938656739573121738514868268", 10)
+	y, _ := new(big.Int).SetString("2626589144620713026669568689430873010625803728049924121243784502389097019475", 10)
+	p := [2]*big.Int{x, y}
+	assert.True(t, bj.IsOnCurve(p))
+
+	p2 := bj.Add(p, p)
+	assert.Equal(t, "6890855772600357754907169075114257697580319025794532037257385534741338397365", p2[0].String())
+	assert.Equal(t, "4338620300185947561074059802482547481416142213883829469920100239455078257889", p2[1].String())
+	assert.True(t, bj.Equal(p2, bj.Double(p)))
+
+	p3 := bj.MulScalar(p, big.NewInt(int64(3)))
+	assert.Equal(t, "19372461775513343691590086534037741906533799473648040012278229434133483800898", p3[0].String())
+	assert.Equal(t, "9458658722007214007257525444427903161243386465067105737478306991484593958249", p3[1].String())
+	assert.True(t, bj.Equal(p3, bj.Add(p2, p)))
+
+	// (a + b) * P = a * P + b * P
+	a := big.NewInt(int64(123456789))
+	b := big.NewInt(int64(987654321))
+	assert.True(t, bj.Equal(
+		bj.MulScalar(p, new(big.Int).Add(a, b)),
+		bj.Add(bj.MulScalar(p, a), bj.MulScalar(p, b))))
+}
@@ -0,0 +1,113 @@
+package babyjub
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/gadgets"
+)
+
+// ConstantPoint returns the Variables of the coordinates of a constant point
+func (bj BabyJubJub) ConstantPoint(b *circuitcompiler.Builder, p [2]*big.Int) [2]circuitcompiler.Variable {
+	return [2]circuitcompiler.Variable{b.Constant(p[0]), b.Constant(p[1])}
+}
+
+// AddGadget adds to the Builder the constraints of the addition of two points, returning the Variables of the result
+func (bj BabyJubJub) AddGadget(b *circuitcompiler.Builder, p1, p2 [2]circuitcompiler.Variable) [2]circuitcompiler.Variable {
+	one := b.Constant(big.NewInt(int64(1)))
+	x1x2 := b.Mul(p1[0], p2[0])
+	y1y2 := b.Mul(p1[1], p2[1])
+	tau := b.Mul(b.Constant(bj.D), b.Mul(x1x2, y1y2))
+	// the denominators are never zero, as the addition law is complete
+	x3 := b.Div(
+		b.Add(b.Mul(p1[0], p2[1]), b.Mul(p1[1], p2[0])),
+		b.Add(one, tau))
+	y3 := b.Div(
+		b.Sub(y1y2, b.Mul(b.Constant(bj.A), x1x2)),
+		b.Sub(one, tau))
+	return [2]circuitcompiler.Variable{x3, y3}
+}
+
+// DoubleGadget adds to the Builder the constraints of the doubling of a point, returning the Variables of the result
+func (bj BabyJubJub) DoubleGadget(b *circuitcompiler.Builder, p [2]circuitcompiler.Variable) [2]circuitcompiler.Variable {
+	return bj.AddGadget(b, p, p)
+}
+
+// OnCurveGadget adds to the Builder the constraints that check that the point satisfies the curve equation
+func (bj BabyJubJub) OnCurveGadget(b *circuitcompiler.Builder, p [2]circuitcompiler.Variable) {
+	x2 := b.Mul(p[0], p[0])
+	y2 := b.Mul(p[1], p[1])
+	lhs := b.Add(b.Mul(b.Constant(bj.A), x2), y2)
+	rhs := b.Add(b.Constant(big.NewInt(int64(1))), b.Mul(b.Constant(bj.D), b.Mul(x2, y2)))
+	b.AssertEqual(lhs, rhs)
+}
+
+// selectPoint returns the Variables of p1 if the bit is 1, and the ones of p0 if the bit is 0
+func selectPoint(b *circuitcompiler.Builder, bit circuitcompiler.Variable, p1, p0 [2]circuitcompiler.Variable) [2]circuitcompiler.Variable {
+	// p0 + bit * (p1 - p0)
+	return [2]circuitcompiler.Variable{
+		b.Add(p0[0], b.Mul(bit, b.Sub(p1[0], p0[0]))),
+		b.Add(p0[1], b.Mul(bit, b.Sub(p1[1], p0[1]))),
+	}
+}
+
+// MulScalarGadget adds to the Builder the constraints of the multiplication of a point by the scalar with the given
+// bits (starting from the least significant one, as returned by Builder.ToBits), returning the Variables of the result
+func (bj BabyJubJub) MulScalarGadget(b *circuitcompiler.Builder, p [2]circuitcompiler.Variable, bits []circuitcompiler.Variable) [2]circuitcompiler.Variable {
+	if len(bits) == 0 {
+		return bj.ConstantPoint(b, bj.Zero())
+	}
+	// double and add, from the most significant bit
+	q := selectPoint(b, bits[len(bits)-1], p, bj.ConstantPoint(b, bj.Zero()))
+	for i := len(bits) - 2; i >= 0; i-- {
+		q = bj.DoubleGadget(b, q)
+		q = selectPoint(b, bits[i], bj.AddGadget(b, q, p), q)
+	}
+	return q
+}
+
+// assertLessThan adds to the Builder the constraints that check that the number with the given bits (starting from
+// the least significant one) is less than the constant c
+func assertLessThan(b *circuitcompiler.Builder, bits []circuitcompiler.Variable, c *big.Int) {
+	one := b.Constant(big.NewInt(int64(1)))
+	// from the most significant bit, lt is 1 once a bit is less than the bit of c, while the previous bits are equal
+	lt := b.Constant(big.NewInt(int64(0)))
+	eq := one
+	for i := len(bits) - 1; i >= 0; i-- {
+		notBit := b.Sub(one, bits[i])
+		if c.Bit(i) == 1 {
+			lt = b.Add(lt, b.Mul(eq, notBit))
+			eq = b.Mul(eq, bits[i])
+		} else {
+			eq = b.Mul(eq, notBit)
+		}
+	}
+	b.AssertEqual(lt, one)
+}
+
+// VerifyPoseidonGadget adds to the Builder the constraints of the verification of the EdDSA Poseidon signature (r8, s)
+// of the message for the public key pk, in the same way than BabyJubJub.VerifyPoseidon
+func (bj BabyJubJub) VerifyPoseidonGadget(b *circuitcompiler.Builder, pk [2]circuitcompiler.Variable, msg circuitcompiler.Variable, r8 [2]circuitcompiler.Variable, s circuitcompiler.Variable) error {
+	bj.OnCurveGadget(b, pk)
+	bj.OnCurveGadget(b, r8)
+
+	// S < SubOrder
+	sBits := b.ToBits(s, bj.SubOrder.BitLen())
+	assertLessThan(b, sBits, bj.SubOrder)
+
+	hm, err := gadgets.PoseidonHashGadget(b, []circuitcompiler.Variable{r8[0], r8[1], pk[0], pk[1], msg})
+	if err != nil {
+		return err
+	}
+	// the bits of the hash must be the ones of its value in the Finite Field
+	hmBits := b.ToBits(hm, bj.F.Q.BitLen())
+	assertLessThan(b, hmBits, bj.F.Q)
+
+	// S * B8 = R8 + H(R8, A, msg) * (8 * A)
+	left := bj.MulScalarGadget(b, bj.ConstantPoint(b, bj.B8), sBits)
+	pk8 := bj.DoubleGadget(b, bj.DoubleGadget(b, bj.DoubleGadget(b, pk)))
+	right := bj.AddGadget(b, r8, bj.MulScalarGadget(b, pk8, hmBits))
+	b.AssertEqual(left[0], right[0])
+	b.AssertEqual(left[1], right[1])
+	return nil
+}
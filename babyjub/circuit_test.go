@@ -0,0 +1,118 @@
+package babyjub
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddGadget(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	b := circuitcompiler.NewBuilder()
+	p1 := [2]circuitcompiler.Variable{b.PrivateInput("x1"), b.PrivateInput("y1")}
+	p2 := [2]circuitcompiler.Variable{b.PrivateInput("x2"), b.PrivateInput("y2")}
+	p3 := [2]circuitcompiler.Variable{b.PublicInput("x3"), b.PublicInput("y3")}
+	bj.OnCurveGadget(b, p1)
+	q := bj.AddGadget(b, p1, p2)
+	b.AssertEqual(p3[0], q[0])
+	b.AssertEqual(p3[1], q[1])
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	g2 := bj.Double(bj.G)
+	g3 := bj.Add(bj.G, g2)
+	assignment := circuitcompiler.Assignment{
+		p1[0]: bj.G[0], p1[1]: bj.G[1],
+		p2[0]: g2[0], p2[1]: g2[1],
+		p3[0]: g3[0], p3[1]: g3[1],
+	}
+	assert.Nil(t, b.Check(assignment))
+	w, err := b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	// wrong result
+	assignment[p3[0]] = g2[0]
+	assert.NotNil(t, b.Check(assignment))
+	// point not on the curve
+	assignment[p3[0]] = g3[0]
+	assignment[p1[1]] = big.NewInt(int64(1))
+	assert.NotNil(t, b.Check(assignment))
+}
+
+func TestMulScalarGadget(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	b := circuitcompiler.NewBuilder()
+	e := b.PrivateInput("e")
+	p := [2]circuitcompiler.Variable{b.PublicInput("x"), b.PublicInput("y")}
+	q := bj.MulScalarGadget(b, bj.ConstantPoint(b, bj.B8), b.ToBits(e, 8))
+	b.AssertEqual(p[0], q[0])
+	b.AssertEqual(p[1], q[1])
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	for _, eValue := range []int64{0, 1, 2, 200, 255} {
+		pValue := bj.MulScalar(bj.B8, big.NewInt(eValue))
+		assignment := circuitcompiler.Assignment{
+			e:    big.NewInt(eValue),
+			p[0]: pValue[0],
+			p[1]: pValue[1],
+		}
+		assert.Nil(t, b.Check(assignment))
+		w, err := b.Witness(circuit, assignment)
+		assert.Nil(t, err)
+		assert.True(t, circuit.IsSatisfied(w))
+	}
+
+	// the scalar does not fit in the bits
+	pValue := bj.MulScalar(bj.B8, big.NewInt(int64(256)))
+	assert.NotNil(t, b.Check(circuitcompiler.Assignment{
+		e:    big.NewInt(int64(256)),
+		p[0]: pValue[0],
+		p[1]: pValue[1],
+	}))
+}
+
+func TestVerifyPoseidonGadget(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	k, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	pkValue := bj.PublicKey(k)
+	msgValue := big.NewInt(int64(1234))
+	sig, err := bj.SignPoseidon(k, msgValue)
+	assert.Nil(t, err)
+
+	b := circuitcompiler.NewBuilder()
+	pk := [2]circuitcompiler.Variable{b.PublicInput("ax"), b.PublicInput("ay")}
+	msg := b.PublicInput("msg")
+	r8 := [2]circuitcompiler.Variable{b.PrivateInput("r8x"), b.PrivateInput("r8y")}
+	s := b.PrivateInput("s")
+	assert.Nil(t, bj.VerifyPoseidonGadget(b, pk, msg, r8, s))
+
+	// the circuit is too big for the dense R1CS, so the constraints are checked with Builder.Check
+	assignment := circuitcompiler.Assignment{
+		pk[0]: pkValue[0],
+		pk[1]: pkValue[1],
+		msg:   msgValue,
+		r8[0]: sig.R8[0],
+		r8[1]: sig.R8[1],
+		s:     sig.S,
+	}
+	assert.Nil(t, b.Check(assignment))
+
+	// another message
+	assignment[msg] = big.NewInt(int64(1235))
+	assert.NotNil(t, b.Check(assignment))
+	// S not reduced
+	assignment[msg] = msgValue
+	assignment[s] = new(big.Int).Add(sig.S, bj.SubOrder)
+	assert.NotNil(t, b.Check(assignment))
+}
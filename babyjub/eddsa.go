@@ -0,0 +1,108 @@
+package babyjub
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/gadgets"
+)
+
+// PrivateKey is an EdDSA private key, the secret scalar is derived from its hash
+type PrivateKey [32]byte
+
+// Signature is an EdDSA signature, where R8 = r * B8 and S = r + H(R8, A, msg) * 8 * s (mod SubOrder)
+type Signature struct {
+	R8 [2]*big.Int
+	S  *big.Int
+}
+
+// NewRandPrivateKey generates a new random PrivateKey
+func NewRandPrivateKey() (PrivateKey, error) {
+	var k PrivateKey
+	_, err := rand.Read(k[:])
+	return k, err
+}
+
+// leToBigInt returns the big.Int of the little-endian bytes
+func leToBigInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// bigIntToLE returns the n little-endian bytes of the big.Int
+func bigIntToLE(a *big.Int, n int) []byte {
+	be := a.Bytes()
+	le := make([]byte, n)
+	for i := 0; i < len(be) && i < n; i++ {
+		le[i] = be[len(be)-1-i]
+	}
+	return le
+}
+
+// Scalar returns the secret scalar of the PrivateKey, taken from the first half of the SHA-512 hash of the key
+// pruned as in EdDSA, and divided by 8 so the public key is a multiple of B8
+func (k PrivateKey) Scalar() *big.Int {
+	h := sha512.Sum512(k[:])
+	sBuf := h[:32]
+	sBuf[0] = sBuf[0] & 0xF8
+	sBuf[31] = sBuf[31] & 0x7F
+	sBuf[31] = sBuf[31] | 0x40
+	s := leToBigInt(sBuf)
+	return s.Rsh(s, 3)
+}
+
+// PublicKey returns the public key of the PrivateKey, A = s * B8
+func (bj BabyJubJub) PublicKey(k PrivateKey) [2]*big.Int {
+	return bj.MulScalar(bj.B8, k.Scalar())
+}
+
+// hashPoseidon returns the hash of the signed message, H(R8, A, msg)
+func (bj BabyJubJub) hashPoseidon(r8, pk [2]*big.Int, msg *big.Int) (*big.Int, error) {
+	return gadgets.PoseidonHash([]*big.Int{r8[0], r8[1], pk[0], pk[1], msg})
+}
+
+// SignPoseidon signs the message with the PrivateKey, using the Poseidon hash. The message is an element of the Finite Field R
+func (bj BabyJubJub) SignPoseidon(k PrivateKey, msg *big.Int) (Signature, error) {
+	var sig Signature
+	if msg.Sign() < 0 || msg.Cmp(bj.F.Q) >= 0 {
+		return sig, errors.New("eddsa: msg not inside the Finite Field")
+	}
+	h := sha512.Sum512(k[:])
+	// deterministic nonce, from the second half of the hash of the key and the message
+	rHash := sha512.Sum512(append(h[32:], bigIntToLE(msg, 32)...))
+	r := new(big.Int).Mod(leToBigInt(rHash[:]), bj.SubOrder)
+
+	sig.R8 = bj.MulScalar(bj.B8, r)
+	pk := bj.PublicKey(k)
+	hm, err := bj.hashPoseidon(sig.R8, pk, msg)
+	if err != nil {
+		return sig, err
+	}
+	s := new(big.Int).Lsh(k.Scalar(), 3)
+	s.Mul(s, hm)
+	s.Add(s, r)
+	sig.S = s.Mod(s, bj.SubOrder)
+	return sig, nil
+}
+
+// VerifyPoseidon verifies the Signature of the message for the public key, checking that S * B8 = R8 + 8 * H(R8, A, msg) * A
+func (bj BabyJubJub) VerifyPoseidon(pk [2]*big.Int, msg *big.Int, sig Signature) bool {
+	if !bj.IsOnCurve(pk) || !bj.IsOnCurve(sig.R8) {
+		return false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(bj.SubOrder) >= 0 {
+		return false
+	}
+	hm, err := bj.hashPoseidon(sig.R8, pk, msg)
+	if err != nil {
+		return false
+	}
+	left := bj.MulScalar(bj.B8, sig.S)
+	right := bj.Add(sig.R8, bj.MulScalar(pk, new(big.Int).Mul(hm, big.NewInt(int64(8)))))
+	return bj.Equal(left, right)
+}
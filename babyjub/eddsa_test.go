@@ -0,0 +1,44 @@
+package babyjub
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdDSAPoseidon(t *testing.T) {
+	bj, err := NewBabyJubJub()
+	assert.Nil(t, err)
+
+	k, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	pk := bj.PublicKey(k)
+	assert.True(t, bj.InSubgroup(pk))
+
+	msg := big.NewInt(int64(1234))
+	sig, err := bj.SignPoseidon(k, msg)
+	assert.Nil(t, err)
+	assert.True(t, bj.VerifyPoseidon(pk, msg, sig))
+
+	// the signature is deterministic
+	sig2, err := bj.SignPoseidon(k, msg)
+	assert.Nil(t, err)
+	assert.Equal(t, sig, sig2)
+
+	// another message
+	assert.False(t, bj.VerifyPoseidon(pk, big.NewInt(int64(1235)), sig))
+	// another public key
+	k2, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	assert.False(t, bj.VerifyPoseidon(bj.PublicKey(k2), msg, sig))
+	// S not reduced
+	sigNotReduced := Signature{R8: sig.R8, S: new(big.Int).Add(sig.S, bj.SubOrder)}
+	assert.False(t, bj.VerifyPoseidon(pk, msg, sigNotReduced))
+	// R8 not on the curve
+	sigWrongR8 := Signature{R8: [2]*big.Int{sig.R8[0], big.NewInt(int64(1))}, S: sig.S}
+	assert.False(t, bj.VerifyPoseidon(pk, msg, sigWrongR8))
+
+	_, err = bj.SignPoseidon(k, bj.F.Q)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,218 @@
+// BLS signatures over BN128 (https://datatracker.ietf.org/doc/draft-irtf-cfrg-bls-signature/), with the signatures in
+// G1 and the public keys in G2, and the proof of possession scheme to aggregate the signatures of the same message
+
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+var (
+	// DST is the domain separation tag of the hash to G1 of the signed messages
+	DST = []byte("BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_POP_")
+	// DSTPop is the domain separation tag of the hash to G1 of the public keys in the proofs of possession
+	DSTPop = []byte("BLS_POP_BN254G1_XMD:SHA-256_SVDW_RO_POP_")
+)
+
+// PrivateKey is the secret scalar x of a BLS key pair
+type PrivateKey struct {
+	X *big.Int
+}
+
+// PublicKey is the point x G2
+type PublicKey [3][2]*big.Int
+
+// Signature is the point x H(msg) of G1
+type Signature [3]*big.Int
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+}
+
+// Utils is the data structure holding the BN128 and the FqR Finite Field over R used by the signatures
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	return utils{
+		Bn:  bn,
+		FqR: fields.NewFq(bn.R),
+	}
+}
+
+// NewRandPrivateKey generates a new random PrivateKey
+func NewRandPrivateKey() (PrivateKey, error) {
+	x, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	return PrivateKey{X: x}, nil
+}
+
+// PublicKey returns the PublicKey x G2 of the PrivateKey
+func (k PrivateKey) PublicKey() PublicKey {
+	return PublicKey(Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, k.X)))
+}
+
+// sign returns x H(msg), hashing the message with the domain separation tag dst
+func (k PrivateKey) sign(msg, dst []byte) (Signature, error) {
+	h, err := Utils.Bn.HashToG1(msg, dst)
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature(affineG1(Utils.Bn.G1.MulScalarConstantTime(h, k.X))), nil
+}
+
+// Sign signs the message with the PrivateKey
+func (k PrivateKey) Sign(msg []byte) (Signature, error) {
+	return k.sign(msg, DST)
+}
+
+// Bytes returns the affine coordinates x0, x1, y0, y1 of the PublicKey, with 32 bytes each
+func (pk PublicKey) Bytes() []byte {
+	a := Utils.Bn.G2.Affine(pk)
+	var b []byte
+	for _, v := range []*big.Int{a[0][0], a[0][1], a[1][0], a[1][1]} {
+		var vb [32]byte
+		b = append(b, v.FillBytes(vb[:])...)
+	}
+	return b
+}
+
+// ProvePossession returns the proof of possession of the PrivateKey of the PublicKey, which is the signature of the
+// PublicKey with the domain separation tag DSTPop
+func (k PrivateKey) ProvePossession() (Signature, error) {
+	return k.sign(k.PublicKey().Bytes(), DSTPop)
+}
+
+// Validate checks that the PublicKey is a point of G2 other than the point at infinity
+func (pk PublicKey) Validate() error {
+	if !Utils.Bn.G2.IsOnCurve(pk) {
+		return errors.New("bls: public key not on the curve")
+	}
+	if Utils.Bn.G2.IsZero(pk) {
+		return errors.New("bls: public key is the point at infinity")
+	}
+	if !Utils.Bn.G2.IsInSubgroup(pk) {
+		return errors.New("bls: public key not in the subgroup of order r")
+	}
+	return nil
+}
+
+// Validate checks that the Signature is a point of G1
+func (sig Signature) Validate() error {
+	if !Utils.Bn.G1.IsOnCurve(sig) {
+		return errors.New("bls: signature not on the curve")
+	}
+	return nil
+}
+
+// verify checks e(sig, G2) == Π e(H(msgs[i]), pks[i]) with a single multi pairing
+func verify(pks []PublicKey, msgs [][]byte, sig Signature, dst []byte) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) || sig.Validate() != nil {
+		return false
+	}
+	g1s := [][3]*big.Int{Utils.Bn.G1.Neg(sig)}
+	g2s := [][3][2]*big.Int{Utils.Bn.G2.G}
+	for i := range pks {
+		if pks[i].Validate() != nil {
+			return false
+		}
+		h, err := Utils.Bn.HashToG1(msgs[i], dst)
+		if err != nil {
+			return false
+		}
+		g1s = append(g1s, h)
+		g2s = append(g2s, pks[i])
+	}
+	return Utils.Bn.PairingCheck(g1s, g2s)
+}
+
+// Verify verifies the Signature of the message for the PublicKey, checking e(sig, G2) == e(H(msg), pk)
+func Verify(pk PublicKey, msg []byte, sig Signature) bool {
+	return verify([]PublicKey{pk}, [][]byte{msg}, sig, DST)
+}
+
+// VerifyPossession verifies the proof of possession of the PublicKey
+func VerifyPossession(pk PublicKey, proof Signature) bool {
+	return verify([]PublicKey{pk}, [][]byte{pk.Bytes()}, proof, DSTPop)
+}
+
+// Aggregate returns the aggregated Signature, the sum of the signatures
+func Aggregate(sigs []Signature) (Signature, error) {
+	if len(sigs) == 0 {
+		return Signature{}, errors.New("bls: no signatures to aggregate")
+	}
+	res := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for _, sig := range sigs {
+		if err := sig.Validate(); err != nil {
+			return Signature{}, err
+		}
+		if Utils.Bn.G1.Equal(res, sig) {
+			res = Utils.Bn.G1.Double(res)
+		} else {
+			res = Utils.Bn.G1.Add(res, sig)
+		}
+	}
+	return Signature(affineG1(res)), nil
+}
+
+// AggregatePublicKeys returns the aggregated PublicKey, the sum of the public keys
+func AggregatePublicKeys(pks []PublicKey) (PublicKey, error) {
+	if len(pks) == 0 {
+		return PublicKey{}, errors.New("bls: no public keys to aggregate")
+	}
+	res := Utils.Bn.G2.Zero()
+	for _, pk := range pks {
+		if err := pk.Validate(); err != nil {
+			return PublicKey{}, err
+		}
+		if Utils.Bn.G2.Equal(res, pk) {
+			res = Utils.Bn.G2.Double(res)
+		} else {
+			res = Utils.Bn.G2.Add(res, pk)
+		}
+	}
+	return PublicKey(Utils.Bn.G2.Affine(res)), nil
+}
+
+// AggregateVerify verifies the aggregated Signature of the messages, each one signed by the PublicKey of the same
+// index, checking e(sig, G2) == Π e(H(msgs[i]), pks[i]) with a single multi pairing. Without proofs of possession of
+// the public keys, the messages must be distinct, so it returns false if any of them is repeated.
+func AggregateVerify(pks []PublicKey, msgs [][]byte, sig Signature) bool {
+	seen := make(map[string]bool)
+	for _, msg := range msgs {
+		if seen[string(msg)] {
+			return false
+		}
+		seen[string(msg)] = true
+	}
+	return verify(pks, msgs, sig, DST)
+}
+
+// FastAggregateVerify verifies the aggregated Signature of the same message signed by all the public keys, checking
+// e(sig, G2) == e(H(msg), Σ pks[i]). It is only secure against rogue key attacks when the proof of possession of each
+// PublicKey has been checked with VerifyPossession.
+func FastAggregateVerify(pks []PublicKey, msg []byte, sig Signature) bool {
+	apk, err := AggregatePublicKeys(pks)
+	if err != nil {
+		return false
+	}
+	return verify([]PublicKey{apk}, [][]byte{msg}, sig, DST)
+}
+
+func affineG1(p [3]*big.Int) [3]*big.Int {
+	if Utils.Bn.G1.IsZero(p) {
+		return p
+	}
+	a := Utils.Bn.G1.Affine(p)
+	return [3]*big.Int{a[0], a[1], Utils.Bn.G1.F.One()}
+}
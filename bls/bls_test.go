@@ -0,0 +1,163 @@
+package bls
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	k, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	pk := k.PublicKey()
+	assert.Nil(t, pk.Validate())
+
+	msg := []byte("message")
+	sig, err := k.Sign(msg)
+	assert.Nil(t, err)
+	assert.Nil(t, sig.Validate())
+	assert.True(t, Verify(pk, msg, sig))
+
+	// the signature is deterministic
+	sig2, err := k.Sign(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, sig, sig2)
+
+	// another message
+	assert.False(t, Verify(pk, []byte("another message"), sig))
+	// another public key
+	k2, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	assert.False(t, Verify(k2.PublicKey(), msg, sig))
+	// signature not on the curve
+	sigWrong := Signature{sig[0], big.NewInt(int64(1)), sig[2]}
+	assert.False(t, Verify(pk, msg, sigWrong))
+	// public key not on the curve
+	pkWrong := pk
+	pkWrong[1] = [2]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(1))}
+	assert.NotNil(t, pkWrong.Validate())
+	assert.False(t, Verify(pkWrong, msg, sig))
+	// public key and signature at infinity
+	pkZero := PublicKey(Utils.Bn.G2.Zero())
+	assert.NotNil(t, pkZero.Validate())
+	sigZero := Signature{big.NewInt(int64(0)), big.NewInt(int64(1)), big.NewInt(int64(0))}
+	assert.False(t, Verify(pkZero, msg, sigZero))
+}
+
+func TestProvePossession(t *testing.T) {
+	k, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	pk := k.PublicKey()
+	proof, err := k.ProvePossession()
+	assert.Nil(t, err)
+	assert.True(t, VerifyPossession(pk, proof))
+
+	// proof of another key
+	k2, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	proof2, err := k2.ProvePossession()
+	assert.Nil(t, err)
+	assert.False(t, VerifyPossession(pk, proof2))
+
+	// a signature of the public key with the DST of the messages is not a proof of possession, and the proof of
+	// possession is not a signature of the public key
+	sig, err := k.Sign(pk.Bytes())
+	assert.Nil(t, err)
+	assert.False(t, VerifyPossession(pk, sig))
+	assert.False(t, Verify(pk, pk.Bytes(), proof))
+}
+
+func TestAggregateVerify(t *testing.T) {
+	var pks []PublicKey
+	var msgs [][]byte
+	var sigs []Signature
+	for i := 0; i < 4; i++ {
+		k, err := NewRandPrivateKey()
+		assert.Nil(t, err)
+		msg := []byte("message " + strconv.Itoa(i))
+		sig, err := k.Sign(msg)
+		assert.Nil(t, err)
+		pks = append(pks, k.PublicKey())
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+	aggSig, err := Aggregate(sigs)
+	assert.Nil(t, err)
+	assert.True(t, AggregateVerify(pks, msgs, aggSig))
+
+	// messages in another order
+	assert.False(t, AggregateVerify(pks, [][]byte{msgs[1], msgs[0], msgs[2], msgs[3]}, aggSig))
+	// missing signature
+	aggSig3, err := Aggregate(sigs[:3])
+	assert.Nil(t, err)
+	assert.False(t, AggregateVerify(pks, msgs, aggSig3))
+	assert.True(t, AggregateVerify(pks[:3], msgs[:3], aggSig3))
+	// different number of public keys and messages
+	assert.False(t, AggregateVerify(pks[:3], msgs, aggSig))
+	// repeated messages
+	assert.False(t, AggregateVerify(pks[:2], [][]byte{msgs[0], msgs[0]}, aggSig))
+
+	_, err = Aggregate(nil)
+	assert.NotNil(t, err)
+	_, err = Aggregate([]Signature{sigs[0], {sigs[1][0], big.NewInt(int64(1)), sigs[1][2]}})
+	assert.NotNil(t, err)
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	msg := []byte("message")
+	var pks []PublicKey
+	var sigs []Signature
+	for i := 0; i < 4; i++ {
+		k, err := NewRandPrivateKey()
+		assert.Nil(t, err)
+		proof, err := k.ProvePossession()
+		assert.Nil(t, err)
+		assert.True(t, VerifyPossession(k.PublicKey(), proof))
+		sig, err := k.Sign(msg)
+		assert.Nil(t, err)
+		pks = append(pks, k.PublicKey())
+		sigs = append(sigs, sig)
+	}
+	aggSig, err := Aggregate(sigs)
+	assert.Nil(t, err)
+	assert.True(t, FastAggregateVerify(pks, msg, aggSig))
+	assert.False(t, FastAggregateVerify(pks, []byte("another message"), aggSig))
+	assert.False(t, FastAggregateVerify(pks[:3], msg, aggSig))
+	assert.False(t, FastAggregateVerify(nil, msg, aggSig))
+
+	// the aggregated public key verifies the aggregated signature
+	apk, err := AggregatePublicKeys(pks)
+	assert.Nil(t, err)
+	assert.True(t, Verify(apk, msg, aggSig))
+
+	// the same key signing twice
+	aggSig2, err := Aggregate([]Signature{sigs[0], sigs[0]})
+	assert.Nil(t, err)
+	assert.True(t, FastAggregateVerify([]PublicKey{pks[0], pks[0]}, msg, aggSig2))
+}
+
+func TestRogueKeyAttack(t *testing.T) {
+	msg := []byte("message")
+	victim, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	victimPk := victim.PublicKey()
+
+	// the attacker publishes pk' = x' G2 - pk, so that the aggregated public key is x' G2, and signs alone for both
+	attacker, err := NewRandPrivateKey()
+	assert.Nil(t, err)
+	roguePk := PublicKey(Utils.Bn.G2.Affine(Utils.Bn.G2.Sub(attacker.PublicKey(), victimPk)))
+	assert.Nil(t, roguePk.Validate())
+	sig, err := attacker.Sign(msg)
+	assert.Nil(t, err)
+	assert.True(t, FastAggregateVerify([]PublicKey{victimPk, roguePk}, msg, sig))
+
+	// but the attacker does not know the private key of pk', so it can not prove its possession
+	proof, err := attacker.ProvePossession()
+	assert.Nil(t, err)
+	assert.False(t, VerifyPossession(roguePk, proof))
+	forged, err := attacker.sign(roguePk.Bytes(), DSTPop)
+	assert.Nil(t, err)
+	assert.False(t, VerifyPossession(roguePk, forged))
+}
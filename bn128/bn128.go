@@ -23,15 +23,20 @@ type Bn128 struct {
 	G2            G2
 	LoopCount     *big.Int
 	LoopCountNeg  bool
-
-	TwoInv             *big.Int
-	CoefB              *big.Int
-	TwistCoefB         [2]*big.Int
-	Twist              [2]*big.Int
-	FrobeniusCoeffsC11 *big.Int
-	TwistMulByQX       [2]*big.Int
-	TwistMulByQY       [2]*big.Int
-	FinalExp           *big.Int
+	U             *big.Int // BN parameter of the curve, where LoopCount = 6u + 2
+
+	TwoInv              *big.Int
+	CoefB               *big.Int
+	TwistCoefB          [2]*big.Int
+	Twist               [2]*big.Int
+	FrobeniusCoeffsC11  *big.Int
+	FrobeniusCoeffsFq12 [6][2]*big.Int
+	TwistMulByQX        [2]*big.Int
+	TwistMulByQY        [2]*big.Int
+	FinalExp            *big.Int
+
+	svdwG1 svdwG1
+	svdwG2 svdwG2
 }
 
 // NewBn128 returns the BN128
@@ -103,6 +108,20 @@ func NewBn128() (Bn128, error) {
 	if err != nil {
 		return b, err
 	}
+	b.G1.coefB = b.CoefB
+	b.G2.coefB = b.TwistCoefB
+	b.G1.order = b.R
+	b.G2.order = b.R
+
+	err = b.prepareEndomorphisms()
+	if err != nil {
+		return b, err
+	}
+
+	err = b.prepareHashToCurve()
+	if err != nil {
+		return b, err
+	}
 
 	return b, nil
 }
@@ -126,6 +145,11 @@ func (bn128 *Bn128) preparePairing() error {
 
 	bn128.LoopCountNeg = false
 
+	bn128.U, ok = new(big.Int).SetString("4965661367192848881", 10)
+	if !ok {
+		return errors.New("err with U from string")
+	}
+
 	bn128.TwoInv = bn128.Fq1.Inverse(big.NewInt(int64(2)))
 
 	bn128.CoefB = big.NewInt(int64(3))
@@ -140,6 +164,8 @@ func (bn128 *Bn128) preparePairing() error {
 		return errors.New("error parsing frobeniusCoeffsC11")
 	}
 
+	bn128.FrobeniusCoeffsFq12 = bn128.Fq12.FrobeniusCoeffs()
+
 	a, ok := new(big.Int).SetString("21575463638280843010398324269430826099269044274347216827212613867836435027261", 10)
 	if !ok {
 		return errors.New("error parsing a")
@@ -177,20 +203,41 @@ func (bn128 *Bn128) preparePairing() error {
 
 // Pairing calculates the BN128 Pairing of two given values
 func (bn128 Bn128) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
-	pre1 := bn128.preComputeG1(p1)
-	pre2 := bn128.preComputeG2(p2)
+	pre1 := bn128.PreComputeG1(p1)
+	pre2 := bn128.PreComputeG2(p2)
 
 	r1 := bn128.MillerLoop(pre1, pre2)
-	res := bn128.finalExponentiation(r1)
+	res := bn128.FinalExponentiation(r1)
 	return res
 }
 
+// PairingCheck returns true if the product of the pairings of the pairs (g1s[i], g2s[i]) is one, accumulating all the
+// Miller loops in the same value with a single final exponentiation
+func (bn128 Bn128) PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool {
+	if len(g1s) != len(g2s) {
+		return false
+	}
+	res := bn128.FinalExponentiation(bn128.MillerLoopMulti(g1s, g2s))
+	return bn128.Fq12.Equal(res, bn128.Fq12.One())
+}
+
+// PairingCheckPrecomp is PairingCheck with the G2 points already precomputed with PreComputeG2
+func (bn128 Bn128) PairingCheckPrecomp(g1s [][3]*big.Int, pre2s []AteG2Precomp) bool {
+	if len(g1s) != len(pre2s) {
+		return false
+	}
+	res := bn128.FinalExponentiation(bn128.MillerLoopMultiPrecomp(g1s, pre2s))
+	return bn128.Fq12.Equal(res, bn128.Fq12.One())
+}
+
+// AteG1Precomp holds the affine coordinates of a G1 point, as used in the Miller loop
 type AteG1Precomp struct {
 	Px *big.Int
 	Py *big.Int
 }
 
-func (bn128 Bn128) preComputeG1(p [3]*big.Int) AteG1Precomp {
+// PreComputeG1 returns the AteG1Precomp of the G1 point
+func (bn128 Bn128) PreComputeG1(p [3]*big.Int) AteG1Precomp {
 	pCopy := bn128.G1.Affine(p)
 	res := AteG1Precomp{
 		Px: pCopy[0],
@@ -199,24 +246,33 @@ func (bn128 Bn128) preComputeG1(p [3]*big.Int) AteG1Precomp {
 	return res
 }
 
+// EllCoeffs are the coefficients of a line of the Miller loop, which only depend on the G2 point
 type EllCoeffs struct {
 	Ell0  [2]*big.Int
 	EllVW [2]*big.Int
 	EllVV [2]*big.Int
 }
+
+// AteG2Precomp holds the affine coordinates of a G2 point and the coefficients of all the lines of its Miller loop, so
+// the pairings with a fixed G2 point can skip its doubling and addition steps
 type AteG2Precomp struct {
 	Qx     [2]*big.Int
 	Qy     [2]*big.Int
 	Coeffs []EllCoeffs
 }
 
-func (bn128 Bn128) preComputeG2(p [3][2]*big.Int) AteG2Precomp {
+// PreComputeG2 returns the AteG2Precomp of the G2 point, which can be computed once and reused in all the pairings with
+// the point. For the point at infinity there are no lines, and its pairings are skipped by MillerLoopMultiPrecomp.
+func (bn128 Bn128) PreComputeG2(p [3][2]*big.Int) AteG2Precomp {
 	qCopy := bn128.G2.Affine(p)
 	res := AteG2Precomp{
 		qCopy[0],
 		qCopy[1],
 		[]EllCoeffs{},
 	}
+	if bn128.G2.IsZero(p) {
+		return res
+	}
 	r := [3][2]*big.Int{
 		bn128.Fq2.Copy(qCopy[0]),
 		bn128.Fq2.Copy(qCopy[1]),
@@ -345,6 +401,7 @@ func (bn128 Bn128) g2MulByQ(p [3][2]*big.Int) [3][2]*big.Int {
 	}
 }
 
+// MillerLoop computes the Miller loop of the pair of precomputed points, which then needs the FinalExponentiation
 func (bn128 Bn128) MillerLoop(pre1 AteG1Precomp, pre2 AteG2Precomp) [2][3][2]*big.Int {
 	// https://cryptojedi.org/papers/dclxvi-20100714.pdf
 	// https://eprint.iacr.org/2008/096.pdf
@@ -399,6 +456,62 @@ func (bn128 Bn128) MillerLoop(pre1 AteG1Precomp, pre2 AteG2Precomp) [2][3][2]*bi
 	return f
 }
 
+// MillerLoopMulti returns the product of the Miller loops of the pairs (g1s[i], g2s[i]), accumulating all the lines in the
+// same Fq12 value so the squarings are shared. The pairs with a point at infinity are skipped, as their pairing is one.
+func (bn128 Bn128) MillerLoopMulti(g1s [][3]*big.Int, g2s [][3][2]*big.Int) [2][3][2]*big.Int {
+	var pre2s []AteG2Precomp
+	for i := 0; i < len(g1s) && i < len(g2s); i++ {
+		if bn128.G1.IsZero(g1s[i]) {
+			// the lines of the pair are not needed
+			pre2s = append(pre2s, AteG2Precomp{})
+			continue
+		}
+		pre2s = append(pre2s, bn128.PreComputeG2(g2s[i]))
+	}
+	return bn128.MillerLoopMultiPrecomp(g1s, pre2s)
+}
+
+// MillerLoopMultiPrecomp is MillerLoopMulti with the G2 points already precomputed with PreComputeG2
+func (bn128 Bn128) MillerLoopMultiPrecomp(g1s [][3]*big.Int, pre2s []AteG2Precomp) [2][3][2]*big.Int {
+	var pre1s []AteG1Precomp
+	var lines []AteG2Precomp
+	for i := 0; i < len(g1s) && i < len(pre2s); i++ {
+		if bn128.G1.IsZero(g1s[i]) || len(pre2s[i].Coeffs) == 0 {
+			continue
+		}
+		pre1s = append(pre1s, bn128.PreComputeG1(g1s[i]))
+		lines = append(lines, pre2s[i])
+	}
+
+	idx := 0
+	f := bn128.Fq12.One()
+	mulLines := func() {
+		for j := range pre1s {
+			c := lines[j].Coeffs[idx]
+			f = bn128.mulBy024(
+				f,
+				c.Ell0,
+				bn128.Fq2.MulScalar(c.EllVW, pre1s[j].Py),
+				bn128.Fq2.MulScalar(c.EllVV, pre1s[j].Px))
+		}
+		idx++
+	}
+
+	for i := bn128.LoopCount.BitLen() - 2; i >= 0; i-- {
+		f = bn128.Fq12.Square(f)
+		mulLines()
+		if bn128.LoopCount.Bit(i) == 1 {
+			mulLines()
+		}
+	}
+	if bn128.LoopCountNeg {
+		f = bn128.Fq12.Inverse(f)
+	}
+	mulLines()
+	mulLines()
+	return f
+}
+
 func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int) [2][3][2]*big.Int {
 	b := [2][3][2]*big.Int{
 		[3][2]*big.Int{
@@ -415,7 +528,55 @@ func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int)
 	return bn128.Fq12.Mul(a, b)
 }
 
-func (bn128 Bn128) finalExponentiation(r [2][3][2]*big.Int) [2][3][2]*big.Int {
-	res := bn128.Fq12.Exp(r, bn128.FinalExp)
+// FinalExponentiation raises the result of the Miller loop to (q^12 - 1) / r, returning the value of the pairing
+func (bn128 Bn128) FinalExponentiation(r [2][3][2]*big.Int) [2][3][2]*big.Int {
+	// easy part, r^((q^6 - 1)(q^2 + 1)), whose result is in the cyclotomic subgroup
+	t1 := bn128.Fq12.Mul(bn128.Fq12.Conjugate(r), bn128.Fq12.Inverse(r))
+	t1 = bn128.Fq12.Mul(bn128.Fq12.Frobenius(t1, 2, bn128.FrobeniusCoeffsFq12), t1)
+
+	// hard part, t1^((q^4 - q^2 + 1) / r), with the addition chain of the exponent written in base q with coefficients
+	// that are polynomials of u, from https://eprint.iacr.org/2008/490.pdf
+	fp := bn128.Fq12.Frobenius(t1, 1, bn128.FrobeniusCoeffsFq12)
+	fp2 := bn128.Fq12.Frobenius(t1, 2, bn128.FrobeniusCoeffsFq12)
+	fp3 := bn128.Fq12.Frobenius(fp2, 1, bn128.FrobeniusCoeffsFq12)
+
+	fu := bn128.cyclotomicExp(t1, bn128.U)
+	fu2 := bn128.cyclotomicExp(fu, bn128.U)
+	fu3 := bn128.cyclotomicExp(fu2, bn128.U)
+
+	y3 := bn128.Fq12.Frobenius(fu, 1, bn128.FrobeniusCoeffsFq12)
+	fu2p := bn128.Fq12.Frobenius(fu2, 1, bn128.FrobeniusCoeffsFq12)
+	fu3p := bn128.Fq12.Frobenius(fu3, 1, bn128.FrobeniusCoeffsFq12)
+	y2 := bn128.Fq12.Frobenius(fu2, 2, bn128.FrobeniusCoeffsFq12)
+
+	y0 := bn128.Fq12.Mul(bn128.Fq12.Mul(fp, fp2), fp3)
+	y1 := bn128.Fq12.Conjugate(t1)
+	y5 := bn128.Fq12.Conjugate(fu2)
+	y3 = bn128.Fq12.Conjugate(y3)
+	y4 := bn128.Fq12.Conjugate(bn128.Fq12.Mul(fu, fu2p))
+	y6 := bn128.Fq12.Conjugate(bn128.Fq12.Mul(fu3, fu3p))
+
+	t0 := bn128.Fq12.CyclotomicSquare(y6)
+	t0 = bn128.Fq12.Mul(bn128.Fq12.Mul(t0, y4), y5)
+	t1 = bn128.Fq12.Mul(bn128.Fq12.Mul(y3, y5), t0)
+	t0 = bn128.Fq12.Mul(t0, y2)
+	t1 = bn128.Fq12.CyclotomicSquare(t1)
+	t1 = bn128.Fq12.Mul(t1, t0)
+	t1 = bn128.Fq12.CyclotomicSquare(t1)
+	t0 = bn128.Fq12.Mul(t1, y1)
+	t1 = bn128.Fq12.Mul(t1, y0)
+	t0 = bn128.Fq12.CyclotomicSquare(t0)
+	return bn128.Fq12.Mul(t0, t1)
+}
+
+// cyclotomicExp returns a^e for an element a of the cyclotomic subgroup, using the cyclotomic squaring
+func (bn128 Bn128) cyclotomicExp(a [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
+	res := bn128.Fq12.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = bn128.Fq12.CyclotomicSquare(res)
+		if e.Bit(i) == 1 {
+			res = bn128.Fq12.Mul(res, a)
+		}
+	}
 	return res
 }
@@ -21,11 +21,11 @@ func TestBN128(t *testing.T) {
 	g1b := bn128.G1.MulScalar(bn128.G1.G, bn128.Fq1.Copy(big75))
 	g2b := bn128.G2.MulScalar(bn128.G2.G, bn128.Fq1.Copy(big40))
 
-	pre1a := bn128.preComputeG1(g1a)
-	pre2a := bn128.preComputeG2(g2a)
+	pre1a := bn128.PreComputeG1(g1a)
+	pre2a := bn128.PreComputeG2(g2a)
 	assert.Nil(t, err)
-	pre1b := bn128.preComputeG1(g1b)
-	pre2b := bn128.preComputeG2(g2b)
+	pre1b := bn128.PreComputeG1(g1b)
+	pre2b := bn128.PreComputeG2(g2b)
 	assert.Nil(t, err)
 
 	r1 := bn128.MillerLoop(pre1a, pre2a)
@@ -33,7 +33,7 @@ func TestBN128(t *testing.T) {
 
 	rbe := bn128.Fq12.Mul(r1, bn128.Fq12.Inverse(r2))
 
-	res := bn128.finalExponentiation(rbe)
+	res := bn128.FinalExponentiation(rbe)
 
 	a := bn128.Fq12.Affine(res)
 	b := bn128.Fq12.Affine(bn128.Fq12.One())
@@ -86,3 +86,86 @@ func TestBN128Pairing2(t *testing.T) {
 	assert.True(t, bn.Fq12.Equal(gt6, bn.Pairing(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(2))), bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))))))
 
 }
+
+func TestBN128MillerLoopMulti(t *testing.T) {
+	bn, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1s := [][3]*big.Int{
+		bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(3))),
+		bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(5))),
+		{bn.G1.F.Zero(), bn.G1.F.One(), bn.G1.F.Zero()},
+	}
+	g2s := [][3][2]*big.Int{
+		bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(7))),
+		bn.G2.G,
+		bn.G2.G,
+	}
+	// e(3 g1, 7 g2) * e(5 g1, g2) * e(0, g2) == e(g1, g2)^26
+	res := bn.FinalExponentiation(bn.MillerLoopMulti(g1s, g2s))
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.Mul(bn.Pairing(g1s[0], g2s[0]), bn.Pairing(g1s[1], g2s[1])), res))
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.Exp(bn.Pairing(bn.G1.G, bn.G2.G), big.NewInt(int64(26))), res))
+
+	// e(3 g1, 7 g2) * e(-21 g1, g2) == 1
+	g1s[1] = bn.G1.Neg(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(21))))
+	res = bn.FinalExponentiation(bn.MillerLoopMulti(g1s, g2s))
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.One(), res))
+}
+
+func TestBN128PairingCheck(t *testing.T) {
+	bn, err := NewBn128()
+	assert.Nil(t, err)
+
+	// e(6 g1, g2) * e(-2 g1, 3 g2) == 1
+	g1s := [][3]*big.Int{
+		bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(6))),
+		bn.G1.Neg(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(2)))),
+	}
+	g2s := [][3][2]*big.Int{
+		bn.G2.G,
+		bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))),
+	}
+	assert.True(t, bn.PairingCheck(g1s, g2s))
+
+	g2s[1] = bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(4)))
+	assert.False(t, bn.PairingCheck(g1s, g2s))
+	assert.False(t, bn.PairingCheck(g1s, g2s[:1]))
+	assert.True(t, bn.PairingCheck(nil, nil))
+}
+
+func TestBN128PairingCheckPrecomp(t *testing.T) {
+	bn, err := NewBn128()
+	assert.Nil(t, err)
+
+	// the precomputed G2 points are reused in several checks
+	g2a := bn.PreComputeG2(bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))))
+	g2b := bn.PreComputeG2(bn.G2.G)
+	for _, x := range []int64{2, 5} {
+		// e(x g1, 3 g2) * e(-3x g1, g2) == 1
+		g1s := [][3]*big.Int{
+			bn.G1.MulScalar(bn.G1.G, big.NewInt(x)),
+			bn.G1.Neg(bn.G1.MulScalar(bn.G1.G, big.NewInt(3*x))),
+		}
+		assert.True(t, bn.PairingCheckPrecomp(g1s, []AteG2Precomp{g2a, g2b}))
+		assert.False(t, bn.PairingCheckPrecomp(g1s, []AteG2Precomp{g2b, g2a}))
+		assert.True(t, bn.Fq12.Equal(
+			bn.MillerLoopMulti(g1s, [][3][2]*big.Int{bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))), bn.G2.G}),
+			bn.MillerLoopMultiPrecomp(g1s, []AteG2Precomp{g2a, g2b})))
+	}
+
+	// the pairings with the point at infinity are one
+	zero := bn.PreComputeG2(bn.G2.Zero())
+	assert.Equal(t, 0, len(zero.Coeffs))
+	assert.True(t, bn.PairingCheckPrecomp([][3]*big.Int{bn.G1.G}, []AteG2Precomp{zero}))
+}
+
+func TestBN128FinalExponentiation(t *testing.T) {
+	bn, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1 := bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(5)))
+	g2 := bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(7)))
+	m := bn.MillerLoop(bn.PreComputeG1(g1), bn.PreComputeG2(g2))
+	// same result than the exponentiation to (q^12 - 1) / r
+	assert.True(t, bn.Fq12.Equal(bn.Fq12.Exp(m, bn.FinalExp), bn.FinalExponentiation(m)))
+}
@@ -0,0 +1,19 @@
+package bn128
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// ladderScalar returns the scalar k = e mod order + order or k = e mod order + 2 order, chosen without branches so
+// that the bit order.BitLen() is always the most significant bit of k. For the points of order r, k P = e P, and the
+// Montgomery ladder over k runs the same sequence of operations for any secret scalar e.
+func ladderScalar(e, order *big.Int) *big.Int {
+	size := (order.BitLen() + 2 + 7) / 8
+	k1 := new(big.Int).Mod(e, order)
+	k1.Add(k1, order)
+	k2 := new(big.Int).Add(k1, order)
+	k, _ := fields.CondSwap(k2, k1, k1.Bit(order.BitLen()), size)
+	return k
+}
@@ -9,6 +9,11 @@ import (
 type G1 struct {
 	F fields.Fq
 	G [3]*big.Int
+
+	coefB *big.Int
+	order *big.Int
+	beta  *big.Int
+	glv   *endomorphism
 }
 
 func NewG1(f fields.Fq, g [2]*big.Int) G1 {
@@ -29,6 +34,25 @@ func (g1 G1) IsZero(p [3]*big.Int) bool {
 	return g1.F.IsZero(p[2])
 }
 
+// IsOnCurve returns true if p is the point at infinity or a point of the curve y² = x³ + 3, with its Jacobian
+// coordinates inside Fq. As the order of the curve is r, all its points are in G1.
+func (g1 G1) IsOnCurve(p [3]*big.Int) bool {
+	for _, c := range p {
+		if c == nil || c.Sign() < 0 || c.Cmp(g1.F.Q) >= 0 {
+			return false
+		}
+	}
+	if g1.IsZero(p) {
+		return true
+	}
+	// Y² = X³ + b Z⁶
+	z2 := g1.F.Square(p[2])
+	z6 := g1.F.Mul(g1.F.Square(z2), z2)
+	y2 := g1.F.Square(p[1])
+	x3 := g1.F.Mul(g1.F.Square(p[0]), p[0])
+	return g1.F.Equal(y2, g1.F.Add(x3, g1.F.Mul(g1.coefB, z6)))
+}
+
 func (g1 G1) Add(p1, p2 [3]*big.Int) [3]*big.Int {
 
 	// https://en.wikibooks.org/wiki/Cryptography/Prime_Curve/Jacobian_Coordinates
@@ -154,6 +178,79 @@ func (g1 G1) MulScalar(p [3]*big.Int, e *big.Int) [3]*big.Int {
 	return q
 }
 
+// MulScalarGLV returns e p as MulScalar, using the GLV endomorphism φ(x, y) = (β x, y) to split e in two scalars of
+// half the size, which are multiplied at once with a joint double-and-add
+func (g1 G1) MulScalarGLV(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	if g1.glv == nil {
+		return g1.MulScalar(p, e)
+	}
+	k1, k2 := g1.glv.decompose(new(big.Int).Mod(e, g1.glv.r))
+
+	p1 := p
+	p2 := [3]*big.Int{g1.F.Mul(g1.beta, p[0]), p[1], p[2]}
+	if k1.Sign() < 0 {
+		k1.Neg(k1)
+		p1 = g1.Neg(p1)
+	}
+	if k2.Sign() < 0 {
+		k2.Neg(k2)
+		p2 = g1.Neg(p2)
+	}
+	p12 := g1.Add(p1, p2)
+
+	q := [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}
+	n := k1.BitLen()
+	if k2.BitLen() > n {
+		n = k2.BitLen()
+	}
+	for i := n - 1; i >= 0; i-- {
+		q = g1.Double(q)
+		switch {
+		case k1.Bit(i) == 1 && k2.Bit(i) == 1:
+			q = g1.Add(q, p12)
+		case k1.Bit(i) == 1:
+			q = g1.Add(q, p1)
+		case k2.Bit(i) == 1:
+			q = g1.Add(q, p2)
+		}
+	}
+	return q
+}
+
+// MulScalarConstantTime returns e p as MulScalar for secret scalars, with a Montgomery ladder over a scalar of fixed
+// bit length and swaps of the points without branches, so that the sequence of operations does not depend on e.
+// The arithmetic of math/big is not constant time, so this removes the branches on the bits of the scalar, but not
+// all the timing differences. The coordinates of p are reduced mod Q before the ladder, as the swaps need them inside
+// Fq.
+func (g1 G1) MulScalarConstantTime(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	if g1.order == nil {
+		return g1.MulScalar(p, e)
+	}
+	k := ladderScalar(e, g1.order)
+	p = [3]*big.Int{g1.F.Affine(p[0]), g1.F.Affine(p[1]), g1.F.Affine(p[2])}
+
+	r0 := p
+	r1 := g1.Double(p)
+	for i := g1.order.BitLen() - 1; i >= 0; i-- {
+		bit := k.Bit(i)
+		r0, r1 = g1.condSwap(r0, r1, bit)
+		r1 = g1.Add(r0, r1)
+		r0 = g1.Double(r0)
+		r0, r1 = g1.condSwap(r0, r1, bit)
+	}
+	return r0
+}
+
+// condSwap returns (p2, p1) if swap is 1 and (p1, p2) if it is 0, with the same sequence of operations for both values
+// of swap. The coordinates of the points must be inside Fq.
+func (g1 G1) condSwap(p1, p2 [3]*big.Int, swap uint) ([3]*big.Int, [3]*big.Int) {
+	size := (g1.F.Q.BitLen() + 7) / 8
+	for i := range p1 {
+		p1[i], p2[i] = fields.CondSwap(p1[i], p2[i], swap, size)
+	}
+	return p1, p2
+}
+
 func (g1 G1) Affine(p [3]*big.Int) [2]*big.Int {
 	if g1.IsZero(p) {
 		return g1.Zero()
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,3 +30,159 @@ func TestG1(t *testing.T) {
 	assert.Equal(t, "2f978c0ab89ebaa576866706b14787f360c4d6c3869efe5a72f7c3651a72ff00", hex.EncodeToString(a[0].Bytes()))
 	assert.Equal(t, "12e4ba7f0edca8b4fa668fe153aebd908d322dc26ad964d4cd314795844b62b2", hex.EncodeToString(a[1].Bytes()))
 }
+
+func testScalars(t *testing.T, bn128 Bn128) []*big.Int {
+	rMinus1 := new(big.Int).Sub(bn128.R, big.NewInt(int64(1)))
+	rPlus5 := new(big.Int).Add(bn128.R, big.NewInt(int64(5)))
+	twoR := new(big.Int).Lsh(bn128.R, 1)
+	max256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(int64(1)), 256), big.NewInt(int64(1)))
+	half := new(big.Int).Rsh(bn128.R, 1)
+	scalars := []*big.Int{
+		big.NewInt(int64(0)),
+		big.NewInt(int64(1)),
+		big.NewInt(int64(2)),
+		big.NewInt(int64(3)),
+		big.NewInt(int64(255)),
+		rMinus1,
+		bn128.R,
+		rPlus5,
+		twoR,
+		max256,
+		half,
+		bn128.G1.glv.lambda,
+		bn128.G2.gls.lambda,
+		new(big.Int).Add(bn128.G1.glv.lambda, big.NewInt(int64(1))),
+	}
+	fqR := fields.NewFq(bn128.R)
+	for i := 0; i < 30; i++ {
+		k, err := fqR.Rand()
+		assert.Nil(t, err)
+		scalars = append(scalars, k)
+	}
+	return scalars
+}
+
+func TestG1Endomorphism(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// φ(x, y) = (β x, y) acts on G1 as the multiplication by λ
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(12345)))
+	phi := [3]*big.Int{bn128.Fq1.Mul(bn128.G1.beta, p[0]), p[1], p[2]}
+	assert.True(t, bn128.G1.Equal(phi, bn128.G1.MulScalar(p, bn128.G1.glv.lambda)))
+	assert.Equal(t, big.NewInt(int64(1)), bn128.Fq1.Exp(bn128.G1.beta, big.NewInt(int64(3))))
+
+	for _, k := range testScalars(t, bn128) {
+		k1, k2 := bn128.G1.glv.decompose(new(big.Int).Mod(k, bn128.R))
+		res := new(big.Int).Add(k1, new(big.Int).Mul(k2, bn128.G1.glv.lambda))
+		assert.Equal(t, 0, new(big.Int).Mod(k, bn128.R).Cmp(res.Mod(res, bn128.R)))
+		assert.True(t, k1.BitLen() <= 128)
+		assert.True(t, k2.BitLen() <= 128)
+	}
+}
+
+func TestG1MulScalarGLV(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	points := [][3]*big.Int{
+		bn128.G1.G,
+		bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33))),
+		bn128.G1.Neg(bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(987654321)))),
+		[3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()},
+	}
+	scalars := testScalars(t, bn128)
+	for _, p := range points {
+		for _, k := range scalars {
+			assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, k), bn128.G1.MulScalarGLV(p, k)))
+		}
+	}
+
+	// random points
+	fqR := fields.NewFq(bn128.R)
+	for i := 0; i < 20; i++ {
+		s, err := fqR.Rand()
+		assert.Nil(t, err)
+		k, err := fqR.Rand()
+		assert.Nil(t, err)
+		p := bn128.G1.MulScalar(bn128.G1.G, s)
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, k), bn128.G1.MulScalarGLV(p, k)))
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(bn128.G1.G, fqR.Mul(s, k)), bn128.G1.MulScalarGLV(p, k)))
+	}
+}
+
+func TestG1MulScalarConstantTime(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	points := [][3]*big.Int{
+		bn128.G1.G,
+		bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33))),
+		[3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()},
+	}
+	for _, p := range points {
+		for _, k := range testScalars(t, bn128) {
+			assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, k), bn128.G1.MulScalarConstantTime(p, k)))
+		}
+	}
+
+	// the coordinates are reduced mod Q before the ladder, so negative coordinates and coordinates that do not fit in
+	// the bytes of Q give the same point
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33)))
+	unreduced := [3]*big.Int{
+		new(big.Int).Sub(p[0], bn128.Q),
+		new(big.Int).Add(p[1], new(big.Int).Lsh(bn128.Q, 300)),
+		new(big.Int).Sub(p[2], new(big.Int).Lsh(bn128.Q, 2)),
+	}
+	for _, k := range testScalars(t, bn128) {
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, k), bn128.G1.MulScalarConstantTime(unreduced, k)))
+	}
+
+	// the ladder runs over a scalar with the same bit length for any scalar
+	for _, k := range testScalars(t, bn128) {
+		assert.Equal(t, bn128.R.BitLen()+1, ladderScalar(k, bn128.R).BitLen())
+	}
+}
+
+func BenchmarkG1MulScalar(b *testing.B) {
+	bn128, _ := NewBn128()
+	k, _ := fields.NewFq(bn128.R).Rand()
+	for i := 0; i < b.N; i++ {
+		bn128.G1.MulScalar(bn128.G1.G, k)
+	}
+}
+
+func BenchmarkG1MulScalarConstantTime(b *testing.B) {
+	bn128, _ := NewBn128()
+	k, _ := fields.NewFq(bn128.R).Rand()
+	for i := 0; i < b.N; i++ {
+		bn128.G1.MulScalarConstantTime(bn128.G1.G, k)
+	}
+}
+
+func BenchmarkG1MulScalarGLV(b *testing.B) {
+	bn128, _ := NewBn128()
+	k, _ := fields.NewFq(bn128.R).Rand()
+	for i := 0; i < b.N; i++ {
+		bn128.G1.MulScalarGLV(bn128.G1.G, k)
+	}
+}
+
+func TestG1IsOnCurve(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.True(t, bn128.G1.IsOnCurve(bn128.G1.G))
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33)))
+	assert.True(t, bn128.G1.IsOnCurve(p))
+	assert.True(t, bn128.G1.IsOnCurve(bn128.G1.Neg(p)))
+	assert.True(t, bn128.G1.IsOnCurve([3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.One(), bn128.Fq1.Zero()}))
+
+	// point off the curve
+	assert.False(t, bn128.G1.IsOnCurve([3]*big.Int{p[0], bn128.Fq1.Add(p[1], bn128.Fq1.One()), p[2]}))
+	assert.False(t, bn128.G1.IsOnCurve([3]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(3)), big.NewInt(int64(1))}))
+	// coordinates outside Fq, or missing
+	assert.False(t, bn128.G1.IsOnCurve([3]*big.Int{new(big.Int).Add(p[0], bn128.Q), p[1], p[2]}))
+	assert.False(t, bn128.G1.IsOnCurve([3]*big.Int{big.NewInt(int64(-1)), p[1], p[2]}))
+	assert.False(t, bn128.G1.IsOnCurve([3]*big.Int{p[0], p[1], nil}))
+}
@@ -9,6 +9,12 @@ import (
 type G2 struct {
 	F fields.Fq2
 	G [3][2]*big.Int
+
+	coefB [2]*big.Int
+	order *big.Int
+	psiX  [2]*big.Int
+	psiY  [2]*big.Int
+	gls   *endomorphism
 }
 
 func NewG2(f fields.Fq2, g [2][2]*big.Int) G2 {
@@ -29,6 +35,41 @@ func (g2 G2) IsZero(p [3][2]*big.Int) bool {
 	return g2.F.IsZero(p[2])
 }
 
+// IsOnCurve returns true if p is the point at infinity or a point of the twist y² = x³ + 3 / (9 + u), with its
+// Jacobian coordinates inside Fq2. The points of G2 are the ones of the twist in the subgroup of order r, see
+// IsInSubgroup.
+func (g2 G2) IsOnCurve(p [3][2]*big.Int) bool {
+	for _, c := range p {
+		for _, ci := range c {
+			if ci == nil || ci.Sign() < 0 || ci.Cmp(g2.F.F.Q) >= 0 {
+				return false
+			}
+		}
+	}
+	if g2.IsZero(p) {
+		return true
+	}
+	// Y² = X³ + b Z⁶
+	z2 := g2.F.Square(p[2])
+	z6 := g2.F.Mul(g2.F.Square(z2), z2)
+	y2 := g2.F.Square(p[1])
+	x3 := g2.F.Mul(g2.F.Square(p[0]), p[0])
+	return g2.F.Equal(y2, g2.F.Add(x3, g2.F.Mul(g2.coefB, z6)))
+}
+
+// IsInSubgroup returns true if p is a point of the twist in the subgroup of order r, that is, in G2. Instead of
+// checking r p == 0, it checks ψ(p) == 6u² p, which holds only for the points of G2 and takes a scalar
+// multiplication of half the size (https://eprint.iacr.org/2022/352.pdf).
+func (g2 G2) IsInSubgroup(p [3][2]*big.Int) bool {
+	if !g2.IsOnCurve(p) {
+		return false
+	}
+	if g2.gls == nil {
+		return g2.IsZero(g2.MulScalar(p, g2.order))
+	}
+	return g2.Equal(g2.psi(p), g2.MulScalar(p, g2.gls.lambda))
+}
+
 func (g2 G2) Add(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
 
 	// https://en.wikibooks.org/wiki/Cryptography/Prime_Curve/Jacobian_Coordinates
@@ -180,6 +221,89 @@ func (g2 G2) MulScalar(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
 	return q
 }
 
+// psi returns the endomorphism ψ(p), which is the Frobenius map of the untwisted point, twisted back to G2
+func (g2 G2) psi(p [3][2]*big.Int) [3][2]*big.Int {
+	return [3][2]*big.Int{
+		g2.F.Mul(g2.psiX, g2.F.Conjugate(p[0])),
+		g2.F.Mul(g2.psiY, g2.F.Conjugate(p[1])),
+		g2.F.Conjugate(p[2]),
+	}
+}
+
+// MulScalarGLS returns e p as MulScalar for the points p of the subgroup of order r, where ψ acts as the
+// multiplication by 6u², using it to split e in two scalars of half the size, which are multiplied at once with a
+// joint double-and-add
+func (g2 G2) MulScalarGLS(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	if g2.gls == nil {
+		return g2.MulScalar(p, e)
+	}
+	k1, k2 := g2.gls.decompose(new(big.Int).Mod(e, g2.gls.r))
+
+	p1 := p
+	p2 := g2.psi(p)
+	if k1.Sign() < 0 {
+		k1.Neg(k1)
+		p1 = g2.Neg(p1)
+	}
+	if k2.Sign() < 0 {
+		k2.Neg(k2)
+		p2 = g2.Neg(p2)
+	}
+	p12 := g2.Add(p1, p2)
+
+	q := [3][2]*big.Int{g2.F.Zero(), g2.F.Zero(), g2.F.Zero()}
+	n := k1.BitLen()
+	if k2.BitLen() > n {
+		n = k2.BitLen()
+	}
+	for i := n - 1; i >= 0; i-- {
+		q = g2.Double(q)
+		switch {
+		case k1.Bit(i) == 1 && k2.Bit(i) == 1:
+			q = g2.Add(q, p12)
+		case k1.Bit(i) == 1:
+			q = g2.Add(q, p1)
+		case k2.Bit(i) == 1:
+			q = g2.Add(q, p2)
+		}
+	}
+	return q
+}
+
+// MulScalarConstantTime returns e p as MulScalar for secret scalars and the points p of the subgroup of order r, with
+// a Montgomery ladder over a scalar of fixed bit length and swaps of the points without branches, as the one of G1.
+// The coordinates of p are reduced mod Q before the ladder.
+func (g2 G2) MulScalarConstantTime(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	if g2.order == nil {
+		return g2.MulScalar(p, e)
+	}
+	k := ladderScalar(e, g2.order)
+	p = [3][2]*big.Int{g2.F.Affine(p[0]), g2.F.Affine(p[1]), g2.F.Affine(p[2])}
+
+	r0 := p
+	r1 := g2.Double(p)
+	for i := g2.order.BitLen() - 1; i >= 0; i-- {
+		bit := k.Bit(i)
+		r0, r1 = g2.condSwap(r0, r1, bit)
+		r1 = g2.Add(r0, r1)
+		r0 = g2.Double(r0)
+		r0, r1 = g2.condSwap(r0, r1, bit)
+	}
+	return r0
+}
+
+// condSwap returns (p2, p1) if swap is 1 and (p1, p2) if it is 0, with the same sequence of operations for both values
+// of swap. The coordinates of the points must be inside Fq2.
+func (g2 G2) condSwap(p1, p2 [3][2]*big.Int, swap uint) ([3][2]*big.Int, [3][2]*big.Int) {
+	size := (g2.F.F.Q.BitLen() + 7) / 8
+	for i := range p1 {
+		for j := range p1[i] {
+			p1[i][j], p2[i][j] = fields.CondSwap(p1[i][j], p2[i][j], swap, size)
+		}
+	}
+	return p1, p2
+}
+
 func (g2 G2) Affine(p [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p) {
 		return g2.Zero()
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,3 +23,136 @@ func TestG2(t *testing.T) {
 	grsum2 := bn128.G2.Affine(bn128.G2.MulScalar(bn128.G2.G, r1r2))
 	assert.True(t, bn128.G2.Equal(grsum1, grsum2))
 }
+
+func TestG2Endomorphism(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// ψ acts on the subgroup of order r of G2 as the multiplication by 6u², and is the same map used by the pairing
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(777)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.psi(p), bn128.G2.MulScalar(p, bn128.G2.gls.lambda)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.psi(p), bn128.g2MulByQ(p)))
+
+	for _, k := range testScalars(t, bn128) {
+		k1, k2 := bn128.G2.gls.decompose(new(big.Int).Mod(k, bn128.R))
+		res := new(big.Int).Add(k1, new(big.Int).Mul(k2, bn128.G2.gls.lambda))
+		assert.Equal(t, 0, new(big.Int).Mod(k, bn128.R).Cmp(res.Mod(res, bn128.R)))
+		assert.True(t, k1.BitLen() <= 128)
+		assert.True(t, k2.BitLen() <= 128)
+	}
+}
+
+func TestG2MulScalarGLS(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	points := [][3][2]*big.Int{
+		bn128.G2.G,
+		bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33))),
+		bn128.G2.Neg(bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(987654321)))),
+		bn128.G2.Zero(),
+	}
+	scalars := testScalars(t, bn128)
+	for _, p := range points {
+		for _, k := range scalars {
+			assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, k), bn128.G2.MulScalarGLS(p, k)))
+		}
+	}
+
+	// random points
+	fqR := fields.NewFq(bn128.R)
+	for i := 0; i < 10; i++ {
+		s, err := fqR.Rand()
+		assert.Nil(t, err)
+		k, err := fqR.Rand()
+		assert.Nil(t, err)
+		p := bn128.G2.MulScalar(bn128.G2.G, s)
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, k), bn128.G2.MulScalarGLS(p, k)))
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(bn128.G2.G, fqR.Mul(s, k)), bn128.G2.MulScalarGLS(p, k)))
+	}
+}
+
+func TestG2MulScalarConstantTime(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	points := [][3][2]*big.Int{
+		bn128.G2.G,
+		bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33))),
+		bn128.G2.Zero(),
+	}
+	for _, p := range points {
+		for _, k := range testScalars(t, bn128) {
+			assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, k), bn128.G2.MulScalarConstantTime(p, k)))
+		}
+	}
+
+	// the coordinates are reduced mod Q before the ladder
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	unreduced := [3][2]*big.Int{
+		{new(big.Int).Sub(p[0][0], bn128.Q), p[0][1]},
+		{p[1][0], new(big.Int).Add(p[1][1], new(big.Int).Lsh(bn128.Q, 300))},
+		{new(big.Int).Neg(new(big.Int).Sub(bn128.Q, p[2][0])), p[2][1]},
+	}
+	for _, k := range testScalars(t, bn128) {
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, k), bn128.G2.MulScalarConstantTime(unreduced, k)))
+	}
+}
+
+func BenchmarkG2MulScalar(b *testing.B) {
+	bn128, _ := NewBn128()
+	k, _ := fields.NewFq(bn128.R).Rand()
+	for i := 0; i < b.N; i++ {
+		bn128.G2.MulScalar(bn128.G2.G, k)
+	}
+}
+
+func BenchmarkG2MulScalarGLS(b *testing.B) {
+	bn128, _ := NewBn128()
+	k, _ := fields.NewFq(bn128.R).Rand()
+	for i := 0; i < b.N; i++ {
+		bn128.G2.MulScalarGLS(bn128.G2.G, k)
+	}
+}
+
+// nonSubgroupPoint returns a point of the twist outside G2
+func nonSubgroupPoint(bn128 Bn128) [3][2]*big.Int {
+	for i := 1; ; i++ {
+		x := [2]*big.Int{big.NewInt(int64(i)), big.NewInt(int64(0))}
+		y2 := bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.TwistCoefB)
+		if y, ok := bn128.Fq2.Sqrt(y2); ok {
+			return [3][2]*big.Int{x, y, bn128.Fq2.One()}
+		}
+	}
+}
+
+func TestG2IsInSubgroup(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.True(t, bn128.G2.IsInSubgroup(bn128.G2.G))
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	assert.True(t, bn128.G2.IsOnCurve(p))
+	assert.True(t, bn128.G2.IsInSubgroup(p))
+	assert.True(t, bn128.G2.IsInSubgroup(bn128.G2.Neg(p)))
+	assert.True(t, bn128.G2.IsInSubgroup(bn128.G2.Zero()))
+
+	// point off the twist
+	offCurve := [3][2]*big.Int{p[0], bn128.Fq2.Add(p[1], bn128.Fq2.One()), p[2]}
+	assert.False(t, bn128.G2.IsOnCurve(offCurve))
+	assert.False(t, bn128.G2.IsInSubgroup(offCurve))
+	// coordinates outside Fq, or missing
+	assert.False(t, bn128.G2.IsOnCurve([3][2]*big.Int{p[0], p[1], {p[2][0], new(big.Int).Add(p[2][1], bn128.Q)}}))
+	assert.False(t, bn128.G2.IsOnCurve([3][2]*big.Int{p[0], {p[1][0], nil}, p[2]}))
+
+	// point of the twist outside G2, which the cofactor 2q - r clears
+	np := nonSubgroupPoint(bn128)
+	assert.True(t, bn128.G2.IsOnCurve(np))
+	assert.False(t, bn128.G2.IsInSubgroup(np))
+	assert.False(t, bn128.G2.IsZero(bn128.G2.MulScalar(np, bn128.R)))
+	assert.False(t, bn128.G2.IsInSubgroup(bn128.G2.Add(np, p)))
+	cofactor := new(big.Int).Sub(new(big.Int).Lsh(bn128.Q, 1), bn128.R)
+	cleared := bn128.G2.MulScalar(np, cofactor)
+	assert.True(t, bn128.G2.IsInSubgroup(cleared))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.MulScalar(cleared, bn128.R)))
+}
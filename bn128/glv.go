@@ -0,0 +1,97 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+)
+
+// endomorphism is the decomposition of the scalars used by the GLV (G1) and GLS (G2) scalar multiplications, where
+// the endomorphism acts on the points of order r as the multiplication by lambda, so that k P = k1 P + k2 φ(P) with
+// k = k1 + k2 lambda mod r, being k1 and k2 of half the size of r
+// https://www.iacr.org/archive/crypto2001/21390189.pdf
+type endomorphism struct {
+	r      *big.Int
+	lambda *big.Int
+	v1     [2]*big.Int
+	v2     [2]*big.Int
+	det    *big.Int
+}
+
+// newEndomorphism computes the short basis {v1, v2} of the lattice {(a, b): a + b lambda = 0 mod r} with the
+// extended Euclidean algorithm over r and lambda, as described in the section 4 of the GLV paper
+func newEndomorphism(r, lambda *big.Int) *endomorphism {
+	sqrtR := new(big.Int).Sqrt(r)
+
+	// each remainder r_i = s_i r + t_i lambda gives the vector (r_i, -t_i) of the lattice
+	rPrev, rCur := new(big.Int).Set(r), new(big.Int).Set(lambda)
+	tPrev, tCur := big.NewInt(int64(0)), big.NewInt(int64(1))
+	for rCur.Cmp(sqrtR) >= 0 {
+		quo := new(big.Int).Div(rPrev, rCur)
+		rPrev, rCur = rCur, new(big.Int).Sub(rPrev, new(big.Int).Mul(quo, rCur))
+		tPrev, tCur = tCur, new(big.Int).Sub(tPrev, new(big.Int).Mul(quo, tCur))
+	}
+	// rPrev is the last remainder >= sqrt(r), and rCur the first one below
+	quo := new(big.Int).Div(rPrev, rCur)
+	rNext := new(big.Int).Sub(rPrev, new(big.Int).Mul(quo, rCur))
+	tNext := new(big.Int).Sub(tPrev, new(big.Int).Mul(quo, tCur))
+
+	e := &endomorphism{r: r, lambda: lambda}
+	e.v1 = [2]*big.Int{rCur, new(big.Int).Neg(tCur)}
+	e.v2 = [2]*big.Int{rPrev, new(big.Int).Neg(tPrev)}
+	if normSquare(rNext, tNext).Cmp(normSquare(rPrev, tPrev)) < 0 {
+		e.v2 = [2]*big.Int{rNext, new(big.Int).Neg(tNext)}
+	}
+	e.det = new(big.Int).Sub(new(big.Int).Mul(e.v1[0], e.v2[1]), new(big.Int).Mul(e.v1[1], e.v2[0]))
+	return e
+}
+
+func normSquare(a, b *big.Int) *big.Int {
+	return new(big.Int).Add(new(big.Int).Mul(a, a), new(big.Int).Mul(b, b))
+}
+
+// roundDiv returns the closest integer to n / d
+func roundDiv(n, d *big.Int) *big.Int {
+	if d.Sign() < 0 {
+		n = new(big.Int).Neg(n)
+		d = new(big.Int).Neg(d)
+	}
+	num := new(big.Int).Add(new(big.Int).Lsh(n, 1), d)
+	return num.Div(num, new(big.Int).Lsh(d, 1))
+}
+
+// decompose returns k1, k2 such that k = k1 + k2 lambda mod r, subtracting from (k, 0) its closest vector of the
+// lattice, so that both k1 and k2 (which can be negative) are of about half the size of r
+func (e *endomorphism) decompose(k *big.Int) (*big.Int, *big.Int) {
+	c1 := roundDiv(new(big.Int).Mul(k, e.v2[1]), e.det)
+	c2 := roundDiv(new(big.Int).Neg(new(big.Int).Mul(k, e.v1[1])), e.det)
+
+	k1 := new(big.Int).Sub(k, new(big.Int).Mul(c1, e.v1[0]))
+	k1.Sub(k1, new(big.Int).Mul(c2, e.v2[0]))
+	k2 := new(big.Int).Neg(new(big.Int).Mul(c1, e.v1[1]))
+	k2.Sub(k2, new(big.Int).Mul(c2, e.v2[1]))
+	return k1, k2
+}
+
+// prepareEndomorphisms sets the endomorphisms of G1 and G2 used by MulScalarGLV and MulScalarGLS
+func (bn128 *Bn128) prepareEndomorphisms() error {
+	// φ(x, y) = (β x, y), where β is a cube root of unity of Fq, acts on G1 as the multiplication by λ
+	beta, ok := new(big.Int).SetString("2203960485148121921418603742825762020974279258880205651966", 10)
+	if !ok {
+		return errors.New("err with beta from string")
+	}
+	lambda, ok := new(big.Int).SetString("4407920970296243842393367215006156084916469457145843978461", 10)
+	if !ok {
+		return errors.New("err with lambda from string")
+	}
+	bn128.G1.beta = beta
+	bn128.G1.glv = newEndomorphism(bn128.R, lambda)
+
+	// ψ, the untwist-Frobenius-twist endomorphism, acts on the subgroup of order r of G2 as the multiplication by
+	// q = 6u² mod r
+	bn128.G2.psiX = bn128.TwistMulByQX
+	bn128.G2.psiY = bn128.TwistMulByQY
+	sixU2 := new(big.Int).Mul(bn128.U, bn128.U)
+	sixU2.Mul(sixU2, big.NewInt(int64(6)))
+	bn128.G2.gls = newEndomorphism(bn128.R, sixU2)
+	return nil
+}
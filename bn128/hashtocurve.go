@@ -0,0 +1,309 @@
+package bn128
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// hash to curve of https://www.rfc-editor.org/rfc/rfc9380.html, with expand_message_xmd over SHA-256 and the
+// Shallue-van de Woestijne (SVDW) map, which works for the curves with A = 0 as BN128 and its twist.
+// The suites are BN254G1_XMD:SHA-256_SVDW_RO_ and BN254G2_XMD:SHA-256_SVDW_RO_.
+
+// hashToFieldL is the number of bytes of each element of Fq given by hash_to_field, ceil((ceil(log2(q)) + 128) / 8)
+const hashToFieldL = 48
+
+// svdwG1 are the constants of the SVDW map to G1
+type svdwG1 struct {
+	z, c1, c2, c3, c4 *big.Int
+}
+
+// svdwG2 are the constants of the SVDW map to the twist
+type svdwG2 struct {
+	z, c1, c2, c3, c4 [2]*big.Int
+}
+
+// ExpandMessageXMD returns lenInBytes uniformly random bytes from the message and the domain separation tag dst, with
+// the expand_message_xmd of RFC 9380 over SHA-256
+func ExpandMessageXMD(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	const bInBytes = sha256.Size
+	const sInBytes = sha256.BlockSize
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 || lenInBytes <= 0 {
+		return nil, errors.New("hash to curve: wrong length of the expanded message")
+	}
+	if len(dst) == 0 || len(dst) > 255 {
+		return nil, errors.New("hash to curve: the domain separation tag must have between 1 and 255 bytes")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h := sha256.New()
+	h.Write(make([]byte, sInBytes))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime), b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime)
+	var uniformBytes []byte
+	bi := make([]byte, bInBytes)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniformBytes = append(uniformBytes, bi...)
+	}
+	return uniformBytes[:lenInBytes], nil
+}
+
+// hashToField returns count elements of the extension of degree m of Fq, as the hash_to_field of RFC 9380
+func (bn128 Bn128) hashToField(msg, dst []byte, count, m int) ([][]*big.Int, error) {
+	uniformBytes, err := ExpandMessageXMD(msg, dst, count*m*hashToFieldL)
+	if err != nil {
+		return nil, err
+	}
+	u := make([][]*big.Int, count)
+	for i := 0; i < count; i++ {
+		for j := 0; j < m; j++ {
+			offset := hashToFieldL * (j + i*m)
+			e := new(big.Int).SetBytes(uniformBytes[offset : offset+hashToFieldL])
+			u[i] = append(u[i], e.Mod(e, bn128.Q))
+		}
+	}
+	return u, nil
+}
+
+// sgn0 is the sign of the element of Fq defined in RFC 9380
+func sgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
+
+// sgn0Fq2 is the sign of the element of Fq2 defined in RFC 9380
+func sgn0Fq2(a [2]*big.Int) uint {
+	zero0 := uint(0)
+	if a[0].Sign() == 0 {
+		zero0 = 1
+	}
+	return a[0].Bit(0) | (zero0 & a[1].Bit(0))
+}
+
+// inv0 returns the inverse of a, or 0 if a is 0
+func (bn128 Bn128) inv0(a *big.Int) *big.Int {
+	if bn128.Fq1.IsZero(a) {
+		return bn128.Fq1.Zero()
+	}
+	return bn128.Fq1.Inverse(a)
+}
+
+func (bn128 Bn128) inv0Fq2(a [2]*big.Int) [2]*big.Int {
+	if bn128.Fq2.IsZero(a) {
+		return bn128.Fq2.Zero()
+	}
+	return bn128.Fq2.Inverse(a)
+}
+
+// g1Curve returns x³ + 3
+func (bn128 Bn128) g1Curve(x *big.Int) *big.Int {
+	return bn128.Fq1.Add(bn128.Fq1.Mul(bn128.Fq1.Square(x), x), bn128.CoefB)
+}
+
+// g2Curve returns x³ + 3 / (9 + u)
+func (bn128 Bn128) g2Curve(x [2]*big.Int) [2]*big.Int {
+	return bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.TwistCoefB)
+}
+
+func (bn128 Bn128) isSquare(a *big.Int) bool {
+	_, ok := bn128.Fq1.Sqrt(a)
+	return ok
+}
+
+func (bn128 Bn128) isSquareFq2(a [2]*big.Int) bool {
+	_, ok := bn128.Fq2.Sqrt(a)
+	return ok
+}
+
+// prepareHashToCurve computes the constants of the SVDW maps, where Z is the first of 1, -1, 2, -2, ... that satisfies
+// the conditions of find_z_svdw (RFC 9380, appendix H.1), and the square root c3 is the one with sgn0(c3) = 0
+func (bn128 *Bn128) prepareHashToCurve() error {
+	fq, fq2 := bn128.Fq1, bn128.Fq2
+	three := big.NewInt(int64(3))
+	four := big.NewInt(int64(4))
+
+	// G1, where A = 0, so 3 Z² + 4 A = 3 Z²
+	var s1 svdwG1
+	for ctr := int64(1); s1.z == nil; ctr++ {
+		for _, z := range []*big.Int{fq.Affine(big.NewInt(ctr)), fq.Neg(big.NewInt(ctr))} {
+			gz := bn128.g1Curve(z)
+			if fq.IsZero(gz) {
+				continue
+			}
+			hz := fq.Neg(fq.Div(fq.Mul(three, fq.Square(z)), fq.Mul(four, gz)))
+			if fq.IsZero(hz) || !bn128.isSquare(hz) {
+				continue
+			}
+			if bn128.isSquare(gz) || bn128.isSquare(bn128.g1Curve(fq.Neg(fq.Div(z, big.NewInt(int64(2)))))) {
+				s1.z = z
+				break
+			}
+		}
+		if ctr > 1000 {
+			return errors.New("err finding the Z of the SVDW map to G1")
+		}
+	}
+	threeZ2 := fq.Mul(three, fq.Square(s1.z))
+	s1.c1 = bn128.g1Curve(s1.z)
+	s1.c2 = fq.Neg(fq.Div(s1.z, big.NewInt(int64(2))))
+	c3, ok := fq.Sqrt(fq.Neg(fq.Mul(s1.c1, threeZ2)))
+	if !ok {
+		return errors.New("err computing the constants of the SVDW map to G1")
+	}
+	if sgn0(c3) == 1 {
+		c3 = fq.Neg(c3)
+	}
+	s1.c3 = c3
+	s1.c4 = fq.Neg(fq.Div(fq.Mul(four, s1.c1), threeZ2))
+	bn128.svdwG1 = s1
+
+	// G2
+	var s2 svdwG2
+	for ctr := int64(1); s2.z[0] == nil; ctr++ {
+		for _, z := range [][2]*big.Int{{big.NewInt(ctr), fq.Zero()}, {fq.Neg(big.NewInt(ctr)), fq.Zero()}} {
+			gz := bn128.g2Curve(z)
+			if fq2.IsZero(gz) {
+				continue
+			}
+			hz := fq2.Neg(fq2.Div(fq2.MulScalar(fq2.Square(z), three), fq2.MulScalar(gz, four)))
+			if fq2.IsZero(hz) || !bn128.isSquareFq2(hz) {
+				continue
+			}
+			if bn128.isSquareFq2(gz) || bn128.isSquareFq2(bn128.g2Curve(fq2.Neg(fq2.Mul(z, [2]*big.Int{bn128.TwoInv, fq.Zero()})))) {
+				s2.z = z
+				break
+			}
+		}
+		if ctr > 1000 {
+			return errors.New("err finding the Z of the SVDW map to G2")
+		}
+	}
+	threeZ2Fq2 := fq2.MulScalar(fq2.Square(s2.z), three)
+	s2.c1 = bn128.g2Curve(s2.z)
+	s2.c2 = fq2.Neg(fq2.Mul(s2.z, [2]*big.Int{bn128.TwoInv, fq.Zero()}))
+	c3Fq2, ok := fq2.Sqrt(fq2.Neg(fq2.Mul(s2.c1, threeZ2Fq2)))
+	if !ok {
+		return errors.New("err computing the constants of the SVDW map to G2")
+	}
+	if sgn0Fq2(c3Fq2) == 1 {
+		c3Fq2 = fq2.Neg(c3Fq2)
+	}
+	s2.c3 = c3Fq2
+	s2.c4 = fq2.Neg(fq2.Div(fq2.MulScalar(s2.c1, four), threeZ2Fq2))
+	bn128.svdwG2 = s2
+	return nil
+}
+
+// mapToG1 is the SVDW map of the element u of Fq to a point of the curve (RFC 9380, section 6.6.1)
+func (bn128 Bn128) mapToG1(u *big.Int) [3]*big.Int {
+	fq, c := bn128.Fq1, bn128.svdwG1
+	tv1 := fq.Mul(fq.Square(u), c.c1)
+	tv2 := fq.Add(fq.One(), tv1)
+	tv1 = fq.Sub(fq.One(), tv1)
+	tv3 := bn128.inv0(fq.Mul(tv1, tv2))
+	tv4 := fq.Mul(fq.Mul(fq.Mul(u, tv1), tv3), c.c3)
+
+	x1 := fq.Sub(c.c2, tv4)
+	x2 := fq.Add(c.c2, tv4)
+	x3 := fq.Add(fq.Mul(fq.Square(fq.Mul(fq.Square(tv2), tv3)), c.c4), c.z)
+	var x *big.Int
+	switch {
+	case bn128.isSquare(bn128.g1Curve(x1)):
+		x = x1
+	case bn128.isSquare(bn128.g1Curve(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y, _ := fq.Sqrt(bn128.g1Curve(x))
+	if sgn0(u) != sgn0(y) {
+		y = fq.Neg(y)
+	}
+	return [3]*big.Int{x, y, fq.One()}
+}
+
+// mapToG2 is the SVDW map of the element u of Fq2 to a point of the twist
+func (bn128 Bn128) mapToG2(u [2]*big.Int) [3][2]*big.Int {
+	fq2, c := bn128.Fq2, bn128.svdwG2
+	tv1 := fq2.Mul(fq2.Square(u), c.c1)
+	tv2 := fq2.Add(fq2.One(), tv1)
+	tv1 = fq2.Sub(fq2.One(), tv1)
+	tv3 := bn128.inv0Fq2(fq2.Mul(tv1, tv2))
+	tv4 := fq2.Mul(fq2.Mul(fq2.Mul(u, tv1), tv3), c.c3)
+
+	x1 := fq2.Sub(c.c2, tv4)
+	x2 := fq2.Add(c.c2, tv4)
+	x3 := fq2.Add(fq2.Mul(fq2.Square(fq2.Mul(fq2.Square(tv2), tv3)), c.c4), c.z)
+	var x [2]*big.Int
+	switch {
+	case bn128.isSquareFq2(bn128.g2Curve(x1)):
+		x = x1
+	case bn128.isSquareFq2(bn128.g2Curve(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y, _ := fq2.Sqrt(bn128.g2Curve(x))
+	if sgn0Fq2(u) != sgn0Fq2(y) {
+		y = fq2.Neg(y)
+	}
+	return [3][2]*big.Int{x, y, fq2.One()}
+}
+
+// HashToG1 hashes the message to a point of G1, with the hash_to_curve of RFC 9380 for the suite
+// BN254G1_XMD:SHA-256_SVDW_RO_ and the domain separation tag dst. The cofactor of G1 is 1.
+func (bn128 Bn128) HashToG1(msg, dst []byte) ([3]*big.Int, error) {
+	u, err := bn128.hashToField(msg, dst, 2, 1)
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	q0 := bn128.mapToG1(u[0][0])
+	q1 := bn128.mapToG1(u[1][0])
+	if bn128.G1.Equal(q0, q1) {
+		return bn128.G1.Double(q0), nil
+	}
+	return bn128.G1.Add(q0, q1), nil
+}
+
+// HashToG2 hashes the message to a point of G2, with the hash_to_curve of RFC 9380 for the suite
+// BN254G2_XMD:SHA-256_SVDW_RO_ and the domain separation tag dst, clearing the cofactor of the twist with
+// ClearCofactorG2
+func (bn128 Bn128) HashToG2(msg, dst []byte) ([3][2]*big.Int, error) {
+	u, err := bn128.hashToField(msg, dst, 2, 2)
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	q0 := bn128.mapToG2([2]*big.Int{u[0][0], u[0][1]})
+	q1 := bn128.mapToG2([2]*big.Int{u[1][0], u[1][1]})
+	var r [3][2]*big.Int
+	if bn128.G2.Equal(q0, q1) {
+		r = bn128.G2.Double(q0)
+	} else {
+		r = bn128.G2.Add(q0, q1)
+	}
+	return bn128.ClearCofactorG2(r), nil
+}
+
+// ClearCofactorG2 maps the point of the twist to G2, computing [u]P + ψ([3u]P) + ψ²([u]P) + ψ³(P), which is a
+// multiple of the cofactor 2q - r (section 4.1 of https://eprint.iacr.org/2017/419.pdf)
+func (bn128 Bn128) ClearCofactorG2(p [3][2]*big.Int) [3][2]*big.Int {
+	g2 := bn128.G2
+	up := g2.MulScalar(p, bn128.U)
+	up3 := g2.Add(g2.Double(up), up)
+	res := g2.Add(up, g2.psi(up3))
+	res = g2.Add(res, g2.psi(g2.psi(up)))
+	return g2.Add(res, g2.psi(g2.psi(g2.psi(p))))
+}
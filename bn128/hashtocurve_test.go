@@ -0,0 +1,156 @@
+package bn128
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// test vectors of RFC 9380, appendix K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg, uniformBytes string
+	}{
+		{"", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	}
+	for _, v := range vectors {
+		b, err := ExpandMessageXMD([]byte(v.msg), dst, 0x20)
+		assert.Nil(t, err)
+		assert.Equal(t, v.uniformBytes, hex.EncodeToString(b))
+	}
+
+	b, err := ExpandMessageXMD([]byte("abc"), dst, 200)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, len(b))
+	_, err = ExpandMessageXMD([]byte("abc"), nil, 32)
+	assert.NotNil(t, err)
+	_, err = ExpandMessageXMD([]byte("abc"), dst, 256*32)
+	assert.NotNil(t, err)
+}
+
+func TestHashToG1(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// test vectors of the suite BN254G1_XMD:SHA-256_SVDW_RO_
+	dst := []byte("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+	vectors := []struct {
+		msg, x, y string
+	}{
+		{"", "0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86", "02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5"},
+		{"abc", "23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1", "04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d"},
+	}
+	for _, v := range vectors {
+		p, err := bn128.HashToG1([]byte(v.msg), dst)
+		assert.Nil(t, err)
+		a := bn128.G1.Affine(p)
+		assert.Equal(t, v.x, hex.EncodeToString(a[0].Bytes()))
+		assert.Equal(t, v.y, hex.EncodeToString(a[1].Bytes()))
+		assert.True(t, bn128.G1.IsOnCurve(p))
+	}
+
+	// the domain separation tag changes the point
+	p1, err := bn128.HashToG1([]byte("abc"), []byte("dst 1"))
+	assert.Nil(t, err)
+	p2, err := bn128.HashToG1([]byte("abc"), []byte("dst 2"))
+	assert.Nil(t, err)
+	assert.False(t, bn128.G1.Equal(p1, p2))
+	_, err = bn128.HashToG1([]byte("abc"), nil)
+	assert.NotNil(t, err)
+
+	// the map gives points of the curve for any element of Fq
+	fq := fields.NewFq(bn128.Q)
+	us := []*big.Int{big.NewInt(int64(0)), big.NewInt(int64(1)), fq.Neg(big.NewInt(int64(1)))}
+	for i := 0; i < 20; i++ {
+		u, err := fq.Rand()
+		assert.Nil(t, err)
+		us = append(us, u)
+	}
+	for _, u := range us {
+		p := bn128.mapToG1(u)
+		assert.True(t, bn128.G1.IsOnCurve(p))
+		assert.Equal(t, sgn0(u), sgn0(p[1]))
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// test vectors of the suite BN254G2_XMD:SHA-256_SVDW_RO_, with the coordinates of Fq2 as x0 + x1·u
+	dst := []byte("QUUX-V01-CS02-with-BN254G2_XMD:SHA-256_SVDW_RO_")
+	vectors := []struct {
+		msg            string
+		x0, x1, y0, y1 string
+	}{
+		{"",
+			"1192005a0f121921a6d5629946199e4b27ff8ee4d6dd4f9581dc550ade851300", "1747d950a6f23c16156e2171bce95d1189b04148ad12628869ed21c96a8c9335",
+			"498f6bb5ac309a07d9a8b88e6ff4b8de0d5f27a075830e1eb0e68ea318201d8", "2c9755350ca363ef2cf541005437221c5740086c2e909b71d075152484e845f4"},
+		{"abc",
+			"16c88b54eec9af86a41569608cd0f60aab43464e52ce7e6e298bf584b94fccd2", "b5db3ca7e8ef5edf3a33dfc3242357fbccead98099c3eb564b3d9d13cba4efd",
+			"1c42ba524cb74db8e2c680449746c028f7bea923f245e69f89256af2d6c5f3ac", "22d02d2da7f288545ff8789e789902245ab08c6b1d253561eec789ec2c1bd630"},
+	}
+	for _, v := range vectors {
+		p, err := bn128.HashToG2([]byte(v.msg), dst)
+		assert.Nil(t, err)
+		a := bn128.G2.Affine(p)
+		assert.Equal(t, v.x0, a[0][0].Text(16))
+		assert.Equal(t, v.x1, a[0][1].Text(16))
+		assert.Equal(t, v.y0, a[1][0].Text(16))
+		assert.Equal(t, v.y1, a[1][1].Text(16))
+	}
+
+	for _, msg := range []string{"", "abc", "abcdef0123456789"} {
+		p, err := bn128.HashToG2([]byte(msg), dst)
+		assert.Nil(t, err)
+		assert.True(t, bn128.G2.IsInSubgroup(p))
+		assert.False(t, bn128.G2.IsZero(p))
+		again, err := bn128.HashToG2([]byte(msg), dst)
+		assert.Nil(t, err)
+		assert.True(t, bn128.G2.Equal(p, again))
+	}
+	p1, err := bn128.HashToG2([]byte("abc"), []byte("dst 1"))
+	assert.Nil(t, err)
+	p2, err := bn128.HashToG2([]byte("abc"), []byte("dst 2"))
+	assert.Nil(t, err)
+	assert.False(t, bn128.G2.Equal(p1, p2))
+
+	// the map gives points of the twist for any element of Fq2
+	fq := fields.NewFq(bn128.Q)
+	us := [][2]*big.Int{bn128.Fq2.Zero(), bn128.Fq2.One()}
+	for i := 0; i < 10; i++ {
+		u0, err := fq.Rand()
+		assert.Nil(t, err)
+		u1, err := fq.Rand()
+		assert.Nil(t, err)
+		us = append(us, [2]*big.Int{u0, u1})
+	}
+	for _, u := range us {
+		p := bn128.mapToG2(u)
+		assert.True(t, bn128.G2.IsOnCurve(p))
+		assert.Equal(t, sgn0Fq2(u), sgn0Fq2(p[1]))
+	}
+}
+
+func TestClearCofactorG2(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	np := nonSubgroupPoint(bn128)
+	assert.False(t, bn128.G2.IsInSubgroup(np))
+	cleared := bn128.ClearCofactorG2(np)
+	assert.True(t, bn128.G2.IsInSubgroup(cleared))
+	assert.False(t, bn128.G2.IsZero(cleared))
+
+	// on G2 it is the multiplication by a fixed scalar, so it commutes with the scalar multiplication
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	assert.True(t, bn128.G2.Equal(
+		bn128.ClearCofactorG2(p),
+		bn128.G2.MulScalar(bn128.ClearCofactorG2(bn128.G2.G), big.NewInt(int64(33)))))
+}
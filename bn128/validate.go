@@ -0,0 +1,50 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// CheckG1 returns an error, prefixed by pkg, if the point p (named name in the error) is not a point of G1
+func (bn128 Bn128) CheckG1(pkg, name string, p [3]*big.Int) error {
+	if !bn128.G1.IsOnCurve(p) {
+		return errors.New(pkg + ": " + name + " not on the curve")
+	}
+	return nil
+}
+
+// CheckG2 returns an error, prefixed by pkg, if the point p (named name in the error) is not a point of the subgroup
+// of order r of G2
+func (bn128 Bn128) CheckG2(pkg, name string, p [3][2]*big.Int) error {
+	if !bn128.G2.IsOnCurve(p) {
+		return errors.New(pkg + ": " + name + " not on the curve")
+	}
+	if !bn128.G2.IsInSubgroup(p) {
+		return errors.New(pkg + ": " + name + " not in the subgroup of order r")
+	}
+	return nil
+}
+
+// CheckScalar returns an error, prefixed by pkg, if x (named name in the error) is not inside the Finite Field over R
+func (bn128 Bn128) CheckScalar(pkg, name string, x *big.Int) error {
+	if x == nil || x.Sign() < 0 || x.Cmp(bn128.R) >= 0 {
+		return errors.New(pkg + ": " + name + " not inside the Finite Field")
+	}
+	return nil
+}
+
+// CheckPublicSignals returns an error, prefixed by pkg, if there are not n public signals, or if any of them is not
+// inside the Finite Field over R
+func (bn128 Bn128) CheckPublicSignals(pkg string, publicSignals []*big.Int, n int) error {
+	if len(publicSignals) != n {
+		return errors.New(pkg + ": wrong number of public signals, expected " + strconv.Itoa(n) +
+			", got " + strconv.Itoa(len(publicSignals)))
+	}
+	for i, s := range publicSignals {
+		if err := bn128.CheckScalar(pkg, "public signal "+strconv.Itoa(i), s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package bn128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPoints(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.Nil(t, bn128.CheckG1("pkg", "p", bn128.G1.G))
+	offCurve := [3]*big.Int{bn128.G1.G[0], big.NewInt(int64(3)), bn128.G1.G[2]}
+	assert.Equal(t, "pkg: p not on the curve", bn128.CheckG1("pkg", "p", offCurve).Error())
+
+	assert.Nil(t, bn128.CheckG2("pkg", "q", bn128.G2.G))
+	np := nonSubgroupPoint(bn128)
+	assert.Equal(t, "pkg: q not in the subgroup of order r", bn128.CheckG2("pkg", "q", np).Error())
+}
+
+func TestCheckPublicSignals(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.Nil(t, bn128.CheckPublicSignals("pkg", []*big.Int{big.NewInt(int64(1))}, 1))
+	err = bn128.CheckPublicSignals("pkg", []*big.Int{big.NewInt(int64(1))}, 2)
+	assert.Equal(t, "pkg: wrong number of public signals, expected 2, got 1", err.Error())
+	err = bn128.CheckPublicSignals("pkg", []*big.Int{bn128.R}, 1)
+	assert.Equal(t, "pkg: public signal 0 not inside the Finite Field", err.Error())
+	err = bn128.CheckPublicSignals("pkg", []*big.Int{nil}, 1)
+	assert.Equal(t, "pkg: public signal 0 not inside the Finite Field", err.Error())
+}
@@ -0,0 +1,312 @@
+// Package ceremony implements the phase 1 of the multi-party trusted setup ceremony for the Groth16 zkSNARK, the
+// powers of tau, where each participant contributes with its own secret τ, α and β to the accumulated powers, so
+// the final powers are secure as long as one of the participants has destroyed its secrets. The phase 2 derives from the
+// powers of tau the Groth16 keys of a circuit, and in the same way each participant contributes with its own secret δ.
+package ceremony
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/r1csqap"
+	"github.com/arnaucube/go-snark/transcript"
+)
+
+// Accumulator holds the accumulated powers of τ, α and β, encrypted in the G1 and G2 curves, for circuits up to Size constraints
+type Accumulator struct {
+	TauG1      [][3]*big.Int    // {τ^i G1} from 0 to 2*Size-2
+	TauG2      [][3][2]*big.Int // {τ^i G2} from 0 to Size-1
+	AlphaTauG1 [][3]*big.Int    // {α τ^i G1} from 0 to Size-1
+	BetaTauG1  [][3]*big.Int    // {β τ^i G1} from 0 to Size-1
+	BetaG2     [3][2]*big.Int   // β G2
+}
+
+// KnowledgeProof is a Schnorr proof of knowledge of the secret x of the point x*G1, where z*G1 = R + c*(x*G1)
+// and c is the hash of the transcript, the point and R
+type KnowledgeProof struct {
+	R [3]*big.Int
+	Z *big.Int
+}
+
+// PublicKey holds the secrets of a contribution encrypted in the G1 and G2 curves, with the proofs of knowledge of them
+type PublicKey struct {
+	TauG1      [3]*big.Int
+	AlphaG1    [3]*big.Int
+	BetaG1     [3]*big.Int
+	TauG2      [3][2]*big.Int
+	AlphaG2    [3][2]*big.Int
+	BetaG2     [3][2]*big.Int
+	TauProof   KnowledgeProof
+	AlphaProof KnowledgeProof
+	BetaProof  KnowledgeProof
+}
+
+// Contribution holds the PublicKey of a contribution and the points of the Accumulator after it, which are used to verify
+// the chain of contributions without the intermediate Accumulators
+type Contribution struct {
+	PublicKey PublicKey
+	TauG1     [3]*big.Int    // τ G1
+	AlphaG1   [3]*big.Int    // α G1
+	BetaG1    [3]*big.Int    // β G1
+	BetaG2    [3][2]*big.Int // β G2
+
+	// for the random beacon contribution, the beacon value and the log2 of the number of times that it is hashed
+	Beacon              []byte
+	BeaconIterationsExp int
+}
+
+// Transcript is the data structure of the powers of tau ceremony, holding the current Accumulator and all the Contributions
+type Transcript struct {
+	Size          int
+	Accumulator   Accumulator
+	Contributions []Contribution
+}
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+	PF  r1csqap.PolynomialField
+}
+
+// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the ceremony operations
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	fqR := fields.NewFq(bn.R)
+	pf := r1csqap.NewPolynomialField(fqR)
+	return utils{
+		Bn:  bn,
+		FqR: fqR,
+		PF:  pf,
+	}
+}
+
+// NewTranscript creates a new Transcript of the powers of tau for circuits up to size constraints, where all the powers are the generators
+func NewTranscript(size int) (*Transcript, error) {
+	if size < 2 {
+		return nil, errors.New("ceremony: size must be at least 2")
+	}
+	t := &Transcript{Size: size}
+	for i := 0; i < 2*size-1; i++ {
+		t.Accumulator.TauG1 = append(t.Accumulator.TauG1, Utils.Bn.G1.G)
+	}
+	for i := 0; i < size; i++ {
+		t.Accumulator.TauG2 = append(t.Accumulator.TauG2, Utils.Bn.G2.G)
+		t.Accumulator.AlphaTauG1 = append(t.Accumulator.AlphaTauG1, Utils.Bn.G1.G)
+		t.Accumulator.BetaTauG1 = append(t.Accumulator.BetaTauG1, Utils.Bn.G1.G)
+	}
+	t.Accumulator.BetaG2 = Utils.Bn.G2.G
+	return t, nil
+}
+
+// secrets are the toxic values of a contribution, which must be destroyed after the contribution
+type secrets struct {
+	Tau   *big.Int
+	Alpha *big.Int
+	Beta  *big.Int
+}
+
+// Contribute adds a contribution to the Transcript with random secrets, which are destroyed once the contribution is done
+func (t *Transcript) Contribute() error {
+	var s secrets
+	var err error
+	for _, x := range []**big.Int{&s.Tau, &s.Alpha, &s.Beta} {
+		*x, err = Utils.FqR.RandNonZero()
+		if err != nil {
+			return err
+		}
+	}
+	return t.contribute(s, nil, 0)
+}
+
+// MaxBeaconIterationsExp is the maximum log2 of the number of times that the beacon value is hashed, as 2^42 hashes
+// already take days, and the verifiers must compute them again
+const MaxBeaconIterationsExp = 42
+
+// checkBeaconIterationsExp returns an error if the exponent is not between 0 and MaxBeaconIterationsExp
+func checkBeaconIterationsExp(iterationsExp int) error {
+	if iterationsExp < 0 || iterationsExp > MaxBeaconIterationsExp {
+		return errors.New("beacon iterations exponent " + strconv.Itoa(iterationsExp) + " out of range, the maximum is " + strconv.Itoa(MaxBeaconIterationsExp))
+	}
+	return nil
+}
+
+// ApplyBeacon adds the final contribution to the Transcript, with the secrets derived from a public random beacon
+// value (for example, a future block hash), hashed 2^iterationsExp times, so anyone can verify it
+func (t *Transcript) ApplyBeacon(beacon []byte, iterationsExp int) error {
+	if err := checkBeaconIterationsExp(iterationsExp); err != nil {
+		return errors.New("ceremony: " + err.Error())
+	}
+	return t.contribute(beaconSecrets(beacon, iterationsExp), beacon, iterationsExp)
+}
+
+// beaconSecrets derives the secrets of the beacon contribution
+func beaconSecrets(beacon []byte, iterationsExp int) secrets {
+	h := sha256.Sum256(beacon)
+	for i := uint64(0); i < uint64(1)<<uint(iterationsExp); i++ {
+		h = sha256.Sum256(h[:])
+	}
+	derive := func(label string) *big.Int {
+		d := sha256.Sum256(append(h[:], []byte(label)...))
+		x := new(big.Int).Mod(new(big.Int).SetBytes(d[:]), Utils.FqR.Q)
+		if x.Sign() == 0 {
+			x = big.NewInt(int64(1))
+		}
+		return x
+	}
+	return secrets{
+		Tau:   derive("tau"),
+		Alpha: derive("alpha"),
+		Beta:  derive("beta"),
+	}
+}
+
+// generatorPoints returns the points of the initial Accumulator
+func generatorPoints() Contribution {
+	return Contribution{
+		TauG1:   Utils.Bn.G1.G,
+		AlphaG1: Utils.Bn.G1.G,
+		BetaG1:  Utils.Bn.G1.G,
+		BetaG2:  Utils.Bn.G2.G,
+	}
+}
+
+// lastPoints returns the points of the Accumulator after the last contribution
+func (t *Transcript) lastPoints() Contribution {
+	if len(t.Contributions) == 0 {
+		return generatorPoints()
+	}
+	return t.Contributions[len(t.Contributions)-1]
+}
+
+func (t *Transcript) contribute(s secrets, beacon []byte, iterationsExp int) error {
+	prev := t.lastPoints()
+	pk, err := newPublicKey(s, prev)
+	if err != nil {
+		return err
+	}
+
+	acc := &t.Accumulator
+	tauI := big.NewInt(int64(1))
+	for i := 0; i < len(acc.TauG1); i++ {
+		acc.TauG1[i] = affineG1(Utils.Bn.G1.MulScalarConstantTime(acc.TauG1[i], tauI))
+		if i < t.Size {
+			acc.TauG2[i] = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(acc.TauG2[i], tauI))
+			acc.AlphaTauG1[i] = affineG1(Utils.Bn.G1.MulScalarConstantTime(acc.AlphaTauG1[i], Utils.FqR.Mul(s.Alpha, tauI)))
+			acc.BetaTauG1[i] = affineG1(Utils.Bn.G1.MulScalarConstantTime(acc.BetaTauG1[i], Utils.FqR.Mul(s.Beta, tauI)))
+		}
+		tauI = Utils.FqR.Mul(tauI, s.Tau)
+	}
+	acc.BetaG2 = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(acc.BetaG2, s.Beta))
+
+	t.Contributions = append(t.Contributions, Contribution{
+		PublicKey:           pk,
+		TauG1:               acc.TauG1[1],
+		AlphaG1:             acc.AlphaTauG1[0],
+		BetaG1:              acc.BetaTauG1[0],
+		BetaG2:              acc.BetaG2,
+		Beacon:              beacon,
+		BeaconIterationsExp: iterationsExp,
+	})
+	return nil
+}
+
+func newPublicKey(s secrets, prev Contribution) (PublicKey, error) {
+	var pk PublicKey
+	var err error
+	pk.TauG1 = affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, s.Tau))
+	pk.AlphaG1 = affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, s.Alpha))
+	pk.BetaG1 = affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, s.Beta))
+	pk.TauG2 = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, s.Tau))
+	pk.AlphaG2 = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, s.Alpha))
+	pk.BetaG2 = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, s.Beta))
+	g1s, g2s := prev.boundPoints()
+	pk.TauProof, err = proveKnowledge("tau", g1s, g2s, s.Tau, pk.TauG1)
+	if err != nil {
+		return pk, err
+	}
+	pk.AlphaProof, err = proveKnowledge("alpha", g1s, g2s, s.Alpha, pk.AlphaG1)
+	if err != nil {
+		return pk, err
+	}
+	pk.BetaProof, err = proveKnowledge("beta", g1s, g2s, s.Beta, pk.BetaG1)
+	return pk, err
+}
+
+func affineG1(p [3]*big.Int) [3]*big.Int {
+	if Utils.Bn.G1.IsZero(p) {
+		return p
+	}
+	a := Utils.Bn.G1.Affine(p)
+	return [3]*big.Int{a[0], a[1], Utils.Bn.G1.F.One()}
+}
+
+// boundPoints returns the points of the Accumulator after the contribution, to which the next proofs of knowledge are bound
+func (c Contribution) boundPoints() ([][3]*big.Int, [][3][2]*big.Int) {
+	return [][3]*big.Int{c.TauG1, c.AlphaG1, c.BetaG1}, [][3][2]*big.Int{c.BetaG2}
+}
+
+// knowledgeChallenge returns the challenge of the proof of knowledge, binding it to the given points of the previous state
+func knowledgeChallenge(label string, g1s [][3]*big.Int, g2s [][3][2]*big.Int, x, r [3]*big.Int) *big.Int {
+	t := transcript.New("go-snark ceremony", transcript.SHA256)
+	t.AppendG1("previous", g1s...)
+	t.AppendG2("previous", g2s...)
+	t.AppendG1("x", x)
+	t.AppendG1("R", r)
+	return t.Challenge(label)
+}
+
+func proveKnowledge(label string, g1s [][3]*big.Int, g2s [][3][2]*big.Int, secret *big.Int, x [3]*big.Int) (KnowledgeProof, error) {
+	k, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return KnowledgeProof{}, err
+	}
+	r := affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, k))
+	c := knowledgeChallenge(label, g1s, g2s, x, r)
+	return KnowledgeProof{
+		R: r,
+		Z: Utils.FqR.Add(k, Utils.FqR.Mul(c, secret)),
+	}, nil
+}
+
+func verifyKnowledge(label string, g1s [][3]*big.Int, g2s [][3][2]*big.Int, x [3]*big.Int, proof KnowledgeProof) bool {
+	if proof.Z == nil {
+		return false
+	}
+	c := knowledgeChallenge(label, g1s, g2s, x, proof.R)
+	return Utils.Bn.G1.Equal(
+		Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, proof.Z),
+		Utils.Bn.G1.Add(proof.R, Utils.Bn.G1.MulScalar(x, c)))
+}
+
+// WriteTranscript stores the Transcript into a json file
+func WriteTranscript(path string, t *Transcript) error {
+	jsonData, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, jsonData, 0644)
+}
+
+// ReadTranscript loads the Transcript from a json file
+func ReadTranscript(path string) (*Transcript, error) {
+	jsonData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Transcript
+	if err := json.Unmarshal(jsonData, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
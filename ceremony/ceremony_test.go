@@ -0,0 +1,126 @@
+package ceremony
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContribution(t *testing.T) {
+	transcript, err := NewTranscript(3)
+	assert.Nil(t, err)
+	s := secrets{
+		Tau:   big.NewInt(int64(2)),
+		Alpha: big.NewInt(int64(3)),
+		Beta:  big.NewInt(int64(5)),
+	}
+	assert.Nil(t, transcript.contribute(s, nil, 0))
+	assert.Nil(t, transcript.contribute(s, nil, 0))
+
+	// τ = 4, α = 9, β = 25
+	acc := transcript.Accumulator
+	assert.Equal(t, 5, len(acc.TauG1))
+	assert.Equal(t, 3, len(acc.TauG2))
+	for i, tauI := range []int64{1, 4, 16, 64, 256} {
+		assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, big.NewInt(tauI)), acc.TauG1[i]))
+		if i < 3 {
+			assert.True(t, Utils.Bn.G2.Equal(Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, big.NewInt(tauI)), acc.TauG2[i]))
+			assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, big.NewInt(9*tauI)), acc.AlphaTauG1[i]))
+			assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, big.NewInt(25*tauI)), acc.BetaTauG1[i]))
+		}
+	}
+	assert.True(t, Utils.Bn.G2.Equal(Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, big.NewInt(int64(25))), acc.BetaG2))
+
+	_, err = NewTranscript(1)
+	assert.NotNil(t, err)
+}
+
+func TestCeremony(t *testing.T) {
+	transcript, err := NewTranscript(4)
+	assert.Nil(t, err)
+	assert.NotNil(t, transcript.Verify())
+
+	assert.Nil(t, transcript.Contribute())
+	assert.Nil(t, transcript.Contribute())
+	assert.Nil(t, transcript.ApplyBeacon([]byte("beacon"), 4))
+	assert.Equal(t, 3, len(transcript.Contributions))
+
+	dir, err := ioutil.TempDir("", "ceremony")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "powersoftau.json")
+	assert.Nil(t, WriteTranscript(path, transcript))
+	transcript, err = ReadTranscript(path)
+	assert.Nil(t, err)
+
+	before := time.Now()
+	assert.Nil(t, transcript.Verify())
+	fmt.Println("verify ceremony time elapsed:", time.Since(before))
+}
+
+func TestCeremonyWrongContributions(t *testing.T) {
+	transcript, err := NewTranscript(2)
+	assert.Nil(t, err)
+	assert.Nil(t, transcript.Contribute())
+	assert.Nil(t, transcript.Verify())
+
+	// accumulator not matching the contribution
+	tauG1 := transcript.Accumulator.TauG1[2]
+	transcript.Accumulator.TauG1[2] = Utils.Bn.G1.Double(tauG1)
+	assert.NotNil(t, transcript.Verify())
+	transcript.Accumulator.TauG1[2] = tauG1
+
+	// wrong proof of knowledge
+	z := transcript.Contributions[0].PublicKey.TauProof.Z
+	transcript.Contributions[0].PublicKey.TauProof.Z = Utils.FqR.Add(z, big.NewInt(int64(1)))
+	assert.NotNil(t, transcript.Verify())
+	transcript.Contributions[0].PublicKey.TauProof.Z = z
+
+	// point of the public key off the curve
+	pkTauG1 := transcript.Contributions[0].PublicKey.TauG1
+	transcript.Contributions[0].PublicKey.TauG1 = [3]*big.Int{pkTauG1[0], Utils.Bn.G1.F.Add(pkTauG1[1], Utils.Bn.G1.F.One()), pkTauG1[2]}
+	err = transcript.Verify()
+	assert.Equal(t, "ceremony: contribution 0: point not on the curve", err.Error())
+	transcript.Contributions[0].PublicKey.TauG1 = pkTauG1
+
+	// point of the twist outside G2 in the accumulator
+	tauG2 := transcript.Accumulator.TauG2[1]
+	transcript.Accumulator.TauG2[1] = notInG2()
+	err = transcript.Verify()
+	assert.Equal(t, "ceremony: G2 point not in the subgroup of order r", err.Error())
+	transcript.Accumulator.TauG2[1] = tauG2
+	assert.Nil(t, transcript.Verify())
+
+	// beacon value not matching the secrets
+	assert.Nil(t, transcript.ApplyBeacon([]byte("beacon"), 0))
+	transcript.Contributions[1].Beacon = []byte("another beacon")
+	assert.NotNil(t, transcript.Verify())
+
+	transcript.Contributions[1].Beacon = []byte("beacon")
+	assert.Nil(t, transcript.Verify())
+
+	assert.NotNil(t, transcript.ApplyBeacon([]byte("beacon"), -1))
+	err = transcript.ApplyBeacon([]byte("beacon"), MaxBeaconIterationsExp+1)
+	assert.Equal(t, "ceremony: beacon iterations exponent 43 out of range, the maximum is 42", err.Error())
+	// the verification rejects a bigger exponent without hashing the beacon
+	transcript.Contributions[1].BeaconIterationsExp = 63
+	err = transcript.Verify()
+	assert.Equal(t, "ceremony: contribution 1: beacon iterations exponent 63 out of range, the maximum is 42", err.Error())
+}
+
+// notInG2 returns a point of the twist outside G2
+func notInG2() [3][2]*big.Int {
+	fq2 := Utils.Bn.Fq2
+	for i := 1; ; i++ {
+		x := [2]*big.Int{big.NewInt(int64(i)), big.NewInt(int64(0))}
+		if y, ok := fq2.Sqrt(fq2.Add(fq2.Mul(fq2.Square(x), x), Utils.Bn.TwistCoefB)); ok {
+			return [3][2]*big.Int{x, y, fq2.One()}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package ceremony
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/groth16"
+)
+
+// DeltaContribution holds the secret x of a contribution to δ encrypted in the G1 and G2 curves, with its proof of
+// knowledge, and δ after the contribution (the previous δ multiplied by x)
+type DeltaContribution struct {
+	XG1     [3]*big.Int
+	XG2     [3][2]*big.Int
+	Proof   KnowledgeProof
+	DeltaG1 [3]*big.Int    // δ G1
+	DeltaG2 [3][2]*big.Int // δ G2
+}
+
+// Phase2 is the data structure of the circuit specific phase 2 of the ceremony, holding the Groth16 keys of the circuit
+// derived from the powers of tau, and all the contributions to δ. The Setup.Toxic sub struct is never set.
+type Phase2 struct {
+	Setup         groth16.Setup
+	Contributions []DeltaContribution
+}
+
+// lagrangePoints returns {Σ_k L_j[k] powers[k]} from 1 to n, the Lagrange polynomials over the points 1..n where the
+// constraints are interpolated, evaluated at τ and encrypted in the G1 curve
+func lagrangePoints(polynomials [][]*big.Int, powers [][3]*big.Int) [][3]*big.Int {
+	var points [][3]*big.Int
+	for _, l := range polynomials {
+		points = append(points, affineG1(linearCombination(powers[:len(l)], l)))
+	}
+	return points
+}
+
+func lagrangePointsG2(polynomials [][]*big.Int, powers [][3][2]*big.Int) [][3][2]*big.Int {
+	var points [][3][2]*big.Int
+	for _, l := range polynomials {
+		points = append(points, Utils.Bn.G2.Affine(linearCombinationG2(powers[:len(l)], l)))
+	}
+	return points
+}
+
+// signalPoint returns Σ_j m[j][i] points[j], the polynomial of the signal i evaluated at τ from the Lagrange points
+func signalPoint(acc [3]*big.Int, m [][]*big.Int, i int, points [][3]*big.Int) [3]*big.Int {
+	for j := range m {
+		if m[j][i].Sign() != 0 {
+			acc = Utils.Bn.G1.Add(acc, Utils.Bn.G1.MulScalar(points[j], m[j][i]))
+		}
+	}
+	return acc
+}
+
+func signalPointG2(m [][]*big.Int, i int, points [][3][2]*big.Int) [3][2]*big.Int {
+	acc := Utils.Bn.G2.Zero()
+	for j := range m {
+		if m[j][i].Sign() != 0 {
+			acc = Utils.Bn.G2.Add(acc, Utils.Bn.G2.MulScalar(points[j], m[j][i]))
+		}
+	}
+	return acc
+}
+
+// NewPhase2 derives from the powers of tau of the Transcript the Groth16 keys of the compiled Circuit (with its R1CS
+// generated), computing the polynomials of the signals in the Lagrange basis. γ and δ are 1, so the keys are only secure
+// once contributed, and the Transcript must have been verified.
+func NewPhase2(t *Transcript, circuit circuitcompiler.Circuit) (*Phase2, error) {
+	a, b, c := circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C
+	n := len(a)
+	if n == 0 || len(b) != n || len(c) != n {
+		return nil, errors.New("ceremony: circuit without R1CS")
+	}
+	if n > t.Size {
+		return nil, errors.New("ceremony: circuit of " + strconv.Itoa(n) + " constraints bigger than the powers of tau size " + strconv.Itoa(t.Size))
+	}
+	acc := t.Accumulator
+	if len(acc.TauG1) != 2*t.Size-1 || len(acc.TauG2) != t.Size ||
+		len(acc.AlphaTauG1) != t.Size || len(acc.BetaTauG1) != t.Size {
+		return nil, errors.New("ceremony: wrong accumulator length")
+	}
+	nVars := len(a[0])
+
+	lagrange := Utils.PF.LagrangePolynomials(n)
+	lTau := lagrangePoints(lagrange, acc.TauG1)
+	lAlphaTau := lagrangePoints(lagrange, acc.AlphaTauG1)
+	lBetaTau := lagrangePoints(lagrange, acc.BetaTauG1)
+	lTauG2 := lagrangePointsG2(lagrange, acc.TauG2)
+
+	var setup groth16.Setup
+	zero3 := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := 0; i < nVars; i++ {
+		setup.Pk.G1.At = append(setup.Pk.G1.At, affineG1(signalPoint(zero3, a, i, lTau)))
+		setup.Pk.G1.BACGamma = append(setup.Pk.G1.BACGamma, affineG1(signalPoint(zero3, b, i, lTau)))
+		setup.Pk.G2.BACGamma = append(setup.Pk.G2.BACGamma, Utils.Bn.G2.Affine(signalPointG2(b, i, lTauG2)))
+
+		// ( βui(τ)+αvi(τ)+wi(τ) ) G1, divided by γ = 1 for the public signals and by δ = 1 for the rest
+		bac := signalPoint(zero3, a, i, lBetaTau)
+		bac = signalPoint(bac, b, i, lAlphaTau)
+		bac = affineG1(signalPoint(bac, c, i, lTau))
+		if i <= circuit.NPublic {
+			setup.Vk.IC = append(setup.Vk.IC, bac)
+			setup.Pk.BACDelta = append(setup.Pk.BACDelta, zero3)
+		} else {
+			setup.Pk.BACDelta = append(setup.Pk.BACDelta, bac)
+		}
+	}
+
+	// {τ^i Z(τ) / δ} up to the degree n-2 of the h(x) polynomial of the proofs
+	setup.Pk.Z = Utils.PF.ZeroPolynomial(n)
+	for i := 0; i < n-1; i++ {
+		setup.Pk.PowersTauDelta = append(setup.Pk.PowersTauDelta, affineG1(linearCombination(acc.TauG1[i:i+len(setup.Pk.Z)], setup.Pk.Z)))
+	}
+
+	setup.Pk.G1.Alpha = acc.AlphaTauG1[0]
+	setup.Pk.G1.Beta = acc.BetaTauG1[0]
+	setup.Pk.G1.Delta = Utils.Bn.G1.G
+	setup.Pk.G2.Beta = acc.BetaG2
+	setup.Pk.G2.Gamma = Utils.Bn.G2.G
+	setup.Pk.G2.Delta = Utils.Bn.G2.G
+
+	setup.Vk.G1.Alpha = acc.AlphaTauG1[0]
+	setup.Vk.G2.Beta = acc.BetaG2
+	setup.Vk.G2.Gamma = Utils.Bn.G2.G
+	setup.Vk.G2.Delta = Utils.Bn.G2.G
+	return &Phase2{Setup: setup}, nil
+}
+
+// lastDelta returns δ after the last contribution, encrypted in the G1 and G2 curves
+func (p *Phase2) lastDelta() ([3]*big.Int, [3][2]*big.Int) {
+	if len(p.Contributions) == 0 {
+		return Utils.Bn.G1.G, Utils.Bn.G2.G
+	}
+	c := p.Contributions[len(p.Contributions)-1]
+	return c.DeltaG1, c.DeltaG2
+}
+
+// Contribute multiplies δ by a random secret, which is destroyed once the contribution is done, updating the keys
+// that depend on it
+func (p *Phase2) Contribute() error {
+	x, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return err
+	}
+	xInv := Utils.FqR.Inverse(x)
+	prevG1, prevG2 := p.lastDelta()
+
+	var contribution DeltaContribution
+	contribution.XG1 = affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, x))
+	contribution.XG2 = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, x))
+	contribution.Proof, err = proveKnowledge("delta", [][3]*big.Int{prevG1}, [][3][2]*big.Int{prevG2}, x, contribution.XG1)
+	if err != nil {
+		return err
+	}
+
+	setup := &p.Setup
+	setup.Pk.G1.Delta = affineG1(Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Delta, x))
+	setup.Pk.G2.Delta = Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(setup.Pk.G2.Delta, x))
+	setup.Vk.G2.Delta = setup.Pk.G2.Delta
+	for i := range setup.Pk.BACDelta {
+		if !Utils.Bn.G1.IsZero(setup.Pk.BACDelta[i]) {
+			setup.Pk.BACDelta[i] = affineG1(Utils.Bn.G1.MulScalarConstantTime(setup.Pk.BACDelta[i], xInv))
+		}
+	}
+	for i := range setup.Pk.PowersTauDelta {
+		setup.Pk.PowersTauDelta[i] = affineG1(Utils.Bn.G1.MulScalarConstantTime(setup.Pk.PowersTauDelta[i], xInv))
+	}
+
+	contribution.DeltaG1 = setup.Pk.G1.Delta
+	contribution.DeltaG2 = setup.Pk.G2.Delta
+	p.Contributions = append(p.Contributions, contribution)
+	return nil
+}
+
+func equalG1s(a, b [][3]*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Utils.Bn.G1.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalG2s(a, b [][3][2]*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Utils.Bn.G2.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyKeys checks that the keys that do not depend on δ are the ones derived from the powers of tau
+func (p *Phase2) verifyKeys(initial groth16.Setup) error {
+	pk, vk := p.Setup.Pk, p.Setup.Vk
+	if !equalG1s(pk.G1.At, initial.Pk.G1.At) || !equalG1s(pk.G1.BACGamma, initial.Pk.G1.BACGamma) ||
+		!equalG2s(pk.G2.BACGamma, initial.Pk.G2.BACGamma) || !equalG1s(vk.IC, initial.Vk.IC) {
+		return errors.New("signals keys not derived from the powers of tau")
+	}
+	if !Utils.Bn.G1.Equal(pk.G1.Alpha, initial.Pk.G1.Alpha) || !Utils.Bn.G1.Equal(pk.G1.Beta, initial.Pk.G1.Beta) ||
+		!Utils.Bn.G2.Equal(pk.G2.Beta, initial.Pk.G2.Beta) || !Utils.Bn.G1.Equal(vk.G1.Alpha, initial.Vk.G1.Alpha) ||
+		!Utils.Bn.G2.Equal(vk.G2.Beta, initial.Vk.G2.Beta) || !Utils.Bn.G2.Equal(vk.G2.Gamma, initial.Vk.G2.Gamma) {
+		return errors.New("α, β or γ not derived from the powers of tau")
+	}
+	if len(pk.Z) != len(initial.Pk.Z) {
+		return errors.New("wrong Z polynomial")
+	}
+	for i := range pk.Z {
+		if pk.Z[i] == nil || pk.Z[i].Cmp(initial.Pk.Z[i]) != 0 {
+			return errors.New("wrong Z polynomial")
+		}
+	}
+	if len(pk.BACDelta) != len(initial.Pk.BACDelta) || len(pk.PowersTauDelta) != len(initial.Pk.PowersTauDelta) {
+		return errors.New("wrong δ keys length")
+	}
+	return nil
+}
+
+// Verify verifies the whole chain of contributions to δ of the Phase2, and that its keys are the ones derived from the
+// powers of tau of the Transcript for the compiled Circuit, divided by the final δ
+func (p *Phase2) Verify(t *Transcript, circuit circuitcompiler.Circuit) error {
+	if len(p.Contributions) == 0 {
+		return errors.New("ceremony: phase 2: no contributions")
+	}
+	initial, err := NewPhase2(t, circuit)
+	if err != nil {
+		return err
+	}
+	if err := p.verifyKeys(initial.Setup); err != nil {
+		return errors.New("ceremony: phase 2: " + err.Error())
+	}
+
+	prevG1, prevG2 := Utils.Bn.G1.G, Utils.Bn.G2.G
+	for i, c := range p.Contributions {
+		if err := verifyDeltaContribution(prevG1, prevG2, c); err != nil {
+			return errors.New("ceremony: phase 2: contribution " + strconv.Itoa(i) + ": " + err.Error())
+		}
+		prevG1, prevG2 = c.DeltaG1, c.DeltaG2
+	}
+	if err := p.verifyDelta(initial.Setup, prevG1, prevG2); err != nil {
+		return errors.New("ceremony: phase 2: " + err.Error())
+	}
+	return nil
+}
+
+// verifyDeltaContribution checks the DeltaContribution c over δ before it
+func verifyDeltaContribution(prevG1 [3]*big.Int, prevG2 [3][2]*big.Int, c DeltaContribution) error {
+	if err := checkPoints([][3]*big.Int{c.XG1, c.DeltaG1, c.Proof.R}, [][3][2]*big.Int{c.XG2, c.DeltaG2}); err != nil {
+		return err
+	}
+	if Utils.Bn.G1.IsZero(c.XG1) {
+		return errors.New("zero secret")
+	}
+	if !verifyKnowledge("delta", [][3]*big.Int{prevG1}, [][3][2]*big.Int{prevG2}, c.XG1, c.Proof) {
+		return errors.New("invalid proof of knowledge")
+	}
+	// (G1, x G1) and (prev δ G1, δ G1) with (G2, x G2), both checked at once with a random linear combination
+	r, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return err
+	}
+	if !sameRatio(
+		Utils.Bn.G1.Add(Utils.Bn.G1.G, Utils.Bn.G1.MulScalarGLV(prevG1, r)),
+		Utils.Bn.G1.Add(c.XG1, Utils.Bn.G1.MulScalarGLV(c.DeltaG1, r)),
+		Utils.Bn.G2.G, c.XG2) {
+		return errors.New("δ not applied")
+	}
+	if !sameRatio(Utils.Bn.G1.G, c.XG1, prevG2, c.DeltaG2) {
+		return errors.New("δ not applied in G2")
+	}
+	return nil
+}
+
+// verifyDelta checks that the final δ is the one of the last contribution, and that the keys divided by δ are the
+// initial ones (where δ = 1) divided by it
+func (p *Phase2) verifyDelta(initial groth16.Setup, deltaG1 [3]*big.Int, deltaG2 [3][2]*big.Int) error {
+	pk := p.Setup.Pk
+	if !Utils.Bn.G1.Equal(pk.G1.Delta, deltaG1) || !Utils.Bn.G2.Equal(pk.G2.Delta, deltaG2) ||
+		!Utils.Bn.G2.Equal(p.Setup.Vk.G2.Delta, deltaG2) {
+		return errors.New("δ does not match the last contribution")
+	}
+	if err := checkPoints(append(append([][3]*big.Int{}, pk.BACDelta...), pk.PowersTauDelta...), nil); err != nil {
+		return err
+	}
+	for i := range pk.BACDelta {
+		if Utils.Bn.G1.IsZero(initial.Pk.BACDelta[i]) != Utils.Bn.G1.IsZero(pk.BACDelta[i]) {
+			return errors.New("wrong keys divided by δ")
+		}
+	}
+
+	// (Σ r_i K_i, Σ r_i K_i^initial) with (G2, δ G2), for all the keys K divided by δ at once
+	keys := append(append([][3]*big.Int{}, pk.BACDelta...), pk.PowersTauDelta...)
+	initialKeys := append(append([][3]*big.Int{}, initial.Pk.BACDelta...), initial.Pk.PowersTauDelta...)
+	r, err := randScalars(len(keys))
+	if err != nil {
+		return err
+	}
+	if !sameRatio(linearCombination(keys, r), linearCombination(initialKeys, r), Utils.Bn.G2.G, deltaG2) {
+		return errors.New("wrong keys divided by δ")
+	}
+	return nil
+}
+
+// WritePhase2 stores the Phase2 into a json file
+func WritePhase2(path string, p *Phase2) error {
+	jsonData, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, jsonData, 0644)
+}
+
+// ReadPhase2 loads the Phase2 from a json file
+func ReadPhase2(path string) (*Phase2, error) {
+	jsonData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Phase2
+	if err := json.Unmarshal(jsonData, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
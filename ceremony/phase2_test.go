@@ -0,0 +1,161 @@
+package ceremony
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/groth16"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCircuit(t *testing.T) *circuitcompiler.Circuit {
+	// y = x^3 + x + 5
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+	return circuit
+}
+
+func TestPhase2Keys(t *testing.T) {
+	circuit := testCircuit(t)
+	n := len(circuit.R1CS.A)
+
+	transcript, err := NewTranscript(n)
+	assert.Nil(t, err)
+	s := secrets{
+		Tau:   big.NewInt(int64(11)),
+		Alpha: big.NewInt(int64(3)),
+		Beta:  big.NewInt(int64(5)),
+	}
+	assert.Nil(t, transcript.contribute(s, nil, 0))
+
+	phase2, err := NewPhase2(transcript, *circuit)
+	assert.Nil(t, err)
+	setup := phase2.Setup
+
+	// the keys computed in the Lagrange basis must be the evaluations at τ of the QAP polynomials
+	alphas, betas, gammas, z := Utils.PF.R1CSToQAP(circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C)
+	assert.Equal(t, z, setup.Pk.Z)
+	assert.Equal(t, n-1, len(setup.Pk.PowersTauDelta))
+	for i := 0; i < circuit.NVars; i++ {
+		at := Utils.PF.Eval(alphas[i], s.Tau)
+		bt := Utils.PF.Eval(betas[i], s.Tau)
+		ct := Utils.PF.Eval(gammas[i], s.Tau)
+		assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, at), setup.Pk.G1.At[i]))
+		assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bt), setup.Pk.G1.BACGamma[i]))
+		assert.True(t, Utils.Bn.G2.Equal(Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, bt), setup.Pk.G2.BACGamma[i]))
+		bac := Utils.FqR.Add(Utils.FqR.Add(Utils.FqR.Mul(at, s.Beta), Utils.FqR.Mul(bt, s.Alpha)), ct)
+		if i <= circuit.NPublic {
+			assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bac), setup.Vk.IC[i]))
+		} else {
+			assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bac), setup.Pk.BACDelta[i]))
+		}
+	}
+	zt := Utils.PF.Eval(z, s.Tau)
+	assert.True(t, Utils.Bn.G1.Equal(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, Utils.FqR.Mul(zt, s.Tau)), setup.Pk.PowersTauDelta[1]))
+
+	// the powers of tau must be big enough for the circuit
+	small, err := NewTranscript(n - 1)
+	assert.Nil(t, err)
+	_, err = NewPhase2(small, *circuit)
+	assert.NotNil(t, err)
+}
+
+func TestPhase2(t *testing.T) {
+	circuit := testCircuit(t)
+	transcript, err := NewTranscript(len(circuit.R1CS.A) + 1)
+	assert.Nil(t, err)
+	assert.Nil(t, transcript.Contribute())
+
+	phase2, err := NewPhase2(transcript, *circuit)
+	assert.Nil(t, err)
+	assert.NotNil(t, phase2.Verify(transcript, *circuit))
+	assert.Nil(t, phase2.Contribute())
+	assert.Nil(t, phase2.Contribute())
+
+	dir, err := ioutil.TempDir("", "phase2")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "phase2.json")
+	assert.Nil(t, WritePhase2(path, phase2))
+	phase2, err = ReadPhase2(path)
+	assert.Nil(t, err)
+
+	before := time.Now()
+	assert.Nil(t, phase2.Verify(transcript, *circuit))
+	fmt.Println("phase 2 verification of 2 contributions, time elapsed:", time.Since(before))
+
+	// the keys of the ceremony generate valid Groth16 proofs
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	alphas, betas, gammas, _ := Utils.PF.R1CSToQAP(circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C)
+	_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+	proof, err := groth16.GenerateProofs(nil, *circuit, phase2.Setup, w, px)
+	assert.Nil(t, err)
+	verified, err := groth16.VerifyProof(*circuit, phase2.Setup, proof, []*big.Int{big.NewInt(int64(35))}, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	verified, err = groth16.VerifyProof(*circuit, phase2.Setup, proof, []*big.Int{big.NewInt(int64(34))}, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+}
+
+func TestPhase2WrongContributions(t *testing.T) {
+	circuit := testCircuit(t)
+	transcript, err := NewTranscript(len(circuit.R1CS.A))
+	assert.Nil(t, err)
+	assert.Nil(t, transcript.Contribute())
+	phase2, err := NewPhase2(transcript, *circuit)
+	assert.Nil(t, err)
+	assert.Nil(t, phase2.Contribute())
+
+	// δ replaced without updating the keys divided by it
+	wrongDelta := *phase2
+	wrongDelta.Setup.Pk.G1.Delta = Utils.Bn.G1.Double(phase2.Setup.Pk.G1.Delta)
+	assert.NotNil(t, wrongDelta.Verify(transcript, *circuit))
+
+	// key divided by δ replaced
+	wrongKey := *phase2
+	wrongKey.Setup.Pk.PowersTauDelta = append([][3]*big.Int{}, phase2.Setup.Pk.PowersTauDelta...)
+	wrongKey.Setup.Pk.PowersTauDelta[0] = Utils.Bn.G1.G
+	assert.NotNil(t, wrongKey.Verify(transcript, *circuit))
+
+	// key not depending on δ replaced
+	wrongAt := *phase2
+	wrongAt.Setup.Pk.G1.At = append([][3]*big.Int{}, phase2.Setup.Pk.G1.At...)
+	wrongAt.Setup.Pk.G1.At[1] = Utils.Bn.G1.G
+	assert.NotNil(t, wrongAt.Verify(transcript, *circuit))
+
+	// δ of the contribution outside G2
+	wrongG2 := *phase2
+	wrongG2.Contributions = append([]DeltaContribution{}, phase2.Contributions...)
+	wrongG2.Contributions[0].DeltaG2 = notInG2()
+	err = wrongG2.Verify(transcript, *circuit)
+	assert.Equal(t, "ceremony: phase 2: contribution 0: G2 point not in the subgroup of order r", err.Error())
+
+	// contribution with the proof of knowledge of another one
+	assert.Nil(t, phase2.Contribute())
+	wrongProof := *phase2
+	wrongProof.Contributions = append([]DeltaContribution{}, phase2.Contributions...)
+	wrongProof.Contributions[1].Proof = phase2.Contributions[0].Proof
+	assert.NotNil(t, wrongProof.Verify(transcript, *circuit))
+
+	assert.Nil(t, phase2.Verify(transcript, *circuit))
+}
@@ -0,0 +1,181 @@
+package ceremony
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// sameRatio returns true if q1 / p1 == q2 / p2, that is e(p1, q2) == e(q1, p2)
+func sameRatio(p1, q1 [3]*big.Int, p2, q2 [3][2]*big.Int) bool {
+	return Utils.Bn.PairingCheck(
+		[][3]*big.Int{p1, Utils.Bn.G1.Neg(q1)},
+		[][3][2]*big.Int{q2, p2})
+}
+
+// linearCombination returns Σ r[i] * points[i]
+func linearCombination(points [][3]*big.Int, r []*big.Int) [3]*big.Int {
+	res := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := range points {
+		res = Utils.Bn.G1.Add(res, Utils.Bn.G1.MulScalarGLV(points[i], r[i]))
+	}
+	return res
+}
+
+func linearCombinationG2(points [][3][2]*big.Int, r []*big.Int) [3][2]*big.Int {
+	res := Utils.Bn.G2.Zero()
+	for i := range points {
+		res = Utils.Bn.G2.Add(res, Utils.Bn.G2.MulScalar(points[i], r[i]))
+	}
+	return res
+}
+
+// checkPoints returns an error if any of the points is not on the curve, or any of the G2 points is not in the
+// subgroup of order r
+func checkPoints(g1s [][3]*big.Int, g2s [][3][2]*big.Int) error {
+	for _, p := range g1s {
+		if !Utils.Bn.G1.IsOnCurve(p) {
+			return errors.New("point not on the curve")
+		}
+	}
+	for _, p := range g2s {
+		if !Utils.Bn.G2.IsOnCurve(p) {
+			return errors.New("point not on the curve")
+		}
+		if !Utils.Bn.G2.IsInSubgroup(p) {
+			return errors.New("G2 point not in the subgroup of order r")
+		}
+	}
+	return nil
+}
+
+func randScalars(n int) ([]*big.Int, error) {
+	var r []*big.Int
+	for i := 0; i < n; i++ {
+		x, err := Utils.FqR.RandNonZero()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, x)
+	}
+	return r, nil
+}
+
+// verifyContribution checks the Contribution c over the points of the previous Accumulator
+func verifyContribution(prev, c Contribution) error {
+	pk := c.PublicKey
+	if err := checkPoints(
+		[][3]*big.Int{pk.TauG1, pk.AlphaG1, pk.BetaG1, pk.TauProof.R, pk.AlphaProof.R, pk.BetaProof.R, c.TauG1, c.AlphaG1, c.BetaG1},
+		[][3][2]*big.Int{pk.TauG2, pk.AlphaG2, pk.BetaG2, c.BetaG2}); err != nil {
+		return err
+	}
+	if Utils.Bn.G1.IsZero(pk.TauG1) || Utils.Bn.G1.IsZero(pk.AlphaG1) || Utils.Bn.G1.IsZero(pk.BetaG1) {
+		return errors.New("zero secret")
+	}
+	g1s, g2s := prev.boundPoints()
+	if !verifyKnowledge("tau", g1s, g2s, pk.TauG1, pk.TauProof) ||
+		!verifyKnowledge("alpha", g1s, g2s, pk.AlphaG1, pk.AlphaProof) ||
+		!verifyKnowledge("beta", g1s, g2s, pk.BetaG1, pk.BetaProof) {
+		return errors.New("invalid proof of knowledge")
+	}
+	if c.Beacon != nil {
+		// the exponent is checked before hashing, so a malicious transcript can not make the verification hang
+		if err := checkBeaconIterationsExp(c.BeaconIterationsExp); err != nil {
+			return err
+		}
+		s := beaconSecrets(c.Beacon, c.BeaconIterationsExp)
+		if !Utils.Bn.G1.Equal(pk.TauG1, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, s.Tau)) ||
+			!Utils.Bn.G1.Equal(pk.AlphaG1, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, s.Alpha)) ||
+			!Utils.Bn.G1.Equal(pk.BetaG1, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, s.Beta)) {
+			return errors.New("secrets not derived from the beacon")
+		}
+	}
+
+	// the pairs (G1, x G1) and (prev, next) have the same ratio than (G2, x G2), both checked at once with a random
+	// linear combination: (G1 + r prev, x G1 + r next) and (G2, x G2)
+	r, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return err
+	}
+	sameRatioPairs := func(xG1, prevG1, nextG1 [3]*big.Int, xG2 [3][2]*big.Int) bool {
+		return sameRatio(
+			Utils.Bn.G1.Add(Utils.Bn.G1.G, Utils.Bn.G1.MulScalarGLV(prevG1, r)),
+			Utils.Bn.G1.Add(xG1, Utils.Bn.G1.MulScalarGLV(nextG1, r)),
+			Utils.Bn.G2.G, xG2)
+	}
+	if !sameRatioPairs(pk.TauG1, prev.TauG1, c.TauG1, pk.TauG2) {
+		return errors.New("τ not applied")
+	}
+	if !sameRatioPairs(pk.AlphaG1, prev.AlphaG1, c.AlphaG1, pk.AlphaG2) {
+		return errors.New("α not applied")
+	}
+	if !sameRatioPairs(pk.BetaG1, prev.BetaG1, c.BetaG1, pk.BetaG2) {
+		return errors.New("β not applied")
+	}
+	if !sameRatio(Utils.Bn.G1.G, pk.BetaG1, prev.BetaG2, c.BetaG2) {
+		return errors.New("β not applied in G2")
+	}
+	return nil
+}
+
+// verifyAccumulator checks that the Accumulator contains the successive powers of the same τ, with the points of the last contribution
+func (t *Transcript) verifyAccumulator() error {
+	acc := t.Accumulator
+	if len(acc.TauG1) != 2*t.Size-1 || len(acc.TauG2) != t.Size ||
+		len(acc.AlphaTauG1) != t.Size || len(acc.BetaTauG1) != t.Size {
+		return errors.New("wrong accumulator length")
+	}
+	allG1 := append(append(append([][3]*big.Int{}, acc.TauG1...), acc.AlphaTauG1...), acc.BetaTauG1...)
+	if err := checkPoints(allG1, append(append([][3][2]*big.Int{}, acc.TauG2...), acc.BetaG2)); err != nil {
+		return err
+	}
+	last := t.lastPoints()
+	if !Utils.Bn.G1.Equal(acc.TauG1[0], Utils.Bn.G1.G) || !Utils.Bn.G2.Equal(acc.TauG2[0], Utils.Bn.G2.G) {
+		return errors.New("first power is not the generator")
+	}
+	if !Utils.Bn.G1.Equal(acc.TauG1[1], last.TauG1) || !Utils.Bn.G1.Equal(acc.AlphaTauG1[0], last.AlphaG1) ||
+		!Utils.Bn.G1.Equal(acc.BetaTauG1[0], last.BetaG1) || !Utils.Bn.G2.Equal(acc.BetaG2, last.BetaG2) {
+		return errors.New("accumulator does not match the last contribution")
+	}
+
+	// each power is the previous one multiplied by τ, checked at once for all the G1 powers with
+	// a random linear combination: (Σ r_i P_i, Σ r_i P_{i+1}) and (G2, τ G2)
+	var g1s, g1sNext [][3]*big.Int
+	for _, powers := range [][][3]*big.Int{acc.TauG1, acc.AlphaTauG1, acc.BetaTauG1} {
+		g1s = append(g1s, powers[:len(powers)-1]...)
+		g1sNext = append(g1sNext, powers[1:]...)
+	}
+	r, err := randScalars(len(g1s))
+	if err != nil {
+		return err
+	}
+	if !sameRatio(linearCombination(g1s, r), linearCombination(g1sNext, r), Utils.Bn.G2.G, acc.TauG2[1]) {
+		return errors.New("wrong powers of τ in G1")
+	}
+	r, err = randScalars(t.Size - 1)
+	if err != nil {
+		return err
+	}
+	if !sameRatio(Utils.Bn.G1.G, acc.TauG1[1], linearCombinationG2(acc.TauG2[:t.Size-1], r), linearCombinationG2(acc.TauG2[1:], r)) {
+		return errors.New("wrong powers of τ in G2")
+	}
+	return nil
+}
+
+// Verify verifies the whole chain of contributions of the Transcript and its final Accumulator
+func (t *Transcript) Verify() error {
+	if len(t.Contributions) == 0 {
+		return errors.New("ceremony: no contributions")
+	}
+	prev := generatorPoints()
+	for i, c := range t.Contributions {
+		if err := verifyContribution(prev, c); err != nil {
+			return errors.New("ceremony: contribution " + strconv.Itoa(i) + ": " + err.Error())
+		}
+		prev = c
+	}
+	if err := t.verifyAccumulator(); err != nil {
+		return errors.New("ceremony: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package circuitcompiler
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Variable is a handle to a signal (or to a constant value) of a circuit defined with the Builder
+type Variable struct {
+	name string
+}
+
+// Name returns the name of the signal of the Variable, or the decimal value if it is a constant
+func (v Variable) Name() string {
+	return v.name
+}
+
+// IsConstant returns true if the Variable is a constant value
+func (v Variable) IsConstant() bool {
+	isVal, _ := isValue(v.name)
+	return isVal
+}
+
+// Assignment holds the values of the input Variables of a circuit defined with the Builder
+type Assignment map[Variable]*big.Int
+
+// Builder defines a Circuit from Go code, producing the same Circuit that the Parser produces from the equivalent flat code
+type Builder struct {
+	publicInputs  []string
+	privateInputs []string
+	constraints   []Constraint
+	names         map[string]bool
+	nIntermediate int
+	err           error
+}
+
+// NewBuilder creates a new Builder
+func NewBuilder() *Builder {
+	return &Builder{
+		names: map[string]bool{"one": true},
+	}
+}
+
+func (b *Builder) declare(name string) Variable {
+	if b.err != nil {
+		return Variable{name}
+	}
+	if isVal, _ := isValue(name); isVal || name == "" {
+		b.err = errors.New("invalid signal name: " + name)
+	} else if b.names[name] {
+		b.err = errors.New("signal already declared: " + name)
+	}
+	b.names[name] = true
+	return Variable{name}
+}
+
+// PublicInput declares a new public input of the circuit
+func (b *Builder) PublicInput(name string) Variable {
+	v := b.declare(name)
+	b.publicInputs = append(b.publicInputs, name)
+	return v
+}
+
+// PrivateInput declares a new private input of the circuit
+func (b *Builder) PrivateInput(name string) Variable {
+	v := b.declare(name)
+	b.privateInputs = append(b.privateInputs, name)
+	return v
+}
+
+// Constant returns a Variable with the given constant value
+func (b *Builder) Constant(value *big.Int) Variable {
+	return Variable{fqR.Affine(value).String()}
+}
+
+func (b *Builder) intermediate() string {
+	for {
+		name := "v" + strconv.Itoa(b.nIntermediate)
+		b.nIntermediate++
+		if !b.names[name] {
+			b.names[name] = true
+			return name
+		}
+	}
+}
+
+func (b *Builder) operation(op string, x, y Variable) Variable {
+	out := b.intermediate()
+	b.constraints = append(b.constraints, Constraint{
+		Op:      op,
+		V1:      x.name,
+		V2:      y.name,
+		Out:     out,
+		Literal: out + "=" + x.name + op + y.name,
+	})
+	return Variable{out}
+}
+
+// Add returns a new Variable constrained to x + y
+func (b *Builder) Add(x, y Variable) Variable {
+	return b.operation("+", x, y)
+}
+
+// Sub returns a new Variable constrained to x - y
+func (b *Builder) Sub(x, y Variable) Variable {
+	return b.operation("-", x, y)
+}
+
+// Mul returns a new Variable constrained to x * y
+func (b *Builder) Mul(x, y Variable) Variable {
+	return b.operation("*", x, y)
+}
+
+// Div returns a new Variable constrained to x / y
+func (b *Builder) Div(x, y Variable) Variable {
+	return b.operation("/", x, y)
+}
+
+// ToBits returns n new Variables constrained to be the bits of x, starting from the least significant one, where
+// each bit is constrained to be 0 or 1, and x to be equal to the sum of bits[i] * 2^i
+func (b *Builder) ToBits(x Variable, n int) []Variable {
+	if n < 1 {
+		if b.err == nil {
+			b.err = errors.New("invalid number of bits: " + strconv.Itoa(n))
+		}
+		return nil
+	}
+	var bits []Variable
+	var sum Variable
+	for i := 0; i < n; i++ {
+		out := b.intermediate()
+		b.constraints = append(b.constraints, Constraint{
+			Op:      "bit",
+			V1:      x.name,
+			V2:      strconv.Itoa(i),
+			Out:     out,
+			Literal: out + "=bit(" + x.name + ", " + strconv.Itoa(i) + ")",
+		})
+		bits = append(bits, Variable{out})
+		term := b.Mul(b.Constant(new(big.Int).Lsh(big.NewInt(int64(1)), uint(i))), Variable{out})
+		if i == 0 {
+			sum = term
+			continue
+		}
+		sum = b.Add(sum, term)
+	}
+	b.AssertEqual(x, sum)
+	return bits
+}
+
+// AssertEqual constrains x to be equal to y, in the same way than the flat code equals(x, y)
+func (b *Builder) AssertEqual(x, y Variable) {
+	if x.IsConstant() && y.IsConstant() {
+		if x.name != y.name && b.err == nil {
+			b.err = errors.New("constants not equal: " + x.name + " != " + y.name)
+		}
+		return
+	}
+	if !x.IsConstant() {
+		b.constraints = append(b.constraints, Constraint{
+			Op:      "*",
+			V1:      y.name,
+			V2:      "1",
+			Out:     x.name,
+			Literal: "equals(" + x.name + ", " + y.name + "): " + x.name + "==" + y.name + " * 1",
+		})
+	}
+	if !y.IsConstant() {
+		b.constraints = append(b.constraints, Constraint{
+			Op:      "*",
+			V1:      x.name,
+			V2:      "1",
+			Out:     y.name,
+			Literal: "equals(" + x.name + ", " + y.name + "): " + y.name + "==" + x.name + " * 1",
+		})
+	}
+}
+
+// Build returns the Circuit defined in the Builder, with the R1CS already generated.
+// The signals are placed in the order one, public inputs, private inputs, and the rest in the order they were defined.
+func (b *Builder) Build() (*Circuit, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	circ := &Circuit{}
+	circ.Signals = append(circ.Signals, "one")
+	for _, in := range b.publicInputs {
+		circ.Constraints = append(circ.Constraints, Constraint{Op: "in", Out: in})
+		circ.Signals = append(circ.Signals, in)
+		circ.NPublic++
+	}
+	for _, in := range b.privateInputs {
+		circ.Constraints = append(circ.Constraints, Constraint{Op: "in", Out: in})
+		circ.Signals = append(circ.Signals, in)
+	}
+	for _, constraint := range b.constraints {
+		circ.Constraints = append(circ.Constraints, constraint)
+		circ.Signals = addToArrayIfNotExist(circ.Signals, constraint.Out)
+	}
+	circ.PublicInputs = b.publicInputs
+	circ.PrivateInputs = b.privateInputs
+	circ.NVars = len(circ.Signals)
+	circ.NSignals = len(circ.Signals)
+	circ.GenerateR1CS()
+	return circ, nil
+}
+
+// FuncCode returns the flat code of a function with the given name, with the private inputs of the Builder as its
+// parameters and returning out, so the circuit defined in the Builder can be imported from the flat code circuits once
+// registered with RegisterLibrary. The signal names end with an `a`, so they are still unique once the parser adds the
+// calls count at the end when inlining the function.
+func (b *Builder) FuncCode(name string, out Variable) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if len(b.publicInputs) > 0 {
+		return "", errors.New("the inputs of a func must be private: " + b.publicInputs[0])
+	}
+	if len(b.privateInputs) == 0 {
+		return "", errors.New("func without inputs: " + name)
+	}
+	if out.IsConstant() {
+		return "", errors.New("func returning a constant: " + name)
+	}
+	signal := func(v string) string {
+		if isVal, _ := isValue(v); isVal {
+			return v
+		}
+		return v + "a"
+	}
+	var code strings.Builder
+	var params []string
+	for _, in := range b.privateInputs {
+		params = append(params, "private "+signal(in))
+	}
+	code.WriteString("func " + name + "(" + strings.Join(params, ", ") + "):\n")
+	for _, constraint := range b.constraints {
+		code.WriteString("\t" + signal(constraint.Out) + " = " + signal(constraint.V1) + " " + constraint.Op + " " + signal(constraint.V2) + "\n")
+	}
+	// the parser returns the out of the last constraint of the func
+	ret := "out" + name + "a"
+	code.WriteString("\t" + ret + " = " + signal(out.name) + " * 1\n")
+	code.WriteString("\treturn " + ret + "\n")
+	return code.String(), nil
+}
+
+// Inputs returns the private and public inputs of the circuit, in the order expected by Circuit.CalculateWitness
+func (b *Builder) Inputs(assignment Assignment) (Inputs, error) {
+	var inputs Inputs
+	for _, in := range b.privateInputs {
+		value, ok := assignment[Variable{in}]
+		if !ok {
+			return inputs, errors.New("private input not assigned: " + in)
+		}
+		inputs.Private = append(inputs.Private, value)
+	}
+	for _, in := range b.publicInputs {
+		value, ok := assignment[Variable{in}]
+		if !ok {
+			return inputs, errors.New("public input not assigned: " + in)
+		}
+		inputs.Public = append(inputs.Public, value)
+	}
+	return inputs, nil
+}
+
+// Witness calculates the witness of the given Circuit (built with the Builder) from the values assigned to its input Variables
+func (b *Builder) Witness(circ *Circuit, assignment Assignment) ([]*big.Int, error) {
+	inputs, err := b.Inputs(assignment)
+	if err != nil {
+		return nil, err
+	}
+	return circ.CalculateWitness(inputs.Private, inputs.Public)
+}
+
+// Check evaluates the constraints defined in the Builder with the values assigned to the input Variables, returning an
+// error with the first constraint that is not satisfied. Unlike Build and Witness it does not generate the R1CS, so it
+// can be used to check circuits with too many signals for the dense R1CS matrices.
+func (b *Builder) Check(assignment Assignment) error {
+	if b.err != nil {
+		return b.err
+	}
+	inputs, err := b.Inputs(assignment)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]*big.Int)
+	values["one"] = big.NewInt(int64(1))
+	for i, in := range b.publicInputs {
+		values[in] = inputs.Public[i]
+	}
+	for i, in := range b.privateInputs {
+		values[in] = inputs.Private[i]
+	}
+	for _, constraint := range b.constraints {
+		value, err := evalConstraint(values, constraint)
+		if err != nil {
+			return err
+		}
+		if prev, ok := values[constraint.Out]; ok && !fqR.Equal(prev, value) {
+			return errors.New("constraint not satisfied: " + constraint.Literal)
+		}
+		values[constraint.Out] = value
+	}
+	return nil
+}
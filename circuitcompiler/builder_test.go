@@ -0,0 +1,216 @@
+package circuitcompiler
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderSameAsParser(t *testing.T) {
+	// y = x^3 + x + 5
+	flat := `
+	func main(private s0, public s1):
+		v0 = s0 * s0
+		v1 = v0 * s0
+		v2 = v1 + s0
+		v3 = v2 + 5
+		equals(s1, v3)
+	`
+	parser := NewParser(strings.NewReader(flat))
+	parsed, err := parser.Parse()
+	assert.Nil(t, err)
+	parsed.GenerateR1CS()
+
+	b := NewBuilder()
+	x := b.PrivateInput("s0")
+	y := b.PublicInput("s1")
+	x2 := b.Mul(x, x)
+	x3 := b.Mul(x2, x)
+	r := b.Add(b.Add(x3, x), b.Constant(big.NewInt(int64(5))))
+	b.AssertEqual(y, r)
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	assert.Equal(t, parsed.Signals, circuit.Signals)
+	assert.Equal(t, parsed.Constraints, circuit.Constraints)
+	assert.Equal(t, parsed.R1CS, circuit.R1CS)
+	assert.Equal(t, parsed, circuit)
+
+	w, err := b.Witness(circuit, Assignment{
+		x: big.NewInt(int64(3)),
+		y: big.NewInt(int64(35)),
+	})
+	assert.Nil(t, err)
+	wParsed, err := parsed.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	assert.Equal(t, wParsed, w)
+	assert.True(t, circuit.IsSatisfied(w))
+}
+
+func TestBuilderSubDiv(t *testing.T) {
+	// c = (a - b) / (a + b)
+	b := NewBuilder()
+	a := b.PrivateInput("a")
+	bb := b.PrivateInput("b")
+	c := b.PublicInput("c")
+	b.AssertEqual(c, b.Div(b.Sub(a, bb), b.Add(a, bb)))
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "c", "a", "b", "v0", "v1", "v2"}, circuit.Signals)
+	assert.Equal(t, 1, circuit.NPublic)
+
+	// (2 - 5) / (2 + 5) over the field R
+	fq := prepareFqR()
+	cValue := fq.Div(big.NewInt(int64(-3)), big.NewInt(int64(7)))
+	assignment := Assignment{
+		a:  big.NewInt(int64(2)),
+		bb: big.NewInt(int64(5)),
+		c:  cValue,
+	}
+	inputs, err := b.Inputs(assignment)
+	assert.Nil(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(2)), big.NewInt(int64(5))}, inputs.Private)
+	assert.Equal(t, []*big.Int{cValue}, inputs.Public)
+
+	w, err := b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.ConstraintsAfter)
+	w, err = b.Witness(optimized, assignment)
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	// division by zero
+	_, err = b.Witness(circuit, Assignment{
+		a:  big.NewInt(int64(2)),
+		bb: fq.Neg(big.NewInt(int64(2))),
+		c:  cValue,
+	})
+	assert.NotNil(t, err)
+
+	// input not assigned
+	_, err = b.Witness(circuit, Assignment{a: big.NewInt(int64(2))})
+	assert.NotNil(t, err)
+}
+
+func TestBuilderToBits(t *testing.T) {
+	b := NewBuilder()
+	x := b.PrivateInput("x")
+	y := b.PublicInput("y")
+	bits := b.ToBits(x, 4)
+	assert.Equal(t, 4, len(bits))
+	// y = x_3 * x_0
+	b.AssertEqual(y, b.Mul(bits[3], bits[0]))
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	assignment := Assignment{
+		x: big.NewInt(int64(11)),
+		y: big.NewInt(int64(1)),
+	}
+	assert.Nil(t, b.Check(assignment))
+	w, err := b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+	for i, bit := range []int64{1, 1, 0, 1} {
+		assert.Equal(t, big.NewInt(bit), w[indexInArray(circuit.Signals, bits[i].Name())])
+	}
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	// the 4 bits constraints and the multiplication
+	assert.Equal(t, 5, report.ConstraintsAfter)
+	w, err = b.Witness(optimized, assignment)
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	// x does not fit in 4 bits
+	assignment[x] = big.NewInt(int64(27))
+	assert.NotNil(t, b.Check(assignment))
+	w, err = b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	w[2] = big.NewInt(int64(27))
+	assert.False(t, circuit.IsSatisfied(w))
+
+	// wrong public input
+	assignment[x] = big.NewInt(int64(11))
+	assignment[y] = big.NewInt(int64(0))
+	assert.NotNil(t, b.Check(assignment))
+
+	b.ToBits(x, 0)
+	_, err = b.Build()
+	assert.NotNil(t, err)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := NewBuilder()
+	b.PublicInput("a")
+	b.PrivateInput("a")
+	_, err := b.Build()
+	assert.NotNil(t, err)
+
+	b = NewBuilder()
+	b.PublicInput("one")
+	_, err = b.Build()
+	assert.NotNil(t, err)
+
+	b = NewBuilder()
+	b.AssertEqual(b.Constant(big.NewInt(int64(1))), b.Constant(big.NewInt(int64(2))))
+	_, err = b.Build()
+	assert.NotNil(t, err)
+
+	// intermediate names do not collide with the inputs
+	b = NewBuilder()
+	v0 := b.PrivateInput("v0")
+	v := b.Mul(v0, v0)
+	assert.Equal(t, "v1", v.Name())
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "v0", "v1"}, circuit.Signals)
+}
+
+func TestBuilderFuncCode(t *testing.T) {
+	// f(x, k) = (x_0 + x_2) * k, with the bits of x
+	b := NewBuilder()
+	x := b.PrivateInput("x")
+	k := b.PrivateInput("k")
+	bits := b.ToBits(x, 3)
+	out := b.Mul(b.Add(bits[0], bits[2]), k)
+	code, err := b.FuncCode("bitsmul", out)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(code, "func bitsmul(private xa, private ka):\n\tv0a = xa bit 0\n"))
+	RegisterLibrary("bitsmul", code)
+
+	flat := `
+	import "bitsmul"
+	func main(private s0, private s1, public s2):
+		s3 = bitsmul(s0, s1)
+		s4 = bitsmul(s1, s0)
+		s5 = s3 + s4
+		equals(s2, s5)
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	// x = 5: (1 + 1) * 6 + (0 + 1) * 5
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(5)), big.NewInt(int64(6))}, []*big.Int{big.NewInt(int64(17))})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(int64(17)), w[indexInArray(circuit.Signals, "s5")])
+	// without the R1CS the constraints are checked one by one
+	assert.True(t, circuit.IsSatisfied(w))
+	w[indexInArray(circuit.Signals, "s3")] = big.NewInt(int64(13))
+	assert.False(t, circuit.IsSatisfied(w))
+	circuit.GenerateR1CS()
+	assert.False(t, circuit.IsSatisfied(w))
+
+	_, err = b.FuncCode("bitsmul", b.Constant(big.NewInt(int64(1))))
+	assert.NotNil(t, err)
+	b.PublicInput("y")
+	_, err = b.FuncCode("bitsmul", out)
+	assert.NotNil(t, err)
+}
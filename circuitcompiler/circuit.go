@@ -3,11 +3,23 @@ package circuitcompiler
 import (
 	"errors"
 	"math/big"
-	"strconv"
 
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/arnaucube/go-snark/r1csqap"
 )
 
+// fqR is the Finite Field over R (the BN128 scalar field), where the circuit signals take their values
+var fqR = prepareFqR()
+
+func prepareFqR() fields.Fq {
+	f, err := bn128.NewFqR()
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
 // Circuit is the data structure of the compiled circuit
 type Circuit struct {
 	NVars         int
@@ -46,18 +58,17 @@ func indexInArray(arr []string, e string) int {
 	}
 	return -1
 }
-func isValue(a string) (bool, int) {
-	v, err := strconv.Atoi(a)
-	if err != nil {
-		return false, 0
+func isValue(a string) (bool, *big.Int) {
+	v, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		return false, nil
 	}
 	return true, v
 }
 func insertVar(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
 	isVal, value := isValue(v)
-	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		arr[0] = new(big.Int).Add(arr[0], value)
 	} else {
 		if !used[v] {
 			panic(errors.New("using variable before it's set"))
@@ -68,9 +79,8 @@ func insertVar(arr []*big.Int, signals []string, v string, used map[string]bool)
 }
 func insertVarNeg(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
 	isVal, value := isValue(v)
-	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		arr[0] = new(big.Int).Sub(arr[0], value)
 	} else {
 		if !used[v] {
 			panic(errors.New("using variable before it's set"))
@@ -114,7 +124,7 @@ func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
 			bConstraint[0] = big.NewInt(int64(1))
 		} else if constraint.Op == "-" {
 			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
-			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V1, used)
+			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
 			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V2, used)
 			bConstraint[0] = big.NewInt(int64(1))
 		} else if constraint.Op == "*" {
@@ -122,9 +132,15 @@ func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
 			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
 			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
 		} else if constraint.Op == "/" {
+			// out * v2 = v1
 			cConstraint, used = insertVar(cConstraint, circ.Signals, constraint.V1, used)
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			aConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
 			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
+		} else if constraint.Op == "bit" {
+			// out * out = out
+			aConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			bConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
 		}
 
 		a = append(a, aConstraint)
@@ -138,14 +154,16 @@ func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
 	return a, b, c
 }
 
-func grabVar(signals []string, w []*big.Int, vStr string) *big.Int {
+// grabVar returns the value of vStr, which is a constant or a signal of values, and an error if the signal is unknown
+func grabVar(values map[string]*big.Int, vStr string) (*big.Int, error) {
 	isVal, v := isValue(vStr)
-	vBig := big.NewInt(int64(v))
 	if isVal {
-		return vBig
-	} else {
-		return w[indexInArray(signals, vStr)]
+		return v, nil
 	}
+	if value, ok := values[vStr]; ok {
+		return value, nil
+	}
+	return nil, errors.New("unknown signal: " + vStr)
 }
 
 type Inputs struct {
@@ -153,7 +171,8 @@ type Inputs struct {
 	Public  []*big.Int
 }
 
-// CalculateWitness calculates the Witness of a Circuit based on the given inputs
+// CalculateWitness calculates the Witness of a Circuit based on the given inputs, operating over the Finite Field R,
+// so `/` is the division of the Finite Field (not the integer division) and returns an error if the divisor is zero
 // witness = [ one, output, publicInputs, privateInputs, ...]
 func (circ *Circuit) CalculateWitness(privateInputs []*big.Int, publicInputs []*big.Int) ([]*big.Int, error) {
 	if len(privateInputs) != len(circ.PrivateInputs) {
@@ -162,25 +181,147 @@ func (circ *Circuit) CalculateWitness(privateInputs []*big.Int, publicInputs []*
 	if len(publicInputs) != len(circ.PublicInputs) {
 		return []*big.Int{}, errors.New("given publicInputs != circuit.PublicInputs")
 	}
-	w := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-	w[0] = big.NewInt(int64(1))
+	// the values are calculated by signal name, so the witness can be
+	// calculated also for optimized circuits, where some of the signals
+	// used in the Constraints are not part of the circuit Signals
+	values := make(map[string]*big.Int)
+	values["one"] = big.NewInt(int64(1))
 	for i, input := range publicInputs {
-		w[i+1] = input
+		values[circ.PublicInputs[i]] = input
 	}
 	for i, input := range privateInputs {
-		w[i+len(publicInputs)+1] = input
+		values[circ.PrivateInputs[i]] = input
 	}
 	for _, constraint := range circ.Constraints {
 		if constraint.Op == "in" {
-		} else if constraint.Op == "+" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Add(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "-" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Sub(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "*" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Mul(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
-		} else if constraint.Op == "/" {
-			w[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Div(grabVar(circ.Signals, w, constraint.V1), grabVar(circ.Signals, w, constraint.V2))
+			continue
+		}
+		value, err := evalConstraint(values, constraint)
+		if err != nil {
+			return []*big.Int{}, err
+		}
+		values[constraint.Out] = value
+	}
+	w := r1csqap.ArrayOfBigZeros(len(circ.Signals))
+	w[0] = big.NewInt(int64(1))
+	for i := 1; i < len(circ.Signals); i++ {
+		if value, ok := values[circ.Signals[i]]; ok {
+			w[i] = value
 		}
 	}
 	return w, nil
 }
+
+// evalConstraint returns the value of the out signal of the constraint, from the values of its operands
+func evalConstraint(values map[string]*big.Int, constraint Constraint) (*big.Int, error) {
+	v1, err := grabVar(values, constraint.V1)
+	if err != nil {
+		return nil, err
+	}
+	switch constraint.Op {
+	case "bit":
+		// the bit V2 of the value of V1
+		_, i := isValue(constraint.V2)
+		return big.NewInt(int64(fqR.Affine(v1).Bit(int(i.Int64())))), nil
+	case "+", "-", "*", "/":
+	default:
+		return nil, errors.New("unknown operation: " + constraint.Op)
+	}
+	v2, err := grabVar(values, constraint.V2)
+	if err != nil {
+		return nil, err
+	}
+	switch constraint.Op {
+	case "+":
+		return fqR.Add(v1, v2), nil
+	case "-":
+		return fqR.Sub(v1, v2), nil
+	case "*":
+		return fqR.Mul(v1, v2), nil
+	}
+	if fqR.IsZero(fqR.Affine(v2)) {
+		return nil, errors.New("division by zero: " + constraint.Literal)
+	}
+	return fqR.Div(v1, v2), nil
+}
+
+// IsSatisfied returns true if the given witness satisfies all the R1CS constraints of the Circuit, over the Finite Field R.
+// If the R1CS has not been generated, the R1CS constraint of each of the Constraints is checked without building the
+// dense R1CS matrices, so it can be used also for circuits with too many signals for them.
+func (circ *Circuit) IsSatisfied(w []*big.Int) bool {
+	if len(w) != len(circ.Signals) {
+		return false
+	}
+	if circ.R1CS.A == nil {
+		return circ.constraintsSatisfied(w)
+	}
+	for i := 0; i < len(circ.R1CS.A); i++ {
+		a := dotProduct(circ.R1CS.A[i], w)
+		b := dotProduct(circ.R1CS.B[i], w)
+		c := dotProduct(circ.R1CS.C[i], w)
+		if !fqR.Equal(fqR.Mul(a, b), c) {
+			return false
+		}
+	}
+	return true
+}
+
+// constraintsSatisfied returns true if the given witness satisfies the R1CS constraint of each of the Constraints,
+// which are the same constraints that GenerateR1CS generates
+func (circ *Circuit) constraintsSatisfied(w []*big.Int) bool {
+	values := make(map[string]*big.Int)
+	for i, s := range circ.Signals {
+		values[s] = w[i]
+	}
+	for _, constraint := range circ.Constraints {
+		if constraint.Op == "in" {
+			continue
+		}
+		out, err := grabVar(values, constraint.Out)
+		if err != nil {
+			return false
+		}
+		v1, err := grabVar(values, constraint.V1)
+		if err != nil {
+			return false
+		}
+		v2 := big.NewInt(int64(0))
+		if constraint.Op != "bit" {
+			if v2, err = grabVar(values, constraint.V2); err != nil {
+				return false
+			}
+		}
+		var a, b, c *big.Int
+		switch constraint.Op {
+		case "+":
+			a, b, c = fqR.Add(v1, v2), big.NewInt(int64(1)), out
+		case "-":
+			a, b, c = fqR.Sub(v1, v2), big.NewInt(int64(1)), out
+		case "*":
+			a, b, c = v1, v2, out
+		case "/":
+			// out * v2 = v1
+			a, b, c = out, v2, v1
+		case "bit":
+			// out * out = out
+			a, b, c = out, out, out
+		default:
+			return false
+		}
+		if !fqR.Equal(fqR.Mul(a, b), c) {
+			return false
+		}
+	}
+	return true
+}
+
+func dotProduct(row, w []*big.Int) *big.Int {
+	r := big.NewInt(int64(0))
+	for i := 0; i < len(row); i++ {
+		if row[i].Sign() == 0 {
+			continue
+		}
+		r = fqR.Add(r, fqR.Mul(row[i], w[i]))
+	}
+	return r
+}
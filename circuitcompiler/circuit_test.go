@@ -246,3 +246,173 @@ func TestCircuitFromFileWithImports(t *testing.T) {
 	assert.Equal(t, len(circuit.PublicInputs), 1)
 	assert.Equal(t, len(circuit.PrivateInputs), 1)
 }
+
+func TestCircuitSubR1CS(t *testing.T) {
+	// y = (x - z) * (x - 3)
+	flat := `
+	func main(private s0, private s1, public s2):
+		s3 = s0 - s1
+		s4 = s0 - 3
+		s5 = s3 * s4
+		equals(s2, s5)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, _, _ := circuit.GenerateR1CS()
+	assert.Equal(t, []string{"one", "s2", "s0", "s1", "s3", "s4", "s5", "out"}, circuit.Signals)
+	// s3 = s0 - s1: the first operand is added and the second one subtracted
+	assert.Equal(t, big.NewInt(int64(1)), a[0][2])
+	assert.Equal(t, big.NewInt(int64(-1)), a[0][3])
+	// s4 = s0 - 3: the constant is subtracted
+	assert.Equal(t, big.NewInt(int64(1)), a[1][2])
+	assert.Equal(t, big.NewInt(int64(-3)), a[1][0])
+
+	// (7 - 2) * (7 - 3)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(7)), big.NewInt(int64(2))}, []*big.Int{big.NewInt(int64(20))})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+	w[6] = big.NewInt(int64(21))
+	assert.False(t, circuit.IsSatisfied(w))
+}
+
+func TestCircuitDivR1CS(t *testing.T) {
+	// y = x / z
+	flat := `
+	func main(private s0, private s1, public s2):
+		s3 = s0 / s1
+		equals(s2, s3)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	assert.Equal(t, []string{"one", "s2", "s0", "s1", "s3", "out"}, circuit.Signals)
+	// s3 = s0 / s1 is constrained as s3 * s1 = s0
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	assert.Equal(t, []*big.Int{b0, b0, b0, b0, b1, b0}, a[0])
+	assert.Equal(t, []*big.Int{b0, b0, b0, b1, b0, b0}, b[0])
+	assert.Equal(t, []*big.Int{b0, b0, b1, b0, b0, b0}, c[0])
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(12)), big.NewInt(int64(4))}, []*big.Int{big.NewInt(int64(3))})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+	w[4] = big.NewInt(int64(4))
+	assert.False(t, circuit.IsSatisfied(w))
+}
+
+func TestCircuitWitnessBySignalName(t *testing.T) {
+	flat := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		equals(s1, s3)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	// a circuit whose Constraints use a signal that is not in its Signals, as s2 here
+	circuit.Signals = []string{"one", "s1", "s0", "s3", "out"}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(27))})
+	assert.Nil(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(1)), big.NewInt(int64(27)), big.NewInt(int64(3)), big.NewInt(int64(27)), big.NewInt(int64(1))}, w)
+}
+
+func TestCircuitWitnessUnknownSignal(t *testing.T) {
+	flat := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		equals(s1, s3)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	// a constraint using the signal s9, which is never set
+	circuit.Constraints = append(circuit.Constraints, Constraint{Op: "*", V1: "s9", V2: "s0", Out: "s4", Literal: "s4 = s9 * s0"})
+	_, err = circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(27))})
+	assert.Equal(t, "unknown signal: s9", err.Error())
+}
+
+func TestCircuitImportAfterFunc(t *testing.T) {
+	// the funcs declared before an import are kept
+	flat := `
+	func double(private a):
+		b = a + a
+		return b
+
+	import "circuit-test-2.circuit"
+
+	func main(private s0, public s1):
+		s2 = double(s0)
+		s3 = exp3(s2)
+		equals(s1, s3)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(2))}, []*big.Int{big.NewInt(int64(64))})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+}
+
+func TestCircuitEqualsValue(t *testing.T) {
+	// equals with a value only constrains the signal, a value is never the out of a constraint
+	flat := `
+	func main(private s0):
+		s1 = s0 * s0
+		equals(s1, 9)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	for _, constraint := range circuit.Constraints {
+		assert.NotEqual(t, "9", constraint.Out)
+	}
+	circuit.GenerateR1CS()
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+	w, err = circuit.CalculateWitness([]*big.Int{big.NewInt(int64(4))}, []*big.Int{})
+	assert.Nil(t, err)
+	assert.False(t, circuit.IsSatisfied(w))
+}
+
+func TestCircuitNonExactDivision(t *testing.T) {
+	flat := `
+	func main(private s0, private s1):
+		s2 = s0 / s1
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+	assert.Equal(t, []string{"one", "s0", "s1", "s2", "out"}, circuit.Signals)
+
+	// 7 / 2 is 7 * 2^-1 over the Finite Field R, not the integer division 3
+	b2 := big.NewInt(int64(2))
+	b7 := big.NewInt(int64(7))
+	quotient := fqR.Div(b7, b2)
+	assert.Equal(t, b7, fqR.Mul(quotient, b2))
+	w, err := circuit.CalculateWitness([]*big.Int{b7, b2}, []*big.Int{})
+	assert.Nil(t, err)
+	assert.Equal(t, quotient, w[3])
+	assert.True(t, circuit.IsSatisfied(w))
+
+	w[3] = big.NewInt(int64(3))
+	assert.False(t, circuit.IsSatisfied(w))
+
+	_, err = circuit.CalculateWitness([]*big.Int{b7, big.NewInt(int64(0))}, []*big.Int{})
+	assert.Equal(t, "division by zero: s2=s0/s1", err.Error())
+}
@@ -0,0 +1,357 @@
+package circuitcompiler
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/arnaucube/go-snark/r1csqap"
+)
+
+// OptimizationReport contains the number of constraints and signals of the Circuit before and after the optimization
+type OptimizationReport struct {
+	ConstraintsBefore int
+	ConstraintsAfter  int
+	SignalsBefore     int
+	SignalsAfter      int
+}
+
+func (r OptimizationReport) String() string {
+	return fmt.Sprintf("constraints: %d -> %d, signals: %d -> %d",
+		r.ConstraintsBefore, r.ConstraintsAfter, r.SignalsBefore, r.SignalsAfter)
+}
+
+// linearCombination maps the signal index to its coefficient, the index 0 is the 'one' signal
+type linearCombination map[int]*big.Int
+
+// r1csRow is a constraint in the form a * b = c, where a, b and c are linear combinations of signals
+type r1csRow struct {
+	a, b, c linearCombination
+}
+
+func (lc linearCombination) add(i int, coef *big.Int) {
+	v := fqR.Affine(coef)
+	if old, ok := lc[i]; ok {
+		v = fqR.Add(old, v)
+	}
+	if fqR.IsZero(v) {
+		delete(lc, i)
+		return
+	}
+	lc[i] = v
+}
+
+// addTerm adds a flat code value (a literal or a signal name) to the linear combination
+func (lc linearCombination) addTerm(signalIndex map[string]int, v string, coef *big.Int) error {
+	isVal, value := isValue(v)
+	if isVal {
+		lc.add(0, fqR.Mul(coef, value))
+		return nil
+	}
+	i, ok := signalIndex[v]
+	if !ok {
+		return errors.New("signal not found: " + v)
+	}
+	lc.add(i, coef)
+	return nil
+}
+
+// isConstant returns true if the linear combination only depends on the 'one' signal
+func (lc linearCombination) isConstant() bool {
+	for i := range lc {
+		if i != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (lc linearCombination) constant() *big.Int {
+	if v, ok := lc[0]; ok {
+		return v
+	}
+	return fqR.Zero()
+}
+
+// substitute replaces the signal s by the linear combination expr
+func (lc linearCombination) substitute(s int, expr linearCombination) {
+	coef, ok := lc[s]
+	if !ok {
+		return
+	}
+	delete(lc, s)
+	for i, v := range expr {
+		lc.add(i, fqR.Mul(coef, v))
+	}
+}
+
+func (lc linearCombination) key() string {
+	var idx []int
+	for i := range lc {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	var parts []string
+	for _, i := range idx {
+		parts = append(parts, fmt.Sprintf("%d:%s", i, lc[i].String()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// linear returns the linear combination l such that the row is equivalent to l = 0, and false if the row is not linear
+func (row r1csRow) linear() (linearCombination, bool) {
+	var l linearCombination
+	if row.a.isConstant() {
+		l = scaled(row.b, row.a.constant())
+	} else if row.b.isConstant() {
+		l = scaled(row.a, row.b.constant())
+	} else {
+		return nil, false
+	}
+	for i, v := range row.c {
+		l.add(i, fqR.Neg(v))
+	}
+	return l, true
+}
+
+func scaled(lc linearCombination, k *big.Int) linearCombination {
+	r := make(linearCombination)
+	for i, v := range lc {
+		r.add(i, fqR.Mul(v, k))
+	}
+	return r
+}
+
+// Optimize performs an optimization pass over the Circuit Constraints, returning a new Circuit with the optimized R1CS.
+// The linear constraints (additions, subtractions, multiplications by a constant and equals) are merged into the
+// multiplications that use them, the duplicated and trivially satisfied constraints are removed, and the unused
+// intermediate signals are eliminated. The inputs of the circuit are kept in the same positions.
+// The R1CS of the returned Circuit is already generated, so GenerateR1CS must not be called over it, and its
+// Witness is calculated with CalculateWitness as usual.
+func (circ *Circuit) Optimize() (*Circuit, OptimizationReport, error) {
+	var report OptimizationReport
+	report.SignalsBefore = len(circ.Signals)
+
+	signalIndex := make(map[string]int)
+	for i, s := range circ.Signals {
+		signalIndex[s] = i
+	}
+	// the 'one' signal and the inputs can not be eliminated
+	fixed := make(map[int]bool)
+	fixed[0] = true
+	for _, in := range circ.PublicInputs {
+		fixed[signalIndex[in]] = true
+	}
+	for _, in := range circ.PrivateInputs {
+		fixed[signalIndex[in]] = true
+	}
+
+	// from flat code to sparse R1CS rows, in the same way than GenerateR1CS
+	b1 := big.NewInt(int64(1))
+	bNeg1 := big.NewInt(int64(-1))
+	var rows []*r1csRow
+	// division rows out * v2 = v1 also constrain v2 != 0 unless v1 = 0, so they are never removed as unused
+	var division []bool
+	for _, constraint := range circ.Constraints {
+		if constraint.Op == "in" {
+			continue
+		}
+		row := &r1csRow{make(linearCombination), make(linearCombination), make(linearCombination)}
+		var err error
+		switch constraint.Op {
+		case "+":
+			err = firstErr(
+				row.a.addTerm(signalIndex, constraint.V1, b1),
+				row.a.addTerm(signalIndex, constraint.V2, b1),
+				row.c.addTerm(signalIndex, constraint.Out, b1))
+			row.b.add(0, b1)
+		case "-":
+			err = firstErr(
+				row.a.addTerm(signalIndex, constraint.V1, b1),
+				row.a.addTerm(signalIndex, constraint.V2, bNeg1),
+				row.c.addTerm(signalIndex, constraint.Out, b1))
+			row.b.add(0, b1)
+		case "*":
+			err = firstErr(
+				row.a.addTerm(signalIndex, constraint.V1, b1),
+				row.b.addTerm(signalIndex, constraint.V2, b1),
+				row.c.addTerm(signalIndex, constraint.Out, b1))
+		case "/":
+			err = firstErr(
+				row.a.addTerm(signalIndex, constraint.Out, b1),
+				row.b.addTerm(signalIndex, constraint.V2, b1),
+				row.c.addTerm(signalIndex, constraint.V1, b1))
+		case "bit":
+			err = firstErr(
+				row.a.addTerm(signalIndex, constraint.Out, b1),
+				row.b.addTerm(signalIndex, constraint.Out, b1),
+				row.c.addTerm(signalIndex, constraint.Out, b1))
+		default:
+			err = errors.New("unknown operation: " + constraint.Op)
+		}
+		if err != nil {
+			return nil, report, err
+		}
+		rows = append(rows, row)
+		division = append(division, constraint.Op == "/")
+	}
+	report.ConstraintsBefore = len(rows)
+
+	removed := make([]bool, len(rows))
+	eliminated := make(map[int]bool)
+
+	// merge the linear constraints into the other constraints, eliminating one intermediate signal for each of them
+	for changed := true; changed; {
+		changed = false
+		for i, row := range rows {
+			if removed[i] {
+				continue
+			}
+			l, ok := row.linear()
+			if !ok {
+				continue
+			}
+			// take the last declared intermediate signal of the linear combination
+			s := -1
+			for j := range l {
+				if !fixed[j] && j > s {
+					s = j
+				}
+			}
+			if s == -1 {
+				continue
+			}
+			// s = -(l - coef*s) / coef
+			k := fqR.Neg(fqR.Inverse(l[s]))
+			delete(l, s)
+			expr := scaled(l, k)
+			for j, other := range rows {
+				if removed[j] || j == i {
+					continue
+				}
+				other.a.substitute(s, expr)
+				other.b.substitute(s, expr)
+				other.c.substitute(s, expr)
+			}
+			removed[i] = true
+			eliminated[s] = true
+			changed = true
+		}
+	}
+
+	// remove the trivially satisfied and the duplicated constraints
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if removed[i] {
+			continue
+		}
+		if l, ok := row.linear(); ok && len(l) == 0 {
+			removed[i] = true
+			continue
+		}
+		a := row.a.key()
+		b := row.b.key()
+		c := row.c.key()
+		if seen[a+"|"+b+"|"+c] || seen[b+"|"+a+"|"+c] {
+			removed[i] = true
+			continue
+		}
+		seen[a+"|"+b+"|"+c] = true
+	}
+
+	// remove the constraints that only define an intermediate signal not used anywhere else,
+	// as any value of the signal satisfies them, except the divisions
+	for changed := true; changed; {
+		changed = false
+		occurrences := make(map[int]int)
+		for i, row := range rows {
+			if removed[i] {
+				continue
+			}
+			for _, lc := range []linearCombination{row.a, row.b, row.c} {
+				for j := range lc {
+					occurrences[j]++
+				}
+			}
+		}
+		for i, row := range rows {
+			if removed[i] || division[i] {
+				continue
+			}
+			for j := range row.c {
+				if !fixed[j] && occurrences[j] == 1 {
+					removed[i] = true
+					eliminated[j] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// keep only the signals that are still used
+	used := make(map[int]bool)
+	for i, row := range rows {
+		if removed[i] {
+			continue
+		}
+		for _, lc := range []linearCombination{row.a, row.b, row.c} {
+			for j := range lc {
+				used[j] = true
+			}
+		}
+	}
+	var signals []string
+	newIndex := make(map[int]int)
+	for i, s := range circ.Signals {
+		if fixed[i] || (used[i] && !eliminated[i]) {
+			newIndex[i] = len(signals)
+			signals = append(signals, s)
+		}
+	}
+
+	optimized := &Circuit{
+		NPublic:       circ.NPublic,
+		PrivateInputs: circ.PrivateInputs,
+		PublicInputs:  circ.PublicInputs,
+		Signals:       signals,
+		Constraints:   circ.Constraints,
+	}
+	optimized.NVars = len(signals)
+	optimized.NSignals = len(signals)
+	// the R1CS is not nil even without constraints, as the Constraints of the optimized circuit use removed signals
+	optimized.R1CS.A = [][]*big.Int{}
+	optimized.R1CS.B = [][]*big.Int{}
+	optimized.R1CS.C = [][]*big.Int{}
+	for i, row := range rows {
+		if removed[i] {
+			continue
+		}
+		optimized.R1CS.A = append(optimized.R1CS.A, row.a.dense(newIndex, len(signals)))
+		optimized.R1CS.B = append(optimized.R1CS.B, row.b.dense(newIndex, len(signals)))
+		optimized.R1CS.C = append(optimized.R1CS.C, row.c.dense(newIndex, len(signals)))
+	}
+
+	report.ConstraintsAfter = len(optimized.R1CS.A)
+	report.SignalsAfter = len(signals)
+	return optimized, report, nil
+}
+
+func (lc linearCombination) dense(newIndex map[int]int, n int) []*big.Int {
+	r := r1csqap.ArrayOfBigZeros(n)
+	for i, v := range lc {
+		r[newIndex[i]] = v
+	}
+	return r
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
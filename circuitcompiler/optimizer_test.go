@@ -0,0 +1,160 @@
+package circuitcompiler
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimize(t *testing.T) {
+	// y = x^3 + x + 5
+	flat := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	fmt.Println("optimization:", report)
+	assert.Equal(t, OptimizationReport{
+		ConstraintsBefore: 7,
+		ConstraintsAfter:  2,
+		SignalsBefore:     8,
+		SignalsAfter:      4,
+	}, report)
+	assert.Equal(t, []string{"one", "s1", "s0", "s2"}, optimized.Signals)
+	assert.Equal(t, 4, optimized.NVars)
+	assert.Equal(t, 1, optimized.NPublic)
+	assert.Equal(t, 2, len(optimized.R1CS.A))
+	// the original circuit is not modified
+	assert.Equal(t, 8, len(circuit.Signals))
+
+	b3 := big.NewInt(int64(3))
+	b9 := big.NewInt(int64(9))
+	b35 := big.NewInt(int64(35))
+	w, err := optimized.CalculateWitness([]*big.Int{b3}, []*big.Int{b35})
+	assert.Nil(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(1)), b35, b3, b9}, w)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	// wrong public signal
+	w[1] = big.NewInt(int64(34))
+	assert.False(t, optimized.IsSatisfied(w))
+}
+
+func TestOptimizeWithFuncCalls(t *testing.T) {
+	code := `
+		func exp3(private a):
+			b = a * a
+			c = a * b
+			return c
+		func sum(private a, private b):
+			c = a + b
+			return c
+
+		func main(private s0, public s1):
+			s3 = exp3(s0)
+			s4 = sum(s3, s0)
+			s5 = s4 + 5
+			equals(s1, s5)
+			out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, report.ConstraintsBefore)
+	assert.Equal(t, 2, report.ConstraintsAfter)
+
+	w, err := optimized.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+}
+
+func TestOptimizeLinearCombinations(t *testing.T) {
+	// c = (a - b) * (a + 3) - 2*a, with duplicated operations
+	code := `
+	func main(private a, private b, public c):
+		d = a - b
+		e = a + 3
+		f = d * e
+		g = e * d
+		h = a * 2
+		i = f - h
+		j = g - h
+		equals(c, i)
+		equals(c, j)
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	circuit.GenerateR1CS()
+	a := big.NewInt(int64(7))
+	b := big.NewInt(int64(2))
+	// (7 - 2) * (7 + 3) - 2*7 = 36
+	c := big.NewInt(int64(36))
+	w, err := circuit.CalculateWitness([]*big.Int{a, b}, []*big.Int{c})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	fmt.Println("optimization:", report)
+	assert.Equal(t, 12, report.ConstraintsBefore)
+	assert.Equal(t, 1, report.ConstraintsAfter)
+	assert.Equal(t, []string{"one", "c", "a", "b"}, optimized.Signals)
+
+	w, err = optimized.CalculateWitness([]*big.Int{a, b}, []*big.Int{c})
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	w[1] = big.NewInt(int64(35))
+	assert.False(t, optimized.IsSatisfied(w))
+}
+
+func TestOptimizeKeepsDivisions(t *testing.T) {
+	// the quotient is not used, but the division constrains the divisor to not be zero
+	code := `
+	func main(private s0, private s1):
+		s2 = s0 + 1
+		s3 = s2 / s1
+		out = 1 * 1
+	`
+	parser := NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.ConstraintsAfter)
+	assert.Equal(t, []string{"one", "s0", "s1", "s3"}, optimized.Signals)
+
+	b0 := big.NewInt(int64(0))
+	b3 := big.NewInt(int64(3))
+	w, err := optimized.CalculateWitness([]*big.Int{b3, big.NewInt(int64(2))}, []*big.Int{})
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	// with a zero divisor there is no witness
+	_, err = optimized.CalculateWitness([]*big.Int{b3, b0}, []*big.Int{})
+	assert.NotNil(t, err)
+	for _, quotient := range []*big.Int{b0, b3, w[3]} {
+		w = []*big.Int{big.NewInt(int64(1)), b3, b0, quotient}
+		assert.False(t, optimized.IsSatisfied(w))
+	}
+}
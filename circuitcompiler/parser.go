@@ -205,17 +205,68 @@ func subsIfInMap(original string, m map[string]string) string {
 	return original
 }
 
+// renameSignal returns the unique name of a signal of a called function, the values are not renamed
+func renameSignal(original string, callsCountStr string, m map[string]string) string {
+	if isVal, _ := isValue(original); isVal {
+		return original
+	}
+	return subsIfInMap(original+callsCountStr, m)
+}
+
+var libraries = make(map[string]func() string)
+
+// RegisterLibrary registers the flat code of a library of functions, that can be imported from
+// the circuits by its name (`import "name"`) without the need of a file
+func RegisterLibrary(name string, code string) {
+	libraries[name] = func() string {
+		return code
+	}
+}
+
+// RegisterLibraryFunc registers a library of functions like RegisterLibrary, where the flat code is returned by the
+// given function when the library is imported, for the libraries whose code is too big to be generated in advance
+func RegisterLibraryFunc(name string, code func() string) {
+	libraries[name] = code
+}
+
 var circuits map[string]*Circuit
 
+// circuitSignals holds the set of the signals of each circuit of the `circuits` map, so adding a signal does not
+// need to go through all the signals of the circuit
+var circuitSignals map[string]map[string]bool
+
+// addSignal adds the signal to the Signals of the circuit if it is not already there
+func addSignal(circuit string, signal string) {
+	set, ok := circuitSignals[circuit]
+	if !ok {
+		set = make(map[string]bool)
+		for _, s := range circuits[circuit].Signals {
+			set[s] = true
+		}
+		circuitSignals[circuit] = set
+	}
+	if set[signal] {
+		return
+	}
+	set[signal] = true
+	circuits[circuit].Signals = append(circuits[circuit].Signals, signal)
+}
+
 // Parse parses the lines and returns the compiled Circuit
 func (p *Parser) Parse() (*Circuit, error) {
 	// funcsMap is a map holding the functions names and it's content as Circuit
 	circuits = make(map[string]*Circuit)
-	mainExist := false
+	circuitSignals = make(map[string]map[string]bool)
 	circuits["main"] = &Circuit{}
-	callsCount := 0
-
 	circuits["main"].Signals = append(circuits["main"].Signals, "one")
+	return p.parse()
+}
+
+// parse parses the lines adding the declared funcs into the `circuits` map, it is
+// also used for the imports, so the funcs already declared are kept
+func (p *Parser) parse() (*Circuit, error) {
+	mainExist := false
+	callsCount := 0
 	nInputs := 0
 	currCircuit := ""
 	for {
@@ -229,6 +280,7 @@ func (p *Parser) Parse() (*Circuit, error) {
 			if constraint.V1 != "main" {
 				currCircuit = constraint.V1
 				circuits[currCircuit] = &Circuit{}
+				delete(circuitSignals, currCircuit)
 				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constraint)
 				continue
 			}
@@ -245,7 +297,7 @@ func (p *Parser) Parse() (*Circuit, error) {
 				}
 				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *newConstr)
 				nInputs++
-				circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, in)
+				addSignal(currCircuit, in)
 				circuits[currCircuit].NPublic++
 			}
 			for _, in := range constraint.PrivateInputs {
@@ -255,29 +307,34 @@ func (p *Parser) Parse() (*Circuit, error) {
 				}
 				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *newConstr)
 				nInputs++
-				circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, in)
+				addSignal(currCircuit, in)
 			}
 			circuits[currCircuit].PublicInputs = constraint.PublicInputs
 			circuits[currCircuit].PrivateInputs = constraint.PrivateInputs
 			continue
 		}
 		if constraint.Literal == "equals" {
-			constr1 := &Constraint{
-				Op:      "*",
-				V1:      constraint.V2,
-				V2:      "1",
-				Out:     constraint.V1,
-				Literal: "equals(" + constraint.V1 + ", " + constraint.V2 + "): " + constraint.V1 + "==" + constraint.V2 + " * 1",
+			// a value can not be the out of a constraint, so when comparing with a value only one constraint is added
+			if isVal, _ := isValue(constraint.V1); !isVal {
+				constr1 := &Constraint{
+					Op:      "*",
+					V1:      constraint.V2,
+					V2:      "1",
+					Out:     constraint.V1,
+					Literal: "equals(" + constraint.V1 + ", " + constraint.V2 + "): " + constraint.V1 + "==" + constraint.V2 + " * 1",
+				}
+				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constr1)
 			}
-			circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constr1)
-			constr2 := &Constraint{
-				Op:      "*",
-				V1:      constraint.V1,
-				V2:      "1",
-				Out:     constraint.V2,
-				Literal: "equals(" + constraint.V1 + ", " + constraint.V2 + "): " + constraint.V2 + "==" + constraint.V1 + " * 1",
+			if isVal, _ := isValue(constraint.V2); !isVal {
+				constr2 := &Constraint{
+					Op:      "*",
+					V1:      constraint.V1,
+					V2:      "1",
+					Out:     constraint.V2,
+					Literal: "equals(" + constraint.V1 + ", " + constraint.V2 + "): " + constraint.V2 + "==" + constraint.V1 + " * 1",
+				}
+				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constr2)
 			}
-			circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constr2)
 			continue
 		}
 		if constraint.Literal == "return" {
@@ -301,42 +358,51 @@ func (p *Parser) Parse() (*Circuit, error) {
 				// add constraint, puting unique names to vars
 				nc := &Constraint{
 					Op:      c.Op,
-					V1:      subsIfInMap(c.V1+callsCountStr, signalMap),
-					V2:      subsIfInMap(c.V2+callsCountStr, signalMap),
-					Out:     subsIfInMap(c.Out+callsCountStr, signalMap),
+					V1:      renameSignal(c.V1, callsCountStr, signalMap),
+					V2:      renameSignal(c.V2, callsCountStr, signalMap),
+					Out:     renameSignal(c.Out, callsCountStr, signalMap),
 					Literal: "",
 				}
 				nc.Literal = nc.Out + "=" + nc.V1 + nc.Op + nc.V2
 				circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *nc)
 			}
 			for _, s := range circuits[constraint.Op].Signals {
-				circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, subsIfInMap(s+callsCountStr, signalMap))
+				s = renameSignal(s, callsCountStr, signalMap)
+				if isVal, _ := isValue(s); isVal {
+					continue
+				}
+				addSignal(currCircuit, s)
 			}
 			callsCount++
 			continue
 
 		}
 		if constraint.Literal == "import" {
-			circuitFile, err := os.Open(constraint.Out)
-			if err != nil {
-				panic(errors.New("imported path error: " + constraint.Out))
+			var parser *Parser
+			if code, ok := libraries[constraint.Out]; ok {
+				parser = NewParser(strings.NewReader(code()))
+			} else {
+				circuitFile, err := os.Open(constraint.Out)
+				if err != nil {
+					panic(errors.New("imported path error: " + constraint.Out))
+				}
+				parser = NewParser(bufio.NewReader(circuitFile))
 			}
-			parser := NewParser(bufio.NewReader(circuitFile))
-			_, err = parser.Parse() // this will add the imported file funcs into the `circuits` map
+			_, err = parser.parse() // this will add the imported file funcs into the `circuits` map
 			continue
 		}
 
 		circuits[currCircuit].Constraints = append(circuits[currCircuit].Constraints, *constraint)
 		isVal, _ := isValue(constraint.V1)
 		if !isVal {
-			circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, constraint.V1)
+			addSignal(currCircuit, constraint.V1)
 		}
 		isVal, _ = isValue(constraint.V2)
 		if !isVal {
-			circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, constraint.V2)
+			addSignal(currCircuit, constraint.V2)
 		}
 
-		circuits[currCircuit].Signals = addToArrayIfNotExist(circuits[currCircuit].Signals, constraint.Out)
+		addSignal(currCircuit, constraint.Out)
 	}
 	circuits["main"].NVars = len(circuits["main"].Signals)
 	circuits["main"].NSignals = len(circuits["main"].Signals)
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/ceremony"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/urfave/cli"
+)
+
+const powersOfTauFile = "powersoftau.json"
+
+var ceremonyCommands = []cli.Command{
+	{
+		Name:      "new",
+		Aliases:   []string{},
+		Usage:     "initialize the powers of tau for circuits up to the given number of constraints",
+		ArgsUsage: "<size> [out]",
+		Action:    CeremonyNew,
+	},
+	{
+		Name:      "contribute",
+		Aliases:   []string{},
+		Usage:     "add a random contribution to the powers of tau",
+		ArgsUsage: "[in] [out]",
+		Action:    CeremonyContribute,
+	},
+	{
+		Name:      "beacon",
+		Aliases:   []string{},
+		Usage:     "apply the random beacon (hex value, hashed 2^iterationsExp times, iterationsExp up to 42) as the final contribution",
+		ArgsUsage: "<beacon> <iterationsExp> [in] [out]",
+		Action:    CeremonyBeacon,
+	},
+	{
+		Name:      "verify",
+		Aliases:   []string{},
+		Usage:     "verify the chain of contributions of the powers of tau",
+		ArgsUsage: "[in]",
+		Action:    CeremonyVerify,
+	},
+}
+
+// argOrDefault returns the argument at the given position, or the default value if it is not given
+func argOrDefault(context *cli.Context, i int, def string) string {
+	if context.NArg() > i {
+		return context.Args().Get(i)
+	}
+	return def
+}
+
+func CeremonyNew(context *cli.Context) error {
+	size, err := strconv.Atoi(context.Args().Get(0))
+	if err != nil {
+		return errors.New("size of the powers of tau not given")
+	}
+	transcript, err := ceremony.NewTranscript(size)
+	panicErr(err)
+
+	out := argOrDefault(context, 1, powersOfTauFile)
+	panicErr(ceremony.WriteTranscript(out, transcript))
+	fmt.Println("Powers of tau data written to ", out)
+	return nil
+}
+
+func CeremonyContribute(context *cli.Context) error {
+	in := argOrDefault(context, 0, powersOfTauFile)
+	transcript, err := ceremony.ReadTranscript(in)
+	panicErr(err)
+	panicErr(transcript.Contribute())
+
+	out := argOrDefault(context, 1, in)
+	panicErr(ceremony.WriteTranscript(out, transcript))
+	fmt.Println("contribution", len(transcript.Contributions), "added, powers of tau data written to ", out)
+	return nil
+}
+
+func CeremonyBeacon(context *cli.Context) error {
+	beacon, err := hex.DecodeString(context.Args().Get(0))
+	if err != nil || len(beacon) == 0 {
+		return errors.New("beacon hex value not given")
+	}
+	iterationsExp, err := strconv.Atoi(context.Args().Get(1))
+	if err != nil {
+		return errors.New("beacon iterations exponent not given")
+	}
+	in := argOrDefault(context, 2, powersOfTauFile)
+	transcript, err := ceremony.ReadTranscript(in)
+	panicErr(err)
+	panicErr(transcript.ApplyBeacon(beacon, iterationsExp))
+
+	out := argOrDefault(context, 3, in)
+	panicErr(ceremony.WriteTranscript(out, transcript))
+	fmt.Println("beacon applied, powers of tau data written to ", out)
+	return nil
+}
+
+func CeremonyVerify(context *cli.Context) error {
+	in := argOrDefault(context, 0, powersOfTauFile)
+	transcript, err := ceremony.ReadTranscript(in)
+	panicErr(err)
+	if err := transcript.Verify(); err != nil {
+		fmt.Println("❌ powers of tau verification not passed:", err)
+		return err
+	}
+	fmt.Println("✓ powers of tau verification passed,", len(transcript.Contributions), "contributions")
+	return nil
+}
+
+const phase2File = "phase2.json"
+
+var groth16CeremonyCommands = []cli.Command{
+	{
+		Name:      "new",
+		Aliases:   []string{},
+		Usage:     "derive the keys of the compiled circuit from the powers of tau",
+		ArgsUsage: "[powersoftau] [out]",
+		Action:    Groth16CeremonyNew,
+	},
+	{
+		Name:      "contribute",
+		Aliases:   []string{},
+		Usage:     "add a random contribution to δ",
+		ArgsUsage: "[in] [out]",
+		Action:    Groth16CeremonyContribute,
+	},
+	{
+		Name:      "verify",
+		Aliases:   []string{},
+		Usage:     "verify the powers of tau and the contributions to δ, writing the verified keys to trustedsetup.json",
+		ArgsUsage: "[powersoftau] [in]",
+		Action:    Groth16CeremonyVerify,
+	},
+}
+
+func readCompiledCircuit() circuitcompiler.Circuit {
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+	return circuit
+}
+
+func Groth16CeremonyNew(context *cli.Context) error {
+	circuit := readCompiledCircuit()
+	transcript, err := ceremony.ReadTranscript(argOrDefault(context, 0, powersOfTauFile))
+	panicErr(err)
+	phase2, err := ceremony.NewPhase2(transcript, circuit)
+	panicErr(err)
+
+	out := argOrDefault(context, 1, phase2File)
+	panicErr(ceremony.WritePhase2(out, phase2))
+	fmt.Println("Phase 2 data written to ", out)
+	return nil
+}
+
+func Groth16CeremonyContribute(context *cli.Context) error {
+	in := argOrDefault(context, 0, phase2File)
+	phase2, err := ceremony.ReadPhase2(in)
+	panicErr(err)
+	panicErr(phase2.Contribute())
+
+	out := argOrDefault(context, 1, in)
+	panicErr(ceremony.WritePhase2(out, phase2))
+	fmt.Println("contribution", len(phase2.Contributions), "added, phase 2 data written to ", out)
+	return nil
+}
+
+func Groth16CeremonyVerify(context *cli.Context) error {
+	circuit := readCompiledCircuit()
+	transcript, err := ceremony.ReadTranscript(argOrDefault(context, 0, powersOfTauFile))
+	panicErr(err)
+	phase2, err := ceremony.ReadPhase2(argOrDefault(context, 1, phase2File))
+	panicErr(err)
+	if err := transcript.Verify(); err != nil {
+		fmt.Println("❌ powers of tau verification not passed:", err)
+		return err
+	}
+	if err := phase2.Verify(transcript, circuit); err != nil {
+		fmt.Println("❌ phase 2 verification not passed:", err)
+		return err
+	}
+	fmt.Println("✓ phase 2 verification passed,", len(phase2.Contributions), "contributions")
+
+	// store the verified keys to be used by the groth16 genproofs and verify commands
+	jsonData, err := json.Marshal(phase2.Setup)
+	panicErr(err)
+	panicErr(ioutil.WriteFile("trustedsetup.json", jsonData, 0644))
+	fmt.Println("Trusted Setup data written to trustedsetup.json")
+	return nil
+}
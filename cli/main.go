@@ -13,6 +13,8 @@ import (
 
 	snark "github.com/arnaucube/go-snark"
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	_ "github.com/arnaucube/go-snark/gadgets" // registers the mimc7 and poseidon libraries for the circuits imports
+	"github.com/arnaucube/go-snark/gm17"
 	"github.com/arnaucube/go-snark/groth16"
 	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/urfave/cli"
@@ -72,8 +74,45 @@ var commands = []cli.Command{
 				Usage:   "verify the snark proofs",
 				Action:  Groth16VerifyProofs,
 			},
+			{
+				Name:        "ceremony",
+				Aliases:     []string{},
+				Usage:       "circuit specific phase 2 of the multi-party trusted setup, from the powers of tau",
+				Subcommands: groth16CeremonyCommands,
+			},
 		},
 	},
+	{
+		Name:    "gm17",
+		Aliases: []string{},
+		Usage:   "use gm17 protocol",
+		Subcommands: []cli.Command{
+			{
+				Name:    "trustedsetup",
+				Aliases: []string{},
+				Usage:   "generate trusted setup for a circuit",
+				Action:  GM17TrustedSetup,
+			},
+			{
+				Name:    "genproofs",
+				Aliases: []string{},
+				Usage:   "generate the snark proofs",
+				Action:  GM17GenerateProofs,
+			},
+			{
+				Name:    "verify",
+				Aliases: []string{},
+				Usage:   "verify the snark proofs",
+				Action:  GM17VerifyProofs,
+			},
+		},
+	},
+	{
+		Name:        "ceremony",
+		Aliases:     []string{},
+		Usage:       "powers of tau ceremony (phase 1 of the multi-party trusted setup)",
+		Subcommands: ceremonyCommands,
+	},
 }
 
 func main() {
@@ -222,7 +261,7 @@ func TrustedSetup(context *cli.Context) error {
 	fmt.Println(gammas)
 
 	// calculate trusted setup
-	setup, err := snark.GenerateTrustedSetup(len(w), circuit, alphas, betas, gammas)
+	setup, err := snark.GenerateTrustedSetup(nil, len(w), circuit, alphas, betas, gammas)
 	panicErr(err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
@@ -340,7 +379,8 @@ func VerifyProofs(context *cli.Context) error {
 	err = json.Unmarshal([]byte(string(publicInputsFile)), &publicSignals)
 	panicErr(err)
 
-	verified := snark.VerifyProof(circuit, trustedsetup, proof, publicSignals, true)
+	verified, err := snark.VerifyProof(circuit, trustedsetup, proof, publicSignals, true)
+	panicErr(err)
 	if !verified {
 		fmt.Println("ERROR: proofs not verified")
 	} else {
@@ -383,7 +423,7 @@ func Groth16TrustedSetup(context *cli.Context) error {
 	fmt.Println(gammas)
 
 	// calculate trusted setup
-	setup, err := groth16.GenerateTrustedSetup(len(w), circuit, alphas, betas, gammas)
+	setup, err := groth16.GenerateTrustedSetup(nil, len(w), circuit, alphas, betas, gammas)
 	panicErr(err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
@@ -451,7 +491,7 @@ func Groth16GenerateProofs(context *cli.Context) error {
 	fmt.Println(trustedsetup.Pk.PowersTauDelta)
 	fmt.Println(hx)
 	fmt.Println(w)
-	proof, err := groth16.GenerateProofs(circuit, trustedsetup, w, px)
+	proof, err := groth16.GenerateProofs(nil, circuit, trustedsetup, w, px)
 	panicErr(err)
 
 	fmt.Println("\n proofs:")
@@ -499,7 +539,139 @@ func Groth16VerifyProofs(context *cli.Context) error {
 	err = json.Unmarshal([]byte(string(publicInputsFile)), &publicSignals)
 	panicErr(err)
 
-	verified := groth16.VerifyProof(circuit, trustedsetup, proof, publicSignals, true)
+	verified, err := groth16.VerifyProof(circuit, trustedsetup, proof, publicSignals, true)
+	panicErr(err)
+	if !verified {
+		fmt.Println("ERROR: proofs not verified")
+	} else {
+		fmt.Println("Proofs verified")
+	}
+	return nil
+}
+
+func GM17TrustedSetup(context *cli.Context) error {
+	// open compiledcircuit.json
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+
+	// R1CS to SAP
+	us, ws, _ := gm17.Utils.PF.R1CSToSAP(circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C, circuit.NPublic)
+
+	// calculate trusted setup
+	setup, err := gm17.GenerateTrustedSetup(nil, len(us), circuit, us, ws)
+	panicErr(err)
+
+	// remove setup.Toxic
+	var tsetup gm17.Setup
+	tsetup.Pk = setup.Pk
+	tsetup.Vk = setup.Vk
+
+	// store setup to json
+	jsonData, err := json.Marshal(tsetup)
+	panicErr(err)
+	// store setup into file
+	jsonFile, err := os.Create("trustedsetup.json")
+	panicErr(err)
+	defer jsonFile.Close()
+	jsonFile.Write(jsonData)
+	jsonFile.Close()
+	fmt.Println("Trusted Setup data written to ", jsonFile.Name())
+	return nil
+}
+
+func GM17GenerateProofs(context *cli.Context) error {
+	// open compiledcircuit.json
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+
+	// open trustedsetup.json
+	trustedsetupFile, err := ioutil.ReadFile("trustedsetup.json")
+	panicErr(err)
+	var trustedsetup gm17.Setup
+	err = json.Unmarshal(trustedsetupFile, &trustedsetup)
+	panicErr(err)
+
+	// read privateInputs file
+	privateInputsFile, err := ioutil.ReadFile("privateInputs.json")
+	panicErr(err)
+	// read publicInputs file
+	publicInputsFile, err := ioutil.ReadFile("publicInputs.json")
+	panicErr(err)
+	// parse inputs from inputsFile
+	var inputs circuitcompiler.Inputs
+	err = json.Unmarshal(privateInputsFile, &inputs.Private)
+	panicErr(err)
+	err = json.Unmarshal(publicInputsFile, &inputs.Public)
+	panicErr(err)
+
+	// calculate wittness
+	w, err := circuit.CalculateWitness(inputs.Private, inputs.Public)
+	panicErr(err)
+
+	// R1CS to SAP, and the witness of the SAP
+	a := circuit.R1CS.A
+	b := circuit.R1CS.B
+	c := circuit.R1CS.C
+	us, ws, _ := gm17.Utils.PF.R1CSToSAP(a, b, c, circuit.NPublic)
+	sw := gm17.Utils.PF.SAPWitness(a, b, w, circuit.NPublic)
+	_, _, px := gm17.Utils.PF.CombineSAPPolynomials(sw, us, ws)
+
+	proof, err := gm17.GenerateProofs(nil, circuit, trustedsetup, sw, px)
+	panicErr(err)
+
+	fmt.Println("\n proofs:")
+	fmt.Println(proof)
+
+	// store proofs to json
+	jsonData, err := json.Marshal(proof)
+	panicErr(err)
+	// store proof into file
+	jsonFile, err := os.Create("proofs.json")
+	panicErr(err)
+	defer jsonFile.Close()
+	jsonFile.Write(jsonData)
+	jsonFile.Close()
+	fmt.Println("Proofs data written to ", jsonFile.Name())
+	return nil
+}
+
+func GM17VerifyProofs(context *cli.Context) error {
+	// open proofs.json
+	proofsFile, err := ioutil.ReadFile("proofs.json")
+	panicErr(err)
+	var proof gm17.Proof
+	err = json.Unmarshal(proofsFile, &proof)
+	panicErr(err)
+
+	// open compiledcircuit.json
+	compiledcircuitFile, err := ioutil.ReadFile("compiledcircuit.json")
+	panicErr(err)
+	var circuit circuitcompiler.Circuit
+	err = json.Unmarshal(compiledcircuitFile, &circuit)
+	panicErr(err)
+
+	// open trustedsetup.json
+	trustedsetupFile, err := ioutil.ReadFile("trustedsetup.json")
+	panicErr(err)
+	var trustedsetup gm17.Setup
+	err = json.Unmarshal(trustedsetupFile, &trustedsetup)
+	panicErr(err)
+
+	// read publicInputs file
+	publicInputsFile, err := ioutil.ReadFile("publicInputs.json")
+	panicErr(err)
+	var publicSignals []*big.Int
+	err = json.Unmarshal(publicInputsFile, &publicSignals)
+	panicErr(err)
+
+	verified, err := gm17.VerifyProof(circuit, trustedsetup, proof, publicSignals, true)
+	panicErr(err)
 	if !verified {
 		fmt.Println("ERROR: proofs not verified")
 	} else {
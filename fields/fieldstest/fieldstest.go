@@ -0,0 +1,40 @@
+// Package fieldstest provides utilities for testing the code that takes its randomness from an io.Reader. Its sources
+// of randomness are deterministic, so they must only be used in tests, and never in production.
+package fieldstest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// NewSeededReader returns a deterministic source of randomness, the SHA-256 hash in counter mode keyed with the
+// SHA-256 hash of the seed, so the setups and proofs generated from it can be reproduced in tests
+func NewSeededReader(seed []byte) io.Reader {
+	return &seededReader{key: sha256.Sum256(seed)}
+}
+
+// seededReader returns the blocks SHA-256(key || counter), for the counters 0, 1, 2, ... as 8 bytes big endian
+type seededReader struct {
+	key     [32]byte
+	counter uint64
+	block   []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var in [40]byte
+			copy(in[:32], r.key[:])
+			binary.BigEndian.PutUint64(in[32:], r.counter)
+			r.counter++
+			block := sha256.Sum256(in[:])
+			r.block = block[:]
+		}
+		c := copy(p[n:], r.block)
+		r.block = r.block[c:]
+		n += c
+	}
+	return n, nil
+}
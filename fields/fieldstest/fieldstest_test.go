@@ -0,0 +1,30 @@
+package fieldstest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSeededReader(t *testing.T) {
+	read := func(seed string) []byte {
+		b := make([]byte, 64)
+		_, err := io.ReadFull(NewSeededReader([]byte(seed)), b)
+		assert.Nil(t, err)
+		return b
+	}
+	assert.Equal(t, read("seed"), read("seed"))
+	assert.NotEqual(t, read("seed"), read("other seed"))
+
+	// the stream does not depend on the size of the reads
+	r := NewSeededReader([]byte("seed"))
+	b := make([]byte, 64)
+	offset := 0
+	for _, n := range []int{1, 30, 2, 31} {
+		_, err := io.ReadFull(r, b[offset:offset+n])
+		assert.Nil(t, err)
+		offset += n
+	}
+	assert.Equal(t, read("seed"), b)
+}
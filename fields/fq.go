@@ -3,6 +3,8 @@ package fields
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
+	"io"
 	"math/big"
 )
 
@@ -97,8 +99,11 @@ func (fq Fq) Square(a *big.Int) *big.Int {
 	return new(big.Int).Mod(m, fq.Q)
 }
 
-// Exp performs the exponential over Fq
+// Exp performs the exponential over Fq, a negative exponent is the exponential of the inverse
 func (fq Fq) Exp(base *big.Int, e *big.Int) *big.Int {
+	if e.Sign() < 0 {
+		return fq.Exp(fq.Inverse(base), new(big.Int).Neg(e))
+	}
 	res := fq.One()
 	rem := fq.Copy(e)
 	exp := base
@@ -113,22 +118,61 @@ func (fq Fq) Exp(base *big.Int, e *big.Int) *big.Int {
 	return res
 }
 
+// Sqrt returns a square root of a and true, or false if a is not a square in Fq, computed as a^((Q+1)/4), which
+// requires Q = 3 mod 4
+func (fq Fq) Sqrt(a *big.Int) (*big.Int, bool) {
+	e := new(big.Int).Rsh(new(big.Int).Add(fq.Q, big.NewInt(int64(1))), 2)
+	r := fq.Exp(a, e)
+	if !fq.Equal(fq.Square(r), a) {
+		return fq.Zero(), false
+	}
+	return r, true
+}
+
+// Rand returns a uniformly random element of the Finite Field, reading the randomness from crypto/rand
 func (fq Fq) Rand() (*big.Int, error) {
+	return fq.RandFrom(rand.Reader)
+}
+
+// RandFrom returns a uniformly random element of the Finite Field, reading the randomness from r. It samples numbers
+// of the bit length of Q until one of them is lower than Q (rejection sampling), so all the elements have the same
+// probability.
+func (fq Fq) RandFrom(r io.Reader) (*big.Int, error) {
+	bitLen := fq.Q.BitLen()
+	b := make([]byte, (bitLen+7)/8)
+	// bits of the most significant byte inside the bit length of Q
+	mask := byte(0xff >> uint(len(b)*8-bitLen))
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		b[0] &= mask
+		x := new(big.Int).SetBytes(b)
+		if x.Cmp(fq.Q) < 0 {
+			return x, nil
+		}
+	}
+}
 
-	// twoexp := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(maxbits)), nil)
-	// max := new(big.Int).Sub(twoexp, big.NewInt(1))
+// RandNonZero returns a uniformly random non zero element of the Finite Field, reading the randomness from crypto/rand
+func (fq Fq) RandNonZero() (*big.Int, error) {
+	return fq.RandNonZeroFrom(rand.Reader)
+}
 
-	maxbits := fq.Q.BitLen()
-	b := make([]byte, (maxbits/8)-1)
-	_, err := rand.Read(b)
-	if err != nil {
-		return nil, err
+// RandNonZeroFrom returns a uniformly random non zero element of the Finite Field, reading the randomness from r
+func (fq Fq) RandNonZeroFrom(r io.Reader) (*big.Int, error) {
+	if fq.Q.Cmp(big.NewInt(int64(1))) <= 0 {
+		return nil, errors.New("fields: Finite Field without non zero elements")
+	}
+	for {
+		x, err := fq.RandFrom(r)
+		if err != nil {
+			return nil, err
+		}
+		if x.Sign() != 0 {
+			return x, nil
+		}
 	}
-	r := new(big.Int).SetBytes(b)
-	rq := new(big.Int).Mod(r, fq.Q)
-
-	// r over q, nil
-	return rq, nil
 }
 
 func (fq Fq) IsZero(a *big.Int) bool {
@@ -164,6 +208,21 @@ func (fq Fq) Equal(a, b *big.Int) bool {
 	return bytes.Equal(aAff.Bytes(), bAff.Bytes())
 }
 
+// CondSwap returns (b, a) if swap is 1 and (a, b) if it is 0, with the same sequence of operations over the size bytes
+// of the encoding of the values for both values of swap. The values must be non-negative and fit in size bytes, so the
+// elements of a field must be reduced before, as FillBytes panics for the bigger ones and drops the sign
+func CondSwap(a, b *big.Int, swap uint, size int) (*big.Int, *big.Int) {
+	aBytes := a.FillBytes(make([]byte, size))
+	bBytes := b.FillBytes(make([]byte, size))
+	mask := byte(0) - byte(swap&1)
+	for i := range aBytes {
+		t := mask & (aBytes[i] ^ bBytes[i])
+		aBytes[i] ^= t
+		bBytes[i] ^= t
+	}
+	return new(big.Int).SetBytes(aBytes), new(big.Int).SetBytes(bBytes)
+}
+
 func BigIsOdd(n *big.Int) bool {
 	one := big.NewInt(int64(1))
 	and := new(big.Int).And(n, one)
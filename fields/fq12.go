@@ -136,6 +136,90 @@ func (fq12 Fq12) Square(a [2][3][2]*big.Int) [2][3][2]*big.Int {
 	}
 }
 
+// FrobeniusCoeffs returns {ξ^(i(q-1)/6)} from 0 to 5, where ξ is the NonResidue, the factors of the Fq2 coefficients of
+// w^i in the Frobenius map
+func (fq12 Fq12) FrobeniusCoeffs() [6][2]*big.Int {
+	var coeffs [6][2]*big.Int
+	e := new(big.Int).Sub(fq12.Fq2.F.Q, big.NewInt(int64(1)))
+	e.Div(e, big.NewInt(int64(6)))
+	g := fq12.Fq2.Exp(fq12.NonResidue, e)
+	c := fq12.Fq2.One()
+	for i := 0; i < 6; i++ {
+		coeffs[i] = c
+		c = fq12.Fq2.Mul(c, g)
+	}
+	return coeffs
+}
+
+// Frobenius returns a^(q^power), applying power times the Frobenius map, which conjugates the Fq2 coefficient of each
+// w^i (where a = c0 + c1 w, with c0 and c1 in Fq6 over v = w^2) and multiplies it by coeffs[i], the values returned
+// by FrobeniusCoeffs
+func (fq12 Fq12) Frobenius(a [2][3][2]*big.Int, power int, coeffs [6][2]*big.Int) [2][3][2]*big.Int {
+	for k := 0; k < power%12; k++ {
+		var r [2][3][2]*big.Int
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 3; j++ {
+				// the coefficient a[i][j] is the one of v^j w^i = w^(2j+i)
+				r[i][j] = fq12.Fq2.Mul(fq12.Fq2.Conjugate(a[i][j]), coeffs[2*j+i])
+			}
+		}
+		a = r
+	}
+	return a
+}
+
+// Conjugate returns the conjugate c0 - c1 w of a = c0 + c1 w, which is a^(q^6), and the inverse of a for the elements
+// of the cyclotomic subgroup
+func (fq12 Fq12) Conjugate(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	return [2][3][2]*big.Int{
+		a[0],
+		fq12.F.Neg(a[1]),
+	}
+}
+
+// fq4Square returns the square of a + b s over Fq4 = Fq2[s]/(s^2 - ξ)
+func (fq12 Fq12) fq4Square(a, b [2]*big.Int) ([2]*big.Int, [2]*big.Int) {
+	t0 := fq12.Fq2.Square(a)
+	t1 := fq12.Fq2.Square(b)
+	c0 := fq12.Fq2.Add(fq12.Fq2.Mul(fq12.NonResidue, t1), t0)
+	c1 := fq12.Fq2.Sub(fq12.Fq2.Sub(fq12.Fq2.Square(fq12.Fq2.Add(a, b)), t0), t1)
+	return c0, c1
+}
+
+// CyclotomicSquare returns the square of a, which must be an element of the cyclotomic subgroup (a^(q^6+1) = 1), as
+// the result of the easy part of the final exponentiation of the pairings, with the Granger-Scott squaring
+// https://eprint.iacr.org/2009/565.pdf
+func (fq12 Fq12) CyclotomicSquare(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	z0, z4, z3 := a[0][0], a[0][1], a[0][2]
+	z2, z1, z5 := a[1][0], a[1][1], a[1][2]
+	// 3 * t - 2 * z and 3 * t + 2 * z
+	sub := func(t, z [2]*big.Int) [2]*big.Int {
+		d := fq12.Fq2.Sub(t, z)
+		return fq12.Fq2.Add(fq12.Fq2.Add(d, d), t)
+	}
+	add := func(t, z [2]*big.Int) [2]*big.Int {
+		d := fq12.Fq2.Add(t, z)
+		return fq12.Fq2.Add(fq12.Fq2.Add(d, d), t)
+	}
+
+	t0, t1 := fq12.fq4Square(z0, z1)
+	z0 = sub(t0, z0)
+	z1 = add(t1, z1)
+
+	t0, t1 = fq12.fq4Square(z2, z3)
+	t2, t3 := fq12.fq4Square(z4, z5)
+	z4 = sub(t0, z4)
+	z5 = add(t1, z5)
+
+	z2 = add(fq12.Fq2.Mul(fq12.NonResidue, t3), z2)
+	z3 = sub(t2, z3)
+
+	return [2][3][2]*big.Int{
+		{z0, z4, z3},
+		{z2, z1, z5},
+	}
+}
+
 func (fq12 Fq12) Exp(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
 	// TODO fix bottleneck
 
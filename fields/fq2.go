@@ -132,6 +132,50 @@ func (fq2 Fq2) Square(a [2]*big.Int) [2]*big.Int {
 	}
 }
 
+// Conjugate returns the conjugate a0 - a1 u of a = a0 + a1 u, which is a^q
+func (fq2 Fq2) Conjugate(a [2]*big.Int) [2]*big.Int {
+	return [2]*big.Int{
+		a[0],
+		fq2.F.Neg(a[1]),
+	}
+}
+
+// Exp performs the exponential of a to the e on the Fq2
+func (fq2 Fq2) Exp(a [2]*big.Int, e *big.Int) [2]*big.Int {
+	res := fq2.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = fq2.Square(res)
+		if e.Bit(i) == 1 {
+			res = fq2.Mul(res, a)
+		}
+	}
+	return res
+}
+
+// Sqrt returns a square root of a and true, or false if a is not a square in Fq2, with the algorithm 9 of
+// https://eprint.iacr.org/2012/685.pdf, which requires Q = 3 mod 4
+func (fq2 Fq2) Sqrt(a [2]*big.Int) ([2]*big.Int, bool) {
+	one := big.NewInt(int64(1))
+	// (Q - 3) / 4
+	e := new(big.Int).Rsh(new(big.Int).Sub(fq2.F.Q, big.NewInt(int64(3))), 2)
+	a1 := fq2.Exp(a, e)
+	alpha := fq2.Mul(fq2.Square(a1), a)
+	a0 := fq2.Mul(fq2.Conjugate(alpha), alpha)
+	minusOne := fq2.Neg(fq2.One())
+	if fq2.Equal(a0, minusOne) {
+		return fq2.Zero(), false
+	}
+
+	x0 := fq2.Mul(a1, a)
+	if fq2.Equal(alpha, minusOne) {
+		// i x0
+		return fq2.Mul([2]*big.Int{big.NewInt(int64(0)), one}, x0), true
+	}
+	// (1 + alpha)^((Q - 1) / 2) x0
+	b := fq2.Exp(fq2.Add(fq2.One(), alpha), new(big.Int).Rsh(new(big.Int).Sub(fq2.F.Q, one), 1))
+	return fq2.Mul(b, x0), true
+}
+
 func (fq2 Fq2) IsZero(a [2]*big.Int) bool {
 	return fq2.F.IsZero(a[0]) && fq2.F.IsZero(a[1])
 }
@@ -1,9 +1,12 @@
 package fields
 
 import (
+	"bytes"
+	"math"
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields/fieldstest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +47,58 @@ func TestFq1(t *testing.T) {
 	assert.Equal(t, iToBig(4), res)
 }
 
+func TestFqExp(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq := NewFq(q)
+
+	exps := []*big.Int{iToBig(0), iToBig(1), iToBig(2), iToBig(65537), new(big.Int).Sub(q, iToBig(2)), new(big.Int).Lsh(q, 3)}
+	for i := 0; i < 10; i++ {
+		e, err := fq.Rand()
+		assert.Nil(t, err)
+		exps = append(exps, e)
+	}
+	base, err := fq.Rand()
+	assert.Nil(t, err)
+	for _, e := range exps {
+		assert.Equal(t, 0, new(big.Int).Exp(base, e, q).Cmp(fq.Exp(base, e)))
+	}
+	assert.Equal(t, iToBig(6), NewFq(iToBig(7)).Exp(iToBig(3), iToBig(3)))
+
+	// a negative exponent is the exponential of the inverse
+	minus3 := iToBig(-3)
+	assert.Equal(t, 0, new(big.Int).Exp(base, minus3, q).Cmp(fq.Exp(base, minus3)))
+}
+
+func TestFqSqrt(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq := NewFq(q)
+
+	for i := 0; i < 10; i++ {
+		x, err := fq.Rand()
+		assert.Nil(t, err)
+		r, ok := fq.Sqrt(fq.Square(x))
+		assert.True(t, ok)
+		assert.True(t, fq.Equal(fq.Square(r), fq.Square(x)))
+	}
+	r, ok := fq.Sqrt(iToBig(0))
+	assert.True(t, ok)
+	assert.Equal(t, 0, r.Sign())
+	// -1 is not a square, as Q = 3 mod 4
+	_, ok = fq.Sqrt(fq.Neg(iToBig(1)))
+	assert.False(t, ok)
+}
+
+func TestCondSwap(t *testing.T) {
+	a, b := CondSwap(iToBig(5), iToBig(300), 0, 2)
+	assert.Equal(t, iToBig(5), a)
+	assert.Equal(t, iToBig(300), b)
+	a, b = CondSwap(iToBig(5), iToBig(300), 1, 2)
+	assert.Equal(t, iToBig(300), a)
+	assert.Equal(t, iToBig(5), b)
+}
+
 func TestFq2(t *testing.T) {
 	fq1 := NewFq(iToBig(7))
 	nonResidueFq2str := "-1" // i/j
@@ -83,6 +138,42 @@ func TestFq2(t *testing.T) {
 	assert.Equal(t, fq2.Affine(res), fq2.Affine(res2))
 }
 
+func TestFq2Sqrt(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	fq2 := NewFq2(fq1, fq1.Neg(iToBig(1)))
+
+	roots := [][2]*big.Int{iiToBig(0, 0), iiToBig(1, 0), iiToBig(0, 1), iiToBig(9, 1)}
+	for i := 0; i < 10; i++ {
+		a0, err := fq1.Rand()
+		assert.Nil(t, err)
+		a1, err := fq1.Rand()
+		assert.Nil(t, err)
+		roots = append(roots, [2]*big.Int{a0, a1})
+	}
+	nonSquares := 0
+	for i, x := range roots {
+		a := fq2.Square(x)
+		r, ok := fq2.Sqrt(a)
+		assert.True(t, ok)
+		assert.True(t, fq2.Equal(fq2.Square(r), a))
+
+		// half of the elements are not squares
+		b := fq2.Add(a, [2]*big.Int{iToBig(i + 1), iToBig(0)})
+		r, ok = fq2.Sqrt(b)
+		if ok {
+			assert.True(t, fq2.Equal(fq2.Square(r), b))
+		} else {
+			// Euler's criterion over Fq2
+			e := new(big.Int).Rsh(new(big.Int).Sub(new(big.Int).Mul(q, q), iToBig(1)), 1)
+			assert.True(t, fq2.Equal(fq2.Exp(b, e), fq2.Neg(fq2.One())))
+			nonSquares++
+		}
+	}
+	assert.True(t, nonSquares > 0)
+}
+
 func TestFq6(t *testing.T) {
 	// bn128, err := NewBn128()
 	// assert.Nil(t, err)
@@ -167,3 +258,139 @@ func TestFq12(t *testing.T) {
 	divRes := fq12.Div(mulRes, b)
 	assert.Equal(t, fq12.Affine(a), fq12.Affine(divRes))
 }
+
+func TestFq12Frobenius(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	fq2 := NewFq2(fq1, fq1.Neg(iToBig(1)))
+	fq6 := NewFq6(fq2, iiToBig(9, 1))
+	fq12 := NewFq12(fq6, fq2, iiToBig(9, 1))
+	coeffs := fq12.FrobeniusCoeffs()
+
+	x := iiToBig(3, 7)
+	assert.True(t, fq2.Equal(fq2.Exp(x, q), fq2.Conjugate(x)))
+
+	a := [2][3][2]*big.Int{
+		{iiToBig(1, 2), iiToBig(3, 4), iiToBig(5, 6)},
+		{iiToBig(7, 8), iiToBig(9, 10), iiToBig(11, 12)},
+	}
+	// the Frobenius map is the exponentiation to q
+	assert.True(t, fq12.Equal(fq12.Exp(a, q), fq12.Frobenius(a, 1, coeffs)))
+	assert.True(t, fq12.Equal(fq12.Frobenius(fq12.Frobenius(a, 1, coeffs), 1, coeffs), fq12.Frobenius(a, 2, coeffs)))
+	assert.True(t, fq12.Equal(fq12.Conjugate(a), fq12.Frobenius(a, 6, coeffs)))
+	assert.True(t, fq12.Equal(a, fq12.Frobenius(a, 12, coeffs)))
+
+	// a^((q^6-1)(q^2+1)) is in the cyclotomic subgroup
+	c := fq12.Mul(fq12.Conjugate(a), fq12.Inverse(a))
+	c = fq12.Mul(fq12.Frobenius(c, 2, coeffs), c)
+	assert.True(t, fq12.Equal(fq12.One(), fq12.Mul(c, fq12.Conjugate(c))))
+	assert.True(t, fq12.Equal(fq12.Square(c), fq12.CyclotomicSquare(c)))
+	c2 := fq12.CyclotomicSquare(c)
+	assert.True(t, fq12.Equal(fq12.Square(c2), fq12.CyclotomicSquare(c2)))
+}
+
+func TestFqRandFrom(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	fq := NewFq(q)
+
+	// the same seed gives the same elements
+	r1 := fieldstest.NewSeededReader([]byte("seed"))
+	r2 := fieldstest.NewSeededReader([]byte("seed"))
+	for i := 0; i < 10; i++ {
+		x1, err := fq.RandFrom(r1)
+		assert.Nil(t, err)
+		x2, err := fq.RandFrom(r2)
+		assert.Nil(t, err)
+		assert.Equal(t, x1, x2)
+		assert.True(t, x1.Cmp(q) < 0)
+	}
+	x1, err := fq.RandFrom(fieldstest.NewSeededReader([]byte("seed")))
+	assert.Nil(t, err)
+	x2, err := fq.RandFrom(fieldstest.NewSeededReader([]byte("other seed")))
+	assert.Nil(t, err)
+	assert.NotEqual(t, x1, x2)
+
+	// a reader without enough randomness
+	_, err = fq.RandFrom(bytes.NewReader([]byte{1, 2, 3}))
+	assert.NotNil(t, err)
+}
+
+// chiSquare returns the chi-square statistic of n uniform samples of the Finite Field of order q
+func chiSquare(t *testing.T, fq Fq, n int, nonZero bool) float64 {
+	q := int(fq.Q.Int64())
+	counts := make([]int, q)
+	rnd := fieldstest.NewSeededReader([]byte("chi-square"))
+	for i := 0; i < n; i++ {
+		var x *big.Int
+		var err error
+		if nonZero {
+			x, err = fq.RandNonZeroFrom(rnd)
+		} else {
+			x, err = fq.RandFrom(rnd)
+		}
+		assert.Nil(t, err)
+		counts[x.Int64()]++
+	}
+	first := 0
+	if nonZero {
+		assert.Equal(t, 0, counts[0])
+		first = 1
+	}
+	expected := float64(n) / float64(q-first)
+	var chi float64
+	for _, c := range counts[first:] {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	return chi
+}
+
+func TestFqRandUniform(t *testing.T) {
+	// critical values of the chi-square distribution at p = 0.001
+	// 12 degrees of freedom, with 4 bits sampled for each element
+	assert.True(t, chiSquare(t, NewFq(iToBig(13)), 13000, false) < 32.91)
+	// 256 degrees of freedom, with 9 bits sampled for each element
+	assert.True(t, chiSquare(t, NewFq(iToBig(257)), 25700, false) < 330.5)
+	// 11 degrees of freedom without the zero
+	assert.True(t, chiSquare(t, NewFq(iToBig(13)), 12000, true) < 31.26)
+
+	// the elements cover the full range of the BN128 Finite Field over R, where 2^253 <= x < r for a fraction
+	// (r - 2^253) / r of them, which were never sampled from 31 bytes
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	fq := NewFq(r)
+	top := new(big.Int).Lsh(iToBig(1), 253)
+	expected, _ := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(r, top)), new(big.Float).SetInt(r)).Float64()
+	rnd := fieldstest.NewSeededReader([]byte("range"))
+	n := 4000
+	above := 0
+	for i := 0; i < n; i++ {
+		x, err := fq.RandFrom(rnd)
+		assert.Nil(t, err)
+		assert.True(t, x.Cmp(r) < 0)
+		if x.Cmp(top) >= 0 {
+			above++
+		}
+	}
+	// 5 standard deviations of the binomial distribution
+	assert.InDelta(t, expected, float64(above)/float64(n), 5*math.Sqrt(expected*(1-expected)/float64(n)))
+
+	// with crypto/rand
+	x, err := fq.Rand()
+	assert.Nil(t, err)
+	assert.True(t, x.Cmp(r) < 0)
+	x, err = fq.RandNonZero()
+	assert.Nil(t, err)
+	assert.True(t, x.Sign() > 0 && x.Cmp(r) < 0)
+}
+
+func TestFqRandNonZero(t *testing.T) {
+	// the only non zero element of the Finite Field of order 2
+	x, err := NewFq(iToBig(2)).RandNonZero()
+	assert.Nil(t, err)
+	assert.Equal(t, iToBig(1), x)
+	_, err = NewFq(iToBig(1)).RandNonZero()
+	assert.NotNil(t, err)
+}
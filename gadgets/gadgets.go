@@ -0,0 +1,49 @@
+// Package gadgets implements SNARK friendly primitives over the BN128 scalar field, both as native Go
+// functions and as circuit functions, that can be used from the circuitcompiler.Builder or imported
+// from the flat code circuits (`import "mimc7"`, `import "poseidon"`, `import "merkletree"`,
+// `import "sha256"`) once this package is imported.
+package gadgets
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+type utils struct {
+	FqR fields.Fq
+}
+
+// Utils is the data structure holding the FqR Finite Field over R, where the gadgets operate
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	fqR, err := bn128.NewFqR()
+	if err != nil {
+		panic(err)
+	}
+	return utils{
+		FqR: fqR,
+	}
+}
+
+func init() {
+	circuitcompiler.RegisterLibrary("mimc7", MiMC7Code())
+	circuitcompiler.RegisterLibrary("poseidon", PoseidonCode())
+	circuitcompiler.RegisterLibrary("merkletree", MerkleTreeCode())
+	// the SHA-256 code has more than 150000 constraints, so it is only generated when imported
+	circuitcompiler.RegisterLibraryFunc("sha256", func() string {
+		code, err := SHA256Code(SHA256LibraryBytes)
+		if err != nil {
+			panic(err)
+		}
+		return code
+	})
+}
+
+// assertBit adds to the Builder the constraint bit * (bit - 1) == 0, so the bit can only be 0 or 1
+func assertBit(b *circuitcompiler.Builder, bit circuitcompiler.Variable) {
+	b.AssertEqual(b.Mul(bit, b.Sub(bit, b.Constant(big.NewInt(int64(1))))), b.Constant(big.NewInt(int64(0))))
+}
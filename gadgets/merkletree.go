@@ -0,0 +1,172 @@
+package gadgets
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// MerkleTree is a binary Merkle tree of fixed depth over the Poseidon hash, where each node is
+// the hash of its two children, poseidon(left, right), and the empty leaves are 0
+type MerkleTree struct {
+	depth  int
+	levels [][]*big.Int // levels[0] are the leaves and levels[depth] contains the root
+}
+
+// NewMerkleTree creates a new MerkleTree of the given depth with the given leaves, the rest of leaves are set to 0
+func NewMerkleTree(depth int, leaves []*big.Int) (*MerkleTree, error) {
+	if depth < 1 {
+		return nil, errors.New("merkletree: depth must be at least 1")
+	}
+	if len(leaves) > 1<<uint(depth) {
+		return nil, errors.New("merkletree: too many leaves for the tree depth")
+	}
+	mt := &MerkleTree{depth: depth}
+	mt.levels = make([][]*big.Int, depth+1)
+	mt.levels[0] = make([]*big.Int, 1<<uint(depth))
+	for i := range mt.levels[0] {
+		mt.levels[0][i] = big.NewInt(int64(0))
+	}
+	for i, leaf := range leaves {
+		if leaf.Sign() < 0 || leaf.Cmp(Utils.FqR.Q) >= 0 {
+			return nil, errors.New("merkletree: leaf not inside the Finite Field")
+		}
+		mt.levels[0][i] = leaf
+	}
+	for l := 1; l <= depth; l++ {
+		mt.levels[l] = make([]*big.Int, len(mt.levels[l-1])/2)
+		for i := range mt.levels[l] {
+			if err := mt.updateNode(l, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mt, nil
+}
+
+func (mt *MerkleTree) updateNode(level, index int) error {
+	h, err := PoseidonHash([]*big.Int{mt.levels[level-1][2*index], mt.levels[level-1][2*index+1]})
+	if err != nil {
+		return err
+	}
+	mt.levels[level][index] = h
+	return nil
+}
+
+// Depth returns the depth of the MerkleTree
+func (mt *MerkleTree) Depth() int {
+	return mt.depth
+}
+
+// Root returns the root of the MerkleTree
+func (mt *MerkleTree) Root() *big.Int {
+	return mt.levels[mt.depth][0]
+}
+
+// Leaf returns the leaf at the given index
+func (mt *MerkleTree) Leaf(index int) (*big.Int, error) {
+	if index < 0 || index >= len(mt.levels[0]) {
+		return nil, errors.New("merkletree: index out of range")
+	}
+	return mt.levels[0][index], nil
+}
+
+// SetLeaf updates the leaf at the given index, recalculating the nodes of its path
+func (mt *MerkleTree) SetLeaf(index int, leaf *big.Int) error {
+	if index < 0 || index >= len(mt.levels[0]) {
+		return errors.New("merkletree: index out of range")
+	}
+	if leaf.Sign() < 0 || leaf.Cmp(Utils.FqR.Q) >= 0 {
+		return errors.New("merkletree: leaf not inside the Finite Field")
+	}
+	mt.levels[0][index] = leaf
+	for l := 1; l <= mt.depth; l++ {
+		index = index / 2
+		if err := mt.updateNode(l, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateProof returns the path elements (the siblings from the leaf to the root) and the path bits
+// of the leaf at the given index, where the bit i is 1 when the node at the level i is the right child
+func (mt *MerkleTree) GenerateProof(index int) ([]*big.Int, []*big.Int, error) {
+	if index < 0 || index >= len(mt.levels[0]) {
+		return nil, nil, errors.New("merkletree: index out of range")
+	}
+	var siblings []*big.Int
+	var pathBits []*big.Int
+	for l := 0; l < mt.depth; l++ {
+		siblings = append(siblings, mt.levels[l][index^1])
+		pathBits = append(pathBits, big.NewInt(int64(index&1)))
+		index = index / 2
+	}
+	return siblings, pathBits, nil
+}
+
+// VerifyMerkleProof checks that the leaf is in the tree with the given root, with the path elements and path bits
+// returned by MerkleTree.GenerateProof
+func VerifyMerkleProof(root, leaf *big.Int, siblings, pathBits []*big.Int) bool {
+	if len(siblings) != len(pathBits) {
+		return false
+	}
+	node := leaf
+	for i := 0; i < len(siblings); i++ {
+		var children []*big.Int
+		if pathBits[i].Cmp(big.NewInt(int64(0))) == 0 {
+			children = []*big.Int{node, siblings[i]}
+		} else if pathBits[i].Cmp(big.NewInt(int64(1))) == 0 {
+			children = []*big.Int{siblings[i], node}
+		} else {
+			return false
+		}
+		h, err := PoseidonHash(children)
+		if err != nil {
+			return false
+		}
+		node = h
+	}
+	return Utils.FqR.Equal(node, root)
+}
+
+// MerkleProofGadget adds to the Builder the constraints that check that the leaf is in the tree with the given root,
+// where the path elements and the path bits are the ones returned by MerkleTree.GenerateProof
+func MerkleProofGadget(b *circuitcompiler.Builder, root, leaf circuitcompiler.Variable, siblings, pathBits []circuitcompiler.Variable) error {
+	if len(siblings) != len(pathBits) {
+		return errors.New("merkletree: siblings and path bits lengths mismatch")
+	}
+	node := leaf
+	for i := 0; i < len(siblings); i++ {
+		assertBit(b, pathBits[i])
+		// left = node + bit * (sibling - node), right = sibling - bit * (sibling - node)
+		m := b.Mul(pathBits[i], b.Sub(siblings[i], node))
+		left := b.Add(node, m)
+		right := b.Sub(siblings[i], m)
+		h, err := PoseidonHashGadget(b, []circuitcompiler.Variable{left, right})
+		if err != nil {
+			return err
+		}
+		node = h
+	}
+	b.AssertEqual(root, node)
+	return nil
+}
+
+// MerkleTreeCode returns the flat code of the library imported with `import "merkletree"`, which declares the
+// function merklelevel(node, sibling, bit), returning the parent node of the path, where bit is 1 when node is the right child
+func MerkleTreeCode() string {
+	return `import "poseidon"
+func merklelevel(private nodea, private siblinga, private bita):
+	bma = bita - 1
+	bmb = bita * bma
+	equals(bmb, 0)
+	da = siblinga - nodea
+	ma = bita * da
+	lefta = nodea + ma
+	righta = siblinga - ma
+	ha = poseidon2(lefta, righta)
+	return ha
+`
+}
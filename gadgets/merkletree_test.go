@@ -0,0 +1,140 @@
+package gadgets
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/groth16"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleTree(t *testing.T) {
+	leaves := []*big.Int{big.NewInt(int64(1)), big.NewInt(int64(2)), big.NewInt(int64(3))}
+	mt, err := NewMerkleTree(2, leaves)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, mt.Depth())
+
+	h01, err := PoseidonHash([]*big.Int{leaves[0], leaves[1]})
+	assert.Nil(t, err)
+	h23, err := PoseidonHash([]*big.Int{leaves[2], big.NewInt(int64(0))})
+	assert.Nil(t, err)
+	root, err := PoseidonHash([]*big.Int{h01, h23})
+	assert.Nil(t, err)
+	assert.Equal(t, root, mt.Root())
+
+	for i := 0; i < 4; i++ {
+		leaf, err := mt.Leaf(i)
+		assert.Nil(t, err)
+		siblings, pathBits, err := mt.GenerateProof(i)
+		assert.Nil(t, err)
+		assert.True(t, VerifyMerkleProof(mt.Root(), leaf, siblings, pathBits))
+		assert.False(t, VerifyMerkleProof(mt.Root(), big.NewInt(int64(5)), siblings, pathBits))
+	}
+	siblings, pathBits, err := mt.GenerateProof(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(0)), h01}, siblings)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(0)), big.NewInt(int64(1))}, pathBits)
+
+	// update a leaf
+	assert.Nil(t, mt.SetLeaf(3, big.NewInt(int64(4))))
+	mt2, err := NewMerkleTree(2, append(leaves, big.NewInt(int64(4))))
+	assert.Nil(t, err)
+	assert.Equal(t, mt2.Root(), mt.Root())
+	assert.False(t, VerifyMerkleProof(mt.Root(), leaves[2], siblings, pathBits))
+
+	_, err = NewMerkleTree(1, leaves)
+	assert.NotNil(t, err)
+	_, _, err = mt.GenerateProof(4)
+	assert.NotNil(t, err)
+}
+
+func TestMerkleProofGadget(t *testing.T) {
+	mt, err := NewMerkleTree(3, []*big.Int{big.NewInt(int64(10)), big.NewInt(int64(11)), big.NewInt(int64(12))})
+	assert.Nil(t, err)
+
+	b := circuitcompiler.NewBuilder()
+	leaf := b.PrivateInput("leaf")
+	var siblings, pathBits []circuitcompiler.Variable
+	for i := 0; i < mt.Depth(); i++ {
+		siblings = append(siblings, b.PrivateInput(fmt.Sprintf("e%d", i)))
+		pathBits = append(pathBits, b.PrivateInput(fmt.Sprintf("b%d", i)))
+	}
+	root := b.PublicInput("root")
+	assert.Nil(t, MerkleProofGadget(b, root, leaf, siblings, pathBits))
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	siblingsValues, pathBitsValues, err := mt.GenerateProof(2)
+	assert.Nil(t, err)
+	assignment := circuitcompiler.Assignment{
+		leaf: big.NewInt(int64(12)),
+		root: mt.Root(),
+	}
+	for i := 0; i < mt.Depth(); i++ {
+		assignment[siblings[i]] = siblingsValues[i]
+		assignment[pathBits[i]] = pathBitsValues[i]
+	}
+	w, err := b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	// a leaf that is not in the tree
+	assignment[leaf] = big.NewInt(int64(13))
+	w, err = b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	w[1] = mt.Root()
+	assert.False(t, circuit.IsSatisfied(w))
+
+	// a path bit that is not a bit
+	assignment[leaf] = big.NewInt(int64(12))
+	assignment[pathBits[0]] = big.NewInt(int64(2))
+	w, err = b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	w[1] = mt.Root()
+	assert.False(t, circuit.IsSatisfied(w))
+
+	assert.NotNil(t, MerkleProofGadget(b, root, leaf, siblings, pathBits[1:]))
+}
+
+func TestMerkleProofCircuitGroth16(t *testing.T) {
+	// the circuit of circuitexamples/merkleproof.circuit checks the membership in a tree of depth 2
+	circuitFile, err := os.Open("../circuitexamples/merkleproof.circuit")
+	assert.Nil(t, err)
+	defer circuitFile.Close()
+	parser := circuitcompiler.NewParser(bufio.NewReader(circuitFile))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	fmt.Println(report)
+
+	mt, err := NewMerkleTree(2, []*big.Int{big.NewInt(int64(10)), big.NewInt(int64(11)), big.NewInt(int64(12))})
+	assert.Nil(t, err)
+	leaf := big.NewInt(int64(11))
+	siblings, pathBits, err := mt.GenerateProof(1)
+	assert.Nil(t, err)
+	privateInputs := []*big.Int{leaf, siblings[0], pathBits[0], siblings[1], pathBits[1]}
+	publicSignals := []*big.Int{mt.Root()}
+
+	w, err := optimized.CalculateWitness(privateInputs, publicSignals)
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	alphas, betas, gammas, _ := groth16.Utils.PF.R1CSToQAP(optimized.R1CS.A, optimized.R1CS.B, optimized.R1CS.C)
+	_, _, _, px := groth16.Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+	setup, err := groth16.GenerateTrustedSetup(nil, len(w), *optimized, alphas, betas, gammas)
+	assert.Nil(t, err)
+	proof, err := groth16.GenerateProofs(nil, *optimized, setup, w, px)
+	assert.Nil(t, err)
+
+	verified, err := groth16.VerifyProof(*optimized, setup, proof, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	verified, err = groth16.VerifyProof(*optimized, setup, proof, []*big.Int{leaf}, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+}
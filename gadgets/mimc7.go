@@ -0,0 +1,115 @@
+package gadgets
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/internal/keccak"
+)
+
+// MiMC7Seed is the seed used to generate the MiMC-7 round constants, the same than in circomlib
+const MiMC7Seed = "mimc"
+
+// MiMC7Rounds is the number of rounds of the MiMC-7 permutation
+const MiMC7Rounds = 91
+
+var mimc7Constants = mimc7GetConstants(MiMC7Seed, MiMC7Rounds)
+
+// mimc7GetConstants returns the MiMC-7 round constants, being the first one 0 and the rest the chained keccak256 hashes of the seed
+func mimc7GetConstants(seed string, nRounds int) []*big.Int {
+	cts := make([]*big.Int, nRounds)
+	cts[0] = big.NewInt(int64(0))
+	c := new(big.Int).SetBytes(keccak.Hash([]byte(seed)))
+	for i := 1; i < nRounds; i++ {
+		c = new(big.Int).SetBytes(keccak.Hash(c.Bytes()))
+		cts[i] = new(big.Int).Mod(c, Utils.FqR.Q)
+	}
+	return cts
+}
+
+// MiMC7Hash computes the MiMC-7 hash of x with the key k, as the circomlib MiMC7(91) template
+func MiMC7Hash(x, k *big.Int) *big.Int {
+	fq := Utils.FqR
+	var r *big.Int
+	for i := 0; i < MiMC7Rounds; i++ {
+		var t *big.Int
+		if i == 0 {
+			t = fq.Add(x, k)
+		} else {
+			t = fq.Add(fq.Add(r, k), mimc7Constants[i])
+		}
+		t2 := fq.Square(t)
+		t4 := fq.Square(t2)
+		r = fq.Mul(fq.Mul(t4, t2), t)
+	}
+	return fq.Add(r, k)
+}
+
+// MiMC7MultiHash computes the MiMC-7 hash of multiple elements with the given key (which can be nil), as the circomlib MultiMiMC7 template
+func MiMC7MultiHash(arr []*big.Int, key *big.Int) *big.Int {
+	fq := Utils.FqR
+	r := big.NewInt(int64(0))
+	if key != nil {
+		r = fq.Affine(key)
+	}
+	for i := 0; i < len(arr); i++ {
+		r = fq.Add(fq.Add(r, arr[i]), MiMC7Hash(arr[i], r))
+	}
+	return r
+}
+
+// MiMC7HashGadget adds to the Builder the constraints of the MiMC-7 hash of x with the key k, returning the Variable of the hash
+func MiMC7HashGadget(b *circuitcompiler.Builder, x, k circuitcompiler.Variable) circuitcompiler.Variable {
+	var r circuitcompiler.Variable
+	for i := 0; i < MiMC7Rounds; i++ {
+		var t circuitcompiler.Variable
+		if i == 0 {
+			t = b.Add(x, k)
+		} else {
+			t = b.Add(b.Add(r, k), b.Constant(mimc7Constants[i]))
+		}
+		t2 := b.Mul(t, t)
+		t4 := b.Mul(t2, t2)
+		r = b.Mul(b.Mul(t4, t2), t)
+	}
+	return b.Add(r, k)
+}
+
+// MiMC7MultiHashGadget adds to the Builder the constraints of the MiMC-7 hash of multiple elements with the given key, returning the Variable of the hash
+func MiMC7MultiHashGadget(b *circuitcompiler.Builder, arr []circuitcompiler.Variable, key circuitcompiler.Variable) circuitcompiler.Variable {
+	r := key
+	for i := 0; i < len(arr); i++ {
+		r = b.Add(b.Add(r, arr[i]), MiMC7HashGadget(b, arr[i], r))
+	}
+	return r
+}
+
+// MiMC7Code returns the flat code of the library imported with `import "mimc7"`, which declares the function
+// mimc7(x, k), computing the MiMC-7 hash of x with the key k
+func MiMC7Code() string {
+	var code strings.Builder
+	code.WriteString("func mimc7(private xa, private ka):\n")
+	r := ""
+	for i := 0; i < MiMC7Rounds; i++ {
+		// the signal names end with a letter, so they are still unique once
+		// the parser adds the calls count at the end when inlining the function
+		n := strconv.Itoa(i)
+		t := "t" + n + "a"
+		if i == 0 {
+			code.WriteString("\t" + t + " = xa + ka\n")
+		} else {
+			code.WriteString("\tu" + n + "a = " + r + " + ka\n")
+			code.WriteString("\t" + t + " = u" + n + "a + " + mimc7Constants[i].String() + "\n")
+		}
+		code.WriteString("\tt" + n + "b = " + t + " * " + t + "\n")
+		code.WriteString("\tt" + n + "c = t" + n + "b * t" + n + "b\n")
+		code.WriteString("\tt" + n + "d = t" + n + "c * t" + n + "b\n")
+		r = "r" + n + "a"
+		code.WriteString("\t" + r + " = t" + n + "d * " + t + "\n")
+	}
+	code.WriteString("\toutmimca = " + r + " + ka\n")
+	code.WriteString("\treturn outmimca\n")
+	return code.String()
+}
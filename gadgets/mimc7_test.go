@@ -0,0 +1,115 @@
+package gadgets
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/internal/keccak"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiMC7Constants(t *testing.T) {
+	seedHash := keccak.Hash([]byte(MiMC7Seed))
+	assert.Equal(t, "b6e489e6b37224a50bebfddbe7d89fa8fdcaa84304a70bd13f79b5d9f7951e9e", hex.EncodeToString(seedHash))
+	assert.Equal(t, MiMC7Rounds, len(mimc7Constants))
+	assert.Equal(t, 0, mimc7Constants[0].Sign())
+	c1 := new(big.Int).SetBytes(keccak.Hash(seedHash))
+	assert.Equal(t, new(big.Int).Mod(c1, Utils.FqR.Q), mimc7Constants[1])
+}
+
+func TestMiMC7Hash(t *testing.T) {
+	h := MiMC7Hash(big.NewInt(int64(1)), big.NewInt(int64(2)))
+	assert.Equal(t, "10594780656576967754230020536574539122676596303354946869887184401991294982664", h.String())
+
+	// same values than the circomlib tests
+	b12 := big.NewInt(int64(12))
+	b45 := big.NewInt(int64(45))
+	b78 := big.NewInt(int64(78))
+	b41 := big.NewInt(int64(41))
+	h = MiMC7MultiHash([]*big.Int{b12}, nil)
+	assert.Equal(t, "237c92644dbddb86d8a259e0e923aaab65a93f1ec5758b8799988894ac0958fd", hex.EncodeToString(h.Bytes()))
+	h = MiMC7MultiHash([]*big.Int{b78, b41}, nil)
+	assert.Equal(t, "067f3202335ea256ae6e6aadcd2d5f7f4b06a00b2d1e0de903980d5ab552dc70", hex.EncodeToString(h.Bytes()))
+	h = MiMC7MultiHash([]*big.Int{b12, b45}, nil)
+	assert.Equal(t, "15ff7fe9793346a17c3150804bcb36d161c8662b110c50f55ccb7113948d8879", hex.EncodeToString(h.Bytes()))
+	h = MiMC7MultiHash([]*big.Int{b12, b45, b78, b41}, nil)
+	assert.Equal(t, "284bc1f34f335933a23a433b6ff3ee179d682cd5e5e2fcdd2d964afa85104beb", hex.EncodeToString(h.Bytes()))
+}
+
+func TestMiMC7HashGadget(t *testing.T) {
+	b := circuitcompiler.NewBuilder()
+	x := b.PrivateInput("x")
+	k := b.PrivateInput("k")
+	h := b.PublicInput("h")
+	b.AssertEqual(h, MiMC7HashGadget(b, x, k))
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	hValue := MiMC7Hash(big.NewInt(int64(1)), big.NewInt(int64(2)))
+	assignment := circuitcompiler.Assignment{
+		x: big.NewInt(int64(1)),
+		k: big.NewInt(int64(2)),
+		h: hValue,
+	}
+	w, err := b.Witness(circuit, assignment)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	w[1] = big.NewInt(int64(3))
+	assert.False(t, circuit.IsSatisfied(w))
+}
+
+func TestMiMC7MultiHashGadget(t *testing.T) {
+	b := circuitcompiler.NewBuilder()
+	in0 := b.PrivateInput("in0")
+	in1 := b.PrivateInput("in1")
+	h := b.PublicInput("h")
+	b.AssertEqual(h, MiMC7MultiHashGadget(b, []circuitcompiler.Variable{in0, in1}, b.Constant(big.NewInt(int64(0)))))
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	b78 := big.NewInt(int64(78))
+	b41 := big.NewInt(int64(41))
+	w, err := b.Witness(circuit, circuitcompiler.Assignment{
+		in0: b78,
+		in1: b41,
+		h:   MiMC7MultiHash([]*big.Int{b78, b41}, nil),
+	})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+}
+
+func TestMiMC7FlatCode(t *testing.T) {
+	code := `
+	import "mimc7"
+
+	func main(private x, private k, public h):
+		r = mimc7(x, k)
+		s = mimc7(r, 5)
+		equals(h, s)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+
+	x := big.NewInt(int64(1))
+	k := big.NewInt(int64(2))
+	h := MiMC7Hash(MiMC7Hash(x, k), big.NewInt(int64(5)))
+	w, err := circuit.CalculateWitness([]*big.Int{x, k}, []*big.Int{h})
+	assert.Nil(t, err)
+	assert.Equal(t, h, w[1])
+	assert.True(t, circuit.IsSatisfied(w))
+
+	// the optimized circuit has the 4 multiplications of each round
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	assert.Equal(t, 2*4*MiMC7Rounds, report.ConstraintsAfter)
+	w, err = optimized.CalculateWitness([]*big.Int{x, k}, []*big.Int{h})
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+}
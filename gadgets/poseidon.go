@@ -0,0 +1,293 @@
+package gadgets
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// PoseidonFullRounds is the number of full rounds of the Poseidon permutation
+const PoseidonFullRounds = 8
+
+// PoseidonMaxInputs is the maximum number of inputs of the Poseidon hash
+const PoseidonMaxInputs = 16
+
+// PoseidonCodeMaxInputs is the maximum number of inputs of the functions declared in the flat code library
+const PoseidonCodeMaxInputs = 4
+
+// poseidonPartialRounds contains the number of partial rounds for each width t, starting at t=2, as in circomlib
+var poseidonPartialRounds = []int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+// poseidonConstants holds the round constants and the MDS matrix of the Poseidon permutation of width t
+type poseidonConstants struct {
+	t   int
+	nRP int
+	C   []*big.Int
+	M   [][]*big.Int
+}
+
+var poseidonCache = struct {
+	sync.Mutex
+	constants map[int]*poseidonConstants
+}{constants: make(map[int]*poseidonConstants)}
+
+// grain is the Grain LFSR used in the Poseidon reference implementation to generate the parameters
+type grain struct {
+	state [80]byte
+	pos   int
+}
+
+func newGrain(t, nRF, nRP int) *grain {
+	g := &grain{}
+	i := 0
+	appendBits := func(v, n int) {
+		for j := n - 1; j >= 0; j-- {
+			g.state[i] = byte((v >> uint(j)) & 1)
+			i++
+		}
+	}
+	appendBits(1, 2)    // prime field
+	appendBits(0, 4)    // x^alpha sbox
+	appendBits(254, 12) // field size
+	appendBits(t, 12)
+	appendBits(nRF, 10)
+	appendBits(nRP, 10)
+	appendBits((1<<30)-1, 30)
+	for j := 0; j < 160; j++ {
+		g.update()
+	}
+	return g
+}
+
+func (g *grain) update() byte {
+	s := &g.state
+	p := g.pos
+	b := s[(p+62)%80] ^ s[(p+51)%80] ^ s[(p+38)%80] ^ s[(p+23)%80] ^ s[(p+13)%80] ^ s[p]
+	s[p] = b
+	g.pos = (p + 1) % 80
+	return b
+}
+
+// bit returns the next output bit, taking the second bit of each pair of bits that starts with 1
+func (g *grain) bit() byte {
+	for {
+		b1 := g.update()
+		b2 := g.update()
+		if b1 == 1 {
+			return b2
+		}
+	}
+}
+
+func (g *grain) bigInt(n int) *big.Int {
+	r := big.NewInt(int64(0))
+	for i := 0; i < n; i++ {
+		r.Lsh(r, 1)
+		if g.bit() == 1 {
+			r.SetBit(r, 0, 1)
+		}
+	}
+	return r
+}
+
+// getPoseidonConstants returns the parameters of the Poseidon permutation of width t, generating them the first time
+func getPoseidonConstants(t int) *poseidonConstants {
+	poseidonCache.Lock()
+	defer poseidonCache.Unlock()
+	if c, ok := poseidonCache.constants[t]; ok {
+		return c
+	}
+	fq := Utils.FqR
+	nRP := poseidonPartialRounds[t-2]
+	g := newGrain(t, PoseidonFullRounds, nRP)
+
+	c := &poseidonConstants{t: t, nRP: nRP}
+	for i := 0; i < (PoseidonFullRounds+nRP)*t; i++ {
+		v := g.bigInt(254)
+		for v.Cmp(fq.Q) >= 0 {
+			v = g.bigInt(254)
+		}
+		c.C = append(c.C, v)
+	}
+	// MDS matrix, as the Cauchy matrix 1/(x_i + y_j)
+	var xy []*big.Int
+	for i := 0; i < 2*t; i++ {
+		xy = append(xy, new(big.Int).Mod(g.bigInt(254), fq.Q))
+	}
+	for i := 0; i < t; i++ {
+		var row []*big.Int
+		for j := 0; j < t; j++ {
+			row = append(row, fq.Inverse(fq.Add(xy[i], xy[t+j])))
+		}
+		c.M = append(c.M, row)
+	}
+	poseidonCache.constants[t] = c
+	return c
+}
+
+func checkPoseidonInputs(n int) error {
+	if n == 0 {
+		return errors.New("poseidon: no inputs")
+	}
+	if n > PoseidonMaxInputs {
+		return errors.New("poseidon: too many inputs, max " + strconv.Itoa(PoseidonMaxInputs))
+	}
+	return nil
+}
+
+func isFullRound(c *poseidonConstants, r int) bool {
+	return r < PoseidonFullRounds/2 || r >= PoseidonFullRounds/2+c.nRP
+}
+
+// PoseidonHash computes the Poseidon hash of the inputs, as the circomlib Poseidon(nInputs) template
+func PoseidonHash(inputs []*big.Int) (*big.Int, error) {
+	if err := checkPoseidonInputs(len(inputs)); err != nil {
+		return nil, err
+	}
+	fq := Utils.FqR
+	state := []*big.Int{big.NewInt(int64(0))}
+	for _, in := range inputs {
+		if in.Sign() < 0 || in.Cmp(fq.Q) >= 0 {
+			return nil, errors.New("poseidon: input not inside the Finite Field")
+		}
+		state = append(state, in)
+	}
+	c := getPoseidonConstants(len(state))
+	t := c.t
+	pow5 := func(a *big.Int) *big.Int {
+		return fq.Mul(a, fq.Square(fq.Square(a)))
+	}
+	for r := 0; r < PoseidonFullRounds+c.nRP; r++ {
+		for i := 0; i < t; i++ {
+			state[i] = fq.Add(state[i], c.C[r*t+i])
+		}
+		if isFullRound(c, r) {
+			for i := 0; i < t; i++ {
+				state[i] = pow5(state[i])
+			}
+		} else {
+			state[0] = pow5(state[0])
+		}
+		newState := make([]*big.Int, t)
+		for i := 0; i < t; i++ {
+			newState[i] = big.NewInt(int64(0))
+			for j := 0; j < t; j++ {
+				newState[i] = fq.Add(newState[i], fq.Mul(c.M[i][j], state[j]))
+			}
+		}
+		state = newState
+	}
+	return state[0], nil
+}
+
+// PoseidonHashGadget adds to the Builder the constraints of the Poseidon hash of the inputs, returning the Variable of the hash
+func PoseidonHashGadget(b *circuitcompiler.Builder, inputs []circuitcompiler.Variable) (circuitcompiler.Variable, error) {
+	if err := checkPoseidonInputs(len(inputs)); err != nil {
+		return circuitcompiler.Variable{}, err
+	}
+	state := []circuitcompiler.Variable{b.Constant(big.NewInt(int64(0)))}
+	state = append(state, inputs...)
+	c := getPoseidonConstants(len(state))
+	t := c.t
+	pow5 := func(a circuitcompiler.Variable) circuitcompiler.Variable {
+		a2 := b.Mul(a, a)
+		a4 := b.Mul(a2, a2)
+		return b.Mul(a4, a)
+	}
+	nRounds := PoseidonFullRounds + c.nRP
+	for r := 0; r < nRounds; r++ {
+		for i := 0; i < t; i++ {
+			state[i] = b.Add(state[i], b.Constant(c.C[r*t+i]))
+		}
+		if isFullRound(c, r) {
+			for i := 0; i < t; i++ {
+				state[i] = pow5(state[i])
+			}
+		} else {
+			state[0] = pow5(state[0])
+		}
+		newState := make([]circuitcompiler.Variable, t)
+		for i := 0; i < t; i++ {
+			if r == nRounds-1 && i > 0 {
+				// only the first element of the last state is the output
+				break
+			}
+			newState[i] = b.Mul(b.Constant(c.M[i][0]), state[0])
+			for j := 1; j < t; j++ {
+				newState[i] = b.Add(newState[i], b.Mul(b.Constant(c.M[i][j]), state[j]))
+			}
+		}
+		state = newState
+	}
+	return state[0], nil
+}
+
+// poseidonFuncCode returns the flat code of the function poseidon<nInputs>
+func poseidonFuncCode(nInputs int) string {
+	var code strings.Builder
+	c := getPoseidonConstants(nInputs + 1)
+	t := c.t
+
+	// the signal names end with a letter, so they are still unique once
+	// the parser adds the calls count at the end when inlining the function
+	state := []string{"0"}
+	var params []string
+	for i := 0; i < nInputs; i++ {
+		in := "in" + strconv.Itoa(i) + "a"
+		state = append(state, in)
+		params = append(params, "private "+in)
+	}
+	code.WriteString("func poseidon" + strconv.Itoa(nInputs) + "(" + strings.Join(params, ", ") + "):\n")
+	line := func(out, v1, op, v2 string) {
+		code.WriteString("\t" + out + " = " + v1 + " " + op + " " + v2 + "\n")
+	}
+	nRounds := PoseidonFullRounds + c.nRP
+	for r := 0; r < nRounds; r++ {
+		pr := "p" + strconv.Itoa(r)
+		for i := 0; i < t; i++ {
+			name := pr + "n" + strconv.Itoa(i)
+			line(name+"a", state[i], "+", c.C[r*t+i].String())
+			state[i] = name + "a"
+			if isFullRound(c, r) || i == 0 {
+				line(name+"b", name+"a", "*", name+"a")
+				line(name+"c", name+"b", "*", name+"b")
+				line(name+"d", name+"c", "*", name+"a")
+				state[i] = name + "d"
+			}
+		}
+		newState := make([]string, t)
+		for i := 0; i < t; i++ {
+			if r == nRounds-1 && i > 0 {
+				// only the first element of the last state is the output
+				break
+			}
+			for j := 0; j < t; j++ {
+				name := pr + "m" + strconv.Itoa(i) + "n" + strconv.Itoa(j)
+				line(name+"e", c.M[i][j].String(), "*", state[j])
+				if j == 0 {
+					newState[i] = name + "e"
+					continue
+				}
+				line(name+"f", newState[i], "+", name+"e")
+				newState[i] = name + "f"
+			}
+		}
+		state = newState
+	}
+	code.WriteString("\treturn " + state[0] + "\n")
+	return code.String()
+}
+
+// PoseidonCode returns the flat code of the library imported with `import "poseidon"`, which declares the
+// functions poseidon1(a), poseidon2(a, b), ... up to PoseidonCodeMaxInputs inputs, computing the Poseidon hash of the inputs
+func PoseidonCode() string {
+	var code strings.Builder
+	for n := 1; n <= PoseidonCodeMaxInputs; n++ {
+		code.WriteString(poseidonFuncCode(n))
+	}
+	return code.String()
+}
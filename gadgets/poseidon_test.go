@@ -0,0 +1,113 @@
+package gadgets
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoseidonConstants(t *testing.T) {
+	c := getPoseidonConstants(3)
+	assert.Equal(t, (PoseidonFullRounds+57)*3, len(c.C))
+	assert.Equal(t, "0ee9a592ba9a9518d05986d656f40c2114c4993c11bb29938d21d47304cd8e6e", hex.EncodeToString(c.C[0].Bytes()))
+	assert.Equal(t, "109b7f411ba0e4c9b2b70caf5c36a7b194be7c11ad24378bfedb68592ba8118b", hex.EncodeToString(c.M[0][0].Bytes()))
+
+	c = getPoseidonConstants(2)
+	assert.Equal(t, "09c46e9ec68e9bd4fe1faaba294cba38a71aa177534cdd1b6c7dc0dbd0abd7a7", hex.EncodeToString(c.C[0].Bytes()))
+}
+
+func TestPoseidonHash(t *testing.T) {
+	b1 := big.NewInt(int64(1))
+	b2 := big.NewInt(int64(2))
+	b3 := big.NewInt(int64(3))
+	b4 := big.NewInt(int64(4))
+
+	// same values than the circomlib tests
+	h, err := PoseidonHash([]*big.Int{b1})
+	assert.Nil(t, err)
+	assert.Equal(t, "18586133768512220936620570745912940619677854269274689475585506675881198879027", h.String())
+	h, err = PoseidonHash([]*big.Int{b1, b2})
+	assert.Nil(t, err)
+	assert.Equal(t, "7853200120776062878684798364095072458815029376092732009249414926327459813530", h.String())
+	h, err = PoseidonHash([]*big.Int{b3, b4})
+	assert.Nil(t, err)
+	assert.Equal(t, "14763215145315200506921711489642608356394854266165572616578112107564877678998", h.String())
+	h, err = PoseidonHash([]*big.Int{b1, b2, b3, b4})
+	assert.Nil(t, err)
+	assert.Equal(t, "18821383157269793795438455681495246036402687001665670618754263018637548127333", h.String())
+
+	_, err = PoseidonHash([]*big.Int{})
+	assert.NotNil(t, err)
+	_, err = PoseidonHash(make([]*big.Int, PoseidonMaxInputs+1))
+	assert.NotNil(t, err)
+	_, err = PoseidonHash([]*big.Int{Utils.FqR.Q})
+	assert.NotNil(t, err)
+}
+
+func TestPoseidonHashGadget(t *testing.T) {
+	b := circuitcompiler.NewBuilder()
+	a := b.PrivateInput("a")
+	c := b.PrivateInput("c")
+	h := b.PublicInput("h")
+	out, err := PoseidonHashGadget(b, []circuitcompiler.Variable{a, c})
+	assert.Nil(t, err)
+	b.AssertEqual(h, out)
+	circuit, err := b.Build()
+	assert.Nil(t, err)
+
+	hValue, err := PoseidonHash([]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(2))})
+	assert.Nil(t, err)
+	w, err := b.Witness(circuit, circuitcompiler.Assignment{
+		a: big.NewInt(int64(1)),
+		c: big.NewInt(int64(2)),
+		h: hValue,
+	})
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	_, err = PoseidonHashGadget(b, []circuitcompiler.Variable{})
+	assert.NotNil(t, err)
+}
+
+func TestPoseidonFlatCode(t *testing.T) {
+	code := `
+	import "poseidon"
+
+	func main(private a, private b, private c, public h):
+		d = poseidon2(a, b)
+		e = poseidon3(d, c, 7)
+		f = poseidon1(e)
+		equals(h, f)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	a := big.NewInt(int64(1))
+	b := big.NewInt(int64(2))
+	c := big.NewInt(int64(3))
+	d, err := PoseidonHash([]*big.Int{a, b})
+	assert.Nil(t, err)
+	e, err := PoseidonHash([]*big.Int{d, c, big.NewInt(int64(7))})
+	assert.Nil(t, err)
+	h, err := PoseidonHash([]*big.Int{e})
+	assert.Nil(t, err)
+
+	optimized, report, err := circuit.Optimize()
+	assert.Nil(t, err)
+	// each full round has t sboxes and each partial round one sbox, with 3 multiplications each,
+	// except the 4 sboxes of the first round over constant values (the initial 0 of each hash and the 7)
+	nConstraints := 3 * (PoseidonFullRounds*3 + 57 + PoseidonFullRounds*4 + 56 + PoseidonFullRounds*2 + 56 - 4)
+	assert.Equal(t, nConstraints, report.ConstraintsAfter)
+	w, err := optimized.CalculateWitness([]*big.Int{a, b, c}, []*big.Int{h})
+	assert.Nil(t, err)
+	assert.True(t, optimized.IsSatisfied(w))
+
+	w[1] = big.NewInt(int64(1))
+	assert.False(t, optimized.IsSatisfied(w))
+}
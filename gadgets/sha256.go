@@ -0,0 +1,254 @@
+package gadgets
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// SHA256BlockSize is the size in bits of the blocks of SHA-256
+const SHA256BlockSize = 512
+
+// SHA256Size is the size in bits of the SHA-256 digest and state
+const SHA256Size = 256
+
+var sha256K = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+var sha256H0 = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// BytesToBits returns the bits of the bytes, with the most significant bit of each byte first, which is
+// the order of the bits of the messages and digests of the SHA-256 gadgets
+func BytesToBits(data []byte) []*big.Int {
+	var bits []*big.Int
+	for _, d := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, big.NewInt(int64((d>>uint(i))&1)))
+		}
+	}
+	return bits
+}
+
+// word is a 32 bits word of SHA-256, starting from the least significant bit
+type word []circuitcompiler.Variable
+
+func constantWord(b *circuitcompiler.Builder, v uint32) word {
+	w := make(word, 32)
+	for i := 0; i < 32; i++ {
+		w[i] = b.Constant(big.NewInt(int64((v >> uint(i)) & 1)))
+	}
+	return w
+}
+
+// bitsToWords returns the words of the bits, given with the most significant bit first
+func bitsToWords(bits []circuitcompiler.Variable) []word {
+	var words []word
+	for i := 0; i < len(bits); i += 32 {
+		w := make(word, 32)
+		for j := 0; j < 32; j++ {
+			w[j] = bits[i+31-j]
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// wordsToBits returns the bits of the words, with the most significant bit first
+func wordsToBits(words []word) []circuitcompiler.Variable {
+	var bits []circuitcompiler.Variable
+	for _, w := range words {
+		for j := 31; j >= 0; j-- {
+			bits = append(bits, w[j])
+		}
+	}
+	return bits
+}
+
+func rotr(w word, n int) word {
+	r := make(word, 32)
+	for i := 0; i < 32; i++ {
+		r[i] = w[(i+n)%32]
+	}
+	return r
+}
+
+func shr(b *circuitcompiler.Builder, w word, n int) word {
+	r := make(word, 32)
+	for i := 0; i < 32; i++ {
+		if i+n < 32 {
+			r[i] = w[i+n]
+		} else {
+			r[i] = b.Constant(big.NewInt(int64(0)))
+		}
+	}
+	return r
+}
+
+// xor returns x + y - 2xy, for bits x and y
+func xor(b *circuitcompiler.Builder, x, y circuitcompiler.Variable) circuitcompiler.Variable {
+	return b.Sub(b.Add(x, y), b.Mul(b.Constant(big.NewInt(int64(2))), b.Mul(x, y)))
+}
+
+func xor3Words(b *circuitcompiler.Builder, x, y, z word) word {
+	r := make(word, 32)
+	for i := 0; i < 32; i++ {
+		r[i] = xor(b, xor(b, x[i], y[i]), z[i])
+	}
+	return r
+}
+
+// ch returns (e AND f) XOR (NOT e AND g), computed as e * (f - g) + g
+func ch(b *circuitcompiler.Builder, e, f, g word) word {
+	r := make(word, 32)
+	for i := 0; i < 32; i++ {
+		r[i] = b.Add(b.Mul(e[i], b.Sub(f[i], g[i])), g[i])
+	}
+	return r
+}
+
+// maj returns (a AND b) XOR (a AND c) XOR (b AND c), computed as ab + c * (a + b - 2ab)
+func maj(b *circuitcompiler.Builder, x, y, z word) word {
+	r := make(word, 32)
+	for i := 0; i < 32; i++ {
+		xy := b.Mul(x[i], y[i])
+		r[i] = b.Add(xy, b.Mul(z[i], b.Sub(b.Add(x[i], y[i]), b.Mul(b.Constant(big.NewInt(int64(2))), xy))))
+	}
+	return r
+}
+
+// addWords returns the sum modulo 2^32 of the words and the constant c, decomposing
+// into bits the sum over the Finite Field and taking the 32 least significant bits
+func addWords(b *circuitcompiler.Builder, c uint32, words ...word) word {
+	sum := b.Constant(big.NewInt(int64(c)))
+	for _, w := range words {
+		for i := 0; i < 32; i++ {
+			sum = b.Add(sum, b.Mul(b.Constant(new(big.Int).Lsh(big.NewInt(int64(1)), uint(i))), w[i]))
+		}
+	}
+	// the sum of the len(words) + 1 terms needs len(words) more bits than one word
+	nBits := 32 + big.NewInt(int64(len(words))).BitLen()
+	return b.ToBits(sum, nBits)[:32]
+}
+
+func sha256Compress(b *circuitcompiler.Builder, h []word, block []word) []word {
+	w := make([]word, 64)
+	copy(w, block)
+	for t := 16; t < 64; t++ {
+		s0 := xor3Words(b, rotr(w[t-15], 7), rotr(w[t-15], 18), shr(b, w[t-15], 3))
+		s1 := xor3Words(b, rotr(w[t-2], 17), rotr(w[t-2], 19), shr(b, w[t-2], 10))
+		w[t] = addWords(b, 0, w[t-16], s0, w[t-7], s1)
+	}
+
+	v := make([]word, 8)
+	copy(v, h)
+	for t := 0; t < 64; t++ {
+		s1 := xor3Words(b, rotr(v[4], 6), rotr(v[4], 11), rotr(v[4], 25))
+		chW := ch(b, v[4], v[5], v[6])
+		s0 := xor3Words(b, rotr(v[0], 2), rotr(v[0], 13), rotr(v[0], 22))
+		majW := maj(b, v[0], v[1], v[2])
+		// T1 = h + S1 + ch + K[t] + W[t], T2 = S0 + maj, e = d + T1, a = T1 + T2
+		e := addWords(b, sha256K[t], v[3], v[7], s1, chW, w[t])
+		a := addWords(b, sha256K[t], v[7], s1, chW, w[t], s0, majW)
+		v = []word{a, v[0], v[1], v[2], e, v[4], v[5], v[6]}
+	}
+
+	r := make([]word, 8)
+	for i := 0; i < 8; i++ {
+		r[i] = addWords(b, 0, h[i], v[i])
+	}
+	return r
+}
+
+// SHA256CompressGadget adds to the Builder the constraints of the SHA-256 compression function, returning the bits of
+// the new state for the given state of SHA256Size bits and block of SHA256BlockSize bits. All the bits are given with the
+// most significant bit first, and the bits of the state and the block must be constrained to be 0 or 1.
+func SHA256CompressGadget(b *circuitcompiler.Builder, state []circuitcompiler.Variable, block []circuitcompiler.Variable) ([]circuitcompiler.Variable, error) {
+	if len(state) != SHA256Size {
+		return nil, errors.New("sha256: state must have 256 bits")
+	}
+	if len(block) != SHA256BlockSize {
+		return nil, errors.New("sha256: block must have 512 bits")
+	}
+	return wordsToBits(sha256Compress(b, bitsToWords(state), bitsToWords(block))), nil
+}
+
+// SHA256HashGadget adds to the Builder the constraints of the SHA-256 hash of the message bits (with the most significant
+// bit of each byte first, as returned by BytesToBits), returning the bits of the digest. The message bits are constrained
+// to be 0 or 1, and the padding is added as constants, as the length of the message is known when defining the circuit.
+func SHA256HashGadget(b *circuitcompiler.Builder, msg []circuitcompiler.Variable) []circuitcompiler.Variable {
+	bits := make([]circuitcompiler.Variable, len(msg))
+	for i, bit := range msg {
+		if !bit.IsConstant() {
+			assertBit(b, bit)
+		}
+		bits[i] = bit
+	}
+	// padding: 1, the zeros up to 448 mod 512, and the length in bits as a 64 bits big-endian number
+	bits = append(bits, b.Constant(big.NewInt(int64(1))))
+	for len(bits)%SHA256BlockSize != SHA256BlockSize-64 {
+		bits = append(bits, b.Constant(big.NewInt(int64(0))))
+	}
+	l := uint64(len(msg))
+	for i := 63; i >= 0; i-- {
+		bits = append(bits, b.Constant(big.NewInt(int64((l>>uint(i))&1))))
+	}
+
+	h := make([]word, 8)
+	for i := 0; i < 8; i++ {
+		h[i] = constantWord(b, sha256H0[i])
+	}
+	words := bitsToWords(bits)
+	for i := 0; i < len(words); i += 16 {
+		h = sha256Compress(b, h, words[i:i+16])
+	}
+	return wordsToBits(h)
+}
+
+// SHA256LibraryBytes is the size in bytes of the message of the `sha256bytes32` function of `import "sha256"`
+const SHA256LibraryBytes = 32
+
+// SHA256ToField returns the digest as a big-endian number reduced modulo R, which is the value returned by the SHA-256
+// functions of the flat code
+func SHA256ToField(digest []byte) *big.Int {
+	return Utils.FqR.Affine(new(big.Int).SetBytes(digest))
+}
+
+// SHA256Code returns the flat code of the function `sha256bytesN`, where N is the given number of bytes, that takes the
+// N bytes of the message as its parameters and returns the SHA-256 digest as a big-endian number reduced modulo R (as
+// returned by SHA256ToField). Each byte is constrained to fit in 8 bits.
+func SHA256Code(nBytes int) (string, error) {
+	if nBytes < 1 {
+		return "", errors.New("sha256: the message must have at least 1 byte")
+	}
+	b := circuitcompiler.NewBuilder()
+	var msg []circuitcompiler.Variable
+	for i := 0; i < nBytes; i++ {
+		bits := b.ToBits(b.PrivateInput("m"+strconv.Itoa(i)), 8)
+		for j := 7; j >= 0; j-- {
+			msg = append(msg, bits[j])
+		}
+	}
+	digest := SHA256HashGadget(b, msg)
+	var out circuitcompiler.Variable
+	for i, bit := range digest {
+		term := b.Mul(b.Constant(new(big.Int).Lsh(big.NewInt(int64(1)), uint(SHA256Size-1-i))), bit)
+		if i == 0 {
+			out = term
+			continue
+		}
+		out = b.Add(out, term)
+	}
+	return b.FuncCode("sha256bytes"+strconv.Itoa(nBytes), out)
+}
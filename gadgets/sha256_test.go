@@ -0,0 +1,148 @@
+package gadgets
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesToBits(t *testing.T) {
+	bits := BytesToBits([]byte{0x80, 0x05})
+	assert.Equal(t, 16, len(bits))
+	assert.Equal(t, big.NewInt(int64(1)), bits[0])
+	assert.Equal(t, big.NewInt(int64(0)), bits[1])
+	assert.Equal(t, big.NewInt(int64(1)), bits[13])
+	assert.Equal(t, big.NewInt(int64(1)), bits[15])
+}
+
+// sha256Circuit returns a Builder that checks that the public digest is the SHA-256 hash of the private message of n bytes
+func sha256Circuit(n int) (*circuitcompiler.Builder, []circuitcompiler.Variable, []circuitcompiler.Variable) {
+	b := circuitcompiler.NewBuilder()
+	var msg, digest []circuitcompiler.Variable
+	for i := 0; i < SHA256Size; i++ {
+		digest = append(digest, b.PublicInput(fmt.Sprintf("d%d", i)))
+	}
+	for i := 0; i < 8*n; i++ {
+		msg = append(msg, b.PrivateInput(fmt.Sprintf("m%d", i)))
+	}
+	out := SHA256HashGadget(b, msg)
+	for i := range out {
+		b.AssertEqual(digest[i], out[i])
+	}
+	return b, msg, digest
+}
+
+func sha256Assignment(msg, digest []circuitcompiler.Variable, data []byte, h []byte) circuitcompiler.Assignment {
+	assignment := circuitcompiler.Assignment{}
+	for i, bit := range BytesToBits(data) {
+		assignment[msg[i]] = bit
+	}
+	for i, bit := range BytesToBits(h) {
+		assignment[digest[i]] = bit
+	}
+	return assignment
+}
+
+func TestSHA256HashGadget(t *testing.T) {
+	// the messages of 56 and 130 bytes need 2 and 3 blocks
+	for _, data := range [][]byte{
+		[]byte("abc"),
+		[]byte("abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq"),
+		[]byte("The quick brown fox jumps over the lazy dog. The quick brown fox jumps over the lazy dog. The quick brown fox jumps over the lazy dog!!"),
+	} {
+		b, msg, digest := sha256Circuit(len(data))
+		h := sha256.Sum256(data)
+		assignment := sha256Assignment(msg, digest, data, h[:])
+		assert.Nil(t, b.Check(assignment))
+
+		// wrong digest
+		h[31] ^= 1
+		assignment = sha256Assignment(msg, digest, data, h[:])
+		assert.NotNil(t, b.Check(assignment))
+	}
+
+	// the message bits must be 0 or 1
+	data := []byte("abc")
+	b, msg, digest := sha256Circuit(len(data))
+	h := sha256.Sum256(data)
+	assignment := sha256Assignment(msg, digest, data, h[:])
+	assignment[msg[0]] = big.NewInt(int64(2))
+	assert.NotNil(t, b.Check(assignment))
+}
+
+func TestSHA256CompressGadget(t *testing.T) {
+	b := circuitcompiler.NewBuilder()
+	var block []circuitcompiler.Variable
+	for i := 0; i < SHA256BlockSize; i++ {
+		block = append(block, b.PrivateInput(fmt.Sprintf("m%d", i)))
+	}
+	// the initial state of SHA-256
+	var words []word
+	for i := 0; i < 8; i++ {
+		words = append(words, constantWord(b, sha256H0[i]))
+	}
+	state := wordsToBits(words)
+	out, err := SHA256CompressGadget(b, state, block)
+	assert.Nil(t, err)
+	var digest []circuitcompiler.Variable
+	for i := 0; i < SHA256Size; i++ {
+		digest = append(digest, b.PublicInput(fmt.Sprintf("d%d", i)))
+		b.AssertEqual(digest[i], out[i])
+	}
+
+	// the padded block of "abc"
+	padded := make([]byte, 64)
+	copy(padded, []byte("abc"))
+	padded[3] = 0x80
+	padded[63] = 24
+	h := sha256.Sum256([]byte("abc"))
+	assert.Nil(t, b.Check(sha256Assignment(block, digest, padded, h[:])))
+
+	_, err = SHA256CompressGadget(b, state[1:], block)
+	assert.NotNil(t, err)
+	_, err = SHA256CompressGadget(b, state, block[1:])
+	assert.NotNil(t, err)
+}
+
+func TestSHA256Code(t *testing.T) {
+	data := []byte("The quick brown fox jumps over t")
+	assert.Equal(t, SHA256LibraryBytes, len(data))
+	h := sha256.Sum256(data)
+	flat := "import \"sha256\"\nfunc main("
+	for i := range data {
+		flat += fmt.Sprintf("private m%d, ", i)
+	}
+	flat += "public h):\n\td = sha256bytes32("
+	for i := range data {
+		if i > 0 {
+			flat += ", "
+		}
+		flat += fmt.Sprintf("m%d", i)
+	}
+	flat += ")\n\tequals(h, d)\n\tout = 1 * 1\n"
+	parser := circuitcompiler.NewParser(strings.NewReader(flat))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	var privateInputs []*big.Int
+	for _, d := range data {
+		privateInputs = append(privateInputs, big.NewInt(int64(d)))
+	}
+	w, err := circuit.CalculateWitness(privateInputs, []*big.Int{SHA256ToField(h[:])})
+	assert.Nil(t, err)
+	assert.Equal(t, SHA256ToField(h[:]), w[1])
+	assert.True(t, circuit.IsSatisfied(w))
+
+	// wrong digest
+	h[31] ^= 1
+	w[1] = SHA256ToField(h[:])
+	assert.False(t, circuit.IsSatisfied(w))
+
+	_, err = SHA256Code(0)
+	assert.NotNil(t, err)
+}
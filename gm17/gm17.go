@@ -0,0 +1,295 @@
+// implementation of https://eprint.iacr.org/2017/540.pdf, over the Square Arithmetic Program of the circuit
+
+package gm17
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/r1csqap"
+)
+
+// Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed
+type Setup struct {
+	Toxic struct {
+		T      *big.Int // trusted setup secret
+		Kalpha *big.Int
+		Kbeta  *big.Int
+		Kgamma *big.Int
+	}
+
+	// public
+	Pk struct { // Proving Key
+		Z  []*big.Int
+		G1 struct {
+			GammaU          [][3]*big.Int // {γ u_i(τ)} from 0 to m
+			GammaZ          [3]*big.Int   // γ Z(τ)
+			ABGammaZ        [3]*big.Int   // (α+β) γ Z(τ)
+			Gamma2Z2        [3]*big.Int   // γ² Z(τ)²
+			Gamma2ZU        [][3]*big.Int // {2 γ² Z(τ) u_i(τ)} from 0 to m
+			ABGammaUW       [][3]*big.Int // {(α+β) γ u_i(τ) + γ² w_i(τ)} from l+1 to m
+			PowersTauGamma2 [][3]*big.Int // {γ² Z(τ) τ^i}, powers of τ encrypted in G1 curve
+		}
+		G2 struct {
+			GammaU [][3][2]*big.Int // {γ u_i(τ)} from 0 to m
+			GammaZ [3][2]*big.Int   // γ Z(τ)
+		}
+	}
+	Vk VerifyingKey
+}
+
+// VerifyingKey is the data structure of the GM17 verifying key, the public part of the Setup used by the verifiers
+type VerifyingKey struct {
+	IC [][3]*big.Int // {(α+β) u_i(τ) + γ w_i(τ)} from 0 to l
+	G1 struct {
+		Alpha [3]*big.Int
+		Gamma [3]*big.Int
+	}
+	G2 struct {
+		Beta  [3][2]*big.Int
+		Gamma [3][2]*big.Int
+	}
+}
+
+// Proof contains the parameters to proof the zkSNARK
+type Proof struct {
+	PiA [3]*big.Int
+	PiB [3][2]*big.Int
+	PiC [3]*big.Int
+}
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+	PF  r1csqap.PolynomialField
+}
+
+// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the snarks operations
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	// new Finite Field
+	fqR := fields.NewFq(bn.R)
+	// new Polynomial Field
+	pf := r1csqap.NewPolynomialField(fqR)
+
+	return utils{
+		Bn:  bn,
+		FqR: fqR,
+		PF:  pf,
+	}
+}
+
+// GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit and the u and w polynomials of its SAP,
+// where witnessLength is the length of the SAP witness, reading the secrets from randReader, or from crypto/rand if it
+// is nil. The Setup.Toxic sub data structure must be destroyed
+func GenerateTrustedSetup(randReader io.Reader, witnessLength int, circuit circuitcompiler.Circuit, us, ws [][]*big.Int) (Setup, error) {
+	var setup Setup
+	var err error
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	if len(us) != witnessLength || len(ws) != witnessLength {
+		return Setup{}, errors.New("gm17: SAP polynomials of length " + strconv.Itoa(len(us)) + ", expected " + strconv.Itoa(witnessLength))
+	}
+	if circuit.NPublic >= witnessLength {
+		return Setup{}, errors.New("gm17: more public signals than witness variables")
+	}
+
+	// generate random t value
+	setup.Toxic.T, err = Utils.FqR.RandNonZeroFrom(randReader)
+	if err != nil {
+		return Setup{}, err
+	}
+	setup.Toxic.Kalpha, err = Utils.FqR.RandNonZeroFrom(randReader)
+	if err != nil {
+		return Setup{}, err
+	}
+	setup.Toxic.Kbeta, err = Utils.FqR.RandNonZeroFrom(randReader)
+	if err != nil {
+		return Setup{}, err
+	}
+	setup.Toxic.Kgamma, err = Utils.FqR.RandNonZeroFrom(randReader)
+	if err != nil {
+		return Setup{}, err
+	}
+
+	// z pol
+	// the number of points where the constraints are interpolated is the length of the polynomials
+	zpol := Utils.PF.ZeroPolynomial(len(us[0]))
+	setup.Pk.Z = zpol
+	zt := Utils.PF.Eval(zpol, setup.Toxic.T)
+	gamma := setup.Toxic.Kgamma
+	gamma2 := Utils.FqR.Square(gamma)
+	alphaBeta := Utils.FqR.Add(setup.Toxic.Kalpha, setup.Toxic.Kbeta)
+	gammaZ := Utils.FqR.Mul(gamma, zt)
+	gamma2Z := Utils.FqR.Mul(gamma2, zt)
+
+	setup.Pk.G1.GammaZ = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, gammaZ)
+	setup.Pk.G2.GammaZ = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, gammaZ)
+	setup.Pk.G1.ABGammaZ = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, Utils.FqR.Mul(alphaBeta, gammaZ))
+	setup.Pk.G1.Gamma2Z2 = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, Utils.FqR.Mul(gamma2Z, zt))
+
+	// powers of τ encrypted in G1 curve, times γ² Z(τ)
+	tEncr := gamma2Z
+	for i := 0; i < len(zpol)-1; i++ {
+		setup.Pk.G1.PowersTauGamma2 = append(setup.Pk.G1.PowersTauGamma2, Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, tEncr))
+		tEncr = Utils.FqR.Mul(tEncr, setup.Toxic.T)
+	}
+
+	setup.Vk.G1.Alpha = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, setup.Toxic.Kalpha)
+	setup.Vk.G1.Gamma = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, gamma)
+	setup.Vk.G2.Beta = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kbeta)
+	setup.Vk.G2.Gamma = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, gamma)
+
+	zero3 := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := 0; i < witnessLength; i++ {
+		ut := Utils.PF.Eval(us[i], setup.Toxic.T)
+		wt := Utils.PF.Eval(ws[i], setup.Toxic.T)
+		gammaU := Utils.FqR.Mul(gamma, ut)
+
+		// {γ u_i(τ)} in G1 and G2
+		setup.Pk.G1.GammaU = append(setup.Pk.G1.GammaU, Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, gammaU))
+		setup.Pk.G2.GammaU = append(setup.Pk.G2.GammaU, Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, gammaU))
+		// {2 γ² Z(τ) u_i(τ)}
+		setup.Pk.G1.Gamma2ZU = append(setup.Pk.G1.Gamma2ZU,
+			Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, Utils.FqR.Mul(big.NewInt(int64(2)), Utils.FqR.Mul(gamma2Z, ut))))
+
+		if i <= circuit.NPublic {
+			// Vk.IC: {(α+β) u_i(τ) + γ w_i(τ)} from 0 to l, used in verifier
+			ic := Utils.FqR.Add(Utils.FqR.Mul(alphaBeta, ut), Utils.FqR.Mul(gamma, wt))
+			setup.Vk.IC = append(setup.Vk.IC, Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, ic))
+			setup.Pk.G1.ABGammaUW = append(setup.Pk.G1.ABGammaUW, zero3)
+			continue
+		}
+		// Pk.G1.ABGammaUW: {(α+β) γ u_i(τ) + γ² w_i(τ)} from l+1 to m
+		c := Utils.FqR.Add(Utils.FqR.Mul(alphaBeta, gammaU), Utils.FqR.Mul(gamma2, wt))
+		setup.Pk.G1.ABGammaUW = append(setup.Pk.G1.ABGammaUW, Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, c))
+	}
+
+	return setup, nil
+}
+
+// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup, the SAP witness and the
+// P(x) = u(x)^2 - w(x) of the SAP, reading the random r from randReader, or from crypto/rand if it is nil
+func GenerateProofs(randReader io.Reader, circuit circuitcompiler.Circuit, setup Setup, w []*big.Int, px []*big.Int) (Proof, error) {
+	if len(w) != len(setup.Pk.G1.GammaU) {
+		return Proof{}, errors.New("gm17: SAP witness of length " + strconv.Itoa(len(w)) + ", expected " + strconv.Itoa(len(setup.Pk.G1.GammaU)))
+	}
+	hx, rem := Utils.PF.Div(px, setup.Pk.Z)
+	for _, c := range rem {
+		if !Utils.FqR.IsZero(c) {
+			return Proof{}, errors.New("gm17: the witness does not satisfy the SAP")
+		}
+	}
+	if len(hx) > len(setup.Pk.G1.PowersTauGamma2) {
+		return Proof{}, errors.New("gm17: P(x) of degree too big for the Setup")
+	}
+
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	var proof Proof
+	proof.PiA = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	proof.PiB = Utils.Bn.Fq6.Zero()
+	proof.PiC = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+
+	r, err := Utils.FqR.RandFrom(randReader)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	// zu will hold Σ from 0 to m (2 γ² Z(τ) u_i(τ) * w[i]), to be multiplied by r
+	zu := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := 0; i < len(w); i++ {
+		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.GammaU[i], w[i]))
+		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalarConstantTime(setup.Pk.G2.GammaU[i], w[i]))
+		zu = Utils.Bn.G1.Add(zu, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Gamma2ZU[i], w[i]))
+	}
+	for i := circuit.NPublic + 1; i < len(w); i++ {
+		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.ABGammaUW[i], w[i]))
+	}
+
+	// piA = γ (u(τ) + r Z(τ)), piB = the same in G2
+	proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.GammaZ, r))
+	proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalarConstantTime(setup.Pk.G2.GammaZ, r))
+
+	// piC = Σ from l+1 to m (w[i] * ((α+β) γ u_i(τ) + γ² w_i(τ))) + γ² h(τ) Z(τ) + r (2 γ² Z(τ) u(τ) + (α+β) γ Z(τ)) + r² γ² Z(τ)²
+	for i := 0; i < len(hx); i++ {
+		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.PowersTauGamma2[i], hx[i]))
+	}
+	zu = Utils.Bn.G1.Add(zu, setup.Pk.G1.ABGammaZ)
+	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(zu, r))
+	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Gamma2Z2, Utils.FqR.Square(r)))
+
+	return proof, nil
+}
+
+// VerifyProof verifies over the BN128 the Pairings of the Proof, returning an error if the verifying key, the Proof or
+// the public signals are malformed
+func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	verified, err := setup.Vk.Verify(proof, publicSignals)
+	if err != nil {
+		return false, err
+	}
+	if !verified {
+		if debug {
+			fmt.Println("❌ gm17 verification not passed")
+		}
+		return false, nil
+	}
+	if debug {
+		fmt.Println("✓ gm17 verification passed")
+	}
+
+	return true, nil
+}
+
+// Verify verifies the Proof against the VerifyingKey, checking e(piA + α, piB + β) == e(α, β) * e(Σ IC_i s_i, γ) * e(piC, g2)
+// and e(piA, γ) == e(γ, piB). It returns an error if the verifying key, the Proof or the public signals are malformed.
+func (vk VerifyingKey) Verify(proof Proof, publicSignals []*big.Int) (bool, error) {
+	if err := vk.Validate(); err != nil {
+		return false, err
+	}
+	if err := proof.Validate(); err != nil {
+		return false, err
+	}
+	if err := Utils.Bn.CheckPublicSignals("gm17", publicSignals, len(vk.IC)-1); err != nil {
+		return false, err
+	}
+
+	icPubl := vk.IC[0]
+	for i, s := range publicSignals {
+		icPubl = Utils.Bn.G1.Add(icPubl, Utils.Bn.G1.MulScalarGLV(vk.IC[i+1], s))
+	}
+	// piA and piB are computed over the same exponent
+	if !Utils.Bn.PairingCheck(
+		[][3]*big.Int{proof.PiA, Utils.Bn.G1.Neg(vk.G1.Gamma)},
+		[][3][2]*big.Int{vk.G2.Gamma, proof.PiB}) {
+		return false, nil
+	}
+	return Utils.Bn.PairingCheck(
+		[][3]*big.Int{
+			Utils.Bn.G1.Add(proof.PiA, vk.G1.Alpha),
+			Utils.Bn.G1.Neg(vk.G1.Alpha),
+			Utils.Bn.G1.Neg(icPubl),
+			Utils.Bn.G1.Neg(proof.PiC),
+		},
+		[][3][2]*big.Int{
+			Utils.Bn.G2.Add(proof.PiB, vk.G2.Beta),
+			vk.G2.Beta,
+			vk.G2.Gamma,
+			Utils.Bn.G2.G,
+		}), nil
+}
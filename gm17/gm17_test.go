@@ -0,0 +1,153 @@
+package gm17
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/r1csqap"
+	"github.com/stretchr/testify/assert"
+)
+
+// y = x^3 + x + 5
+var testCode = `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+
+func TestGM17MinimalFlow(t *testing.T) {
+	fmt.Println("testing GM17 minimal flow")
+	parser := circuitcompiler.NewParser(strings.NewReader(testCode))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	b3 := big.NewInt(int64(3))
+	privateInputs := []*big.Int{b3}
+	b35 := big.NewInt(int64(35))
+	publicSignals := []*big.Int{b35}
+
+	// wittness
+	w, err := circuit.CalculateWitness(privateInputs, publicSignals)
+	assert.Nil(t, err)
+
+	// code to R1CS
+	a, b, c := circuit.GenerateR1CS()
+
+	// R1CS to SAP
+	us, ws, zx := Utils.PF.R1CSToSAP(a, b, c, circuit.NPublic)
+	sw := Utils.PF.SAPWitness(a, b, w, circuit.NPublic)
+	assert.Equal(t, len(us), len(sw))
+	ux, wx, px := Utils.PF.CombineSAPPolynomials(sw, us, ws)
+	hx, rem := Utils.PF.Div(px, zx)
+	assert.Equal(t, r1csqap.ArrayOfBigZeros(len(rem)), rem)
+	assert.Equal(t, Utils.PF.Sub(Utils.PF.Mul(ux, ux), wx), Utils.PF.Mul(hx, zx))
+
+	// calculate trusted setup
+	setup, err := GenerateTrustedSetup(nil, len(sw), *circuit, us, ws)
+	assert.Nil(t, err)
+	assert.Equal(t, zx, setup.Pk.Z)
+	assert.Equal(t, circuit.NPublic+1, len(setup.Vk.IC))
+
+	proof, err := GenerateProofs(nil, *circuit, setup, sw, px)
+	assert.Nil(t, err)
+
+	before := time.Now()
+	verified, err := VerifyProof(*circuit, setup, proof, publicSignals, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	fmt.Println("verify proof time elapsed:", time.Since(before))
+
+	// check that with another public input the verification returns false
+	verified, err = VerifyProof(*circuit, setup, proof, []*big.Int{big.NewInt(int64(34))}, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// the proofs are randomized
+	proof2, err := GenerateProofs(nil, *circuit, setup, sw, px)
+	assert.Nil(t, err)
+	assert.False(t, Utils.Bn.G1.Equal(proof.PiA, proof2.PiA))
+	verified, err = VerifyProof(*circuit, setup, proof2, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	// a witness that does not satisfy the circuit
+	wrongW := append([]*big.Int{}, w...)
+	wrongW[1] = big.NewInt(int64(36))
+	wrongSW := Utils.PF.SAPWitness(a, b, wrongW, circuit.NPublic)
+	_, _, wrongPx := Utils.PF.CombineSAPPolynomials(wrongSW, us, ws)
+	_, err = GenerateProofs(nil, *circuit, setup, wrongSW, wrongPx)
+	assert.Equal(t, "gm17: the witness does not satisfy the SAP", err.Error())
+	_, err = GenerateProofs(nil, *circuit, setup, w, px)
+	assert.NotNil(t, err)
+}
+
+func TestGM17NonMalleability(t *testing.T) {
+	parser := circuitcompiler.NewParser(strings.NewReader(testCode))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	us, ws, _ := Utils.PF.R1CSToSAP(a, b, c, circuit.NPublic)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	sw := Utils.PF.SAPWitness(a, b, w, circuit.NPublic)
+	_, _, px := Utils.PF.CombineSAPPolynomials(sw, us, ws)
+	setup, err := GenerateTrustedSetup(nil, len(sw), *circuit, us, ws)
+	assert.Nil(t, err)
+	proof, err := GenerateProofs(nil, *circuit, setup, sw, px)
+	assert.Nil(t, err)
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+
+	// the transformations that rerandomize a Groth16 proof do not give valid GM17 proofs
+	k, err := Utils.FqR.Rand()
+	assert.Nil(t, err)
+	mauled := Proof{
+		PiA: Utils.Bn.G1.MulScalar(proof.PiA, k),
+		PiB: Utils.Bn.G2.MulScalar(proof.PiB, Utils.FqR.Inverse(k)),
+		PiC: proof.PiC,
+	}
+	verified, err := setup.Vk.Verify(mauled, publicSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	mauled = Proof{
+		PiA: Utils.Bn.G1.Neg(proof.PiA),
+		PiB: Utils.Bn.G2.Neg(proof.PiB),
+		PiC: proof.PiC,
+	}
+	verified, err = setup.Vk.Verify(mauled, publicSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	mauled = proof
+	mauled.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.G)
+	verified, err = setup.Vk.Verify(mauled, publicSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// malformed inputs
+	_, err = setup.Vk.Verify(proof, nil)
+	assert.Equal(t, "gm17: wrong number of public signals, expected 1, got 0", err.Error())
+	_, err = setup.Vk.Verify(proof, []*big.Int{Utils.FqR.Q})
+	assert.Equal(t, "gm17: public signal 0 not inside the Finite Field", err.Error())
+	malformed := proof
+	malformed.PiC = [3]*big.Int{proof.PiC[0], big.NewInt(int64(1)), proof.PiC[2]}
+	_, err = setup.Vk.Verify(malformed, publicSignals)
+	assert.Equal(t, "gm17: proof piC not on the curve", err.Error())
+	malformedVk := setup.Vk
+	malformedVk.G1.Gamma = [3]*big.Int{setup.Vk.G1.Gamma[0], big.NewInt(int64(1)), setup.Vk.G1.Gamma[2]}
+	_, err = malformedVk.Verify(proof, publicSignals)
+	assert.Equal(t, "gm17: verifying key G1 γ not on the curve", err.Error())
+	malformedVk = setup.Vk
+	g2Gamma := setup.Vk.G2.Gamma
+	malformedVk.G2.Gamma = [3][2]*big.Int{g2Gamma[0], Utils.Bn.Fq2.Add(g2Gamma[1], Utils.Bn.Fq2.One()), g2Gamma[2]}
+	_, err = malformedVk.Verify(proof, publicSignals)
+	assert.Equal(t, "gm17: verifying key G2 γ not on the curve", err.Error())
+}
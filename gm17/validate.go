@@ -0,0 +1,39 @@
+package gm17
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Validate checks that all the points of the VerifyingKey are in G1 and G2
+func (vk VerifyingKey) Validate() error {
+	if len(vk.IC) == 0 {
+		return errors.New("gm17: verifying key without IC")
+	}
+	for i, ic := range vk.IC {
+		if err := Utils.Bn.CheckG1("gm17", "verifying key IC["+strconv.Itoa(i)+"]", ic); err != nil {
+			return err
+		}
+	}
+	if err := Utils.Bn.CheckG1("gm17", "verifying key α", vk.G1.Alpha); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG1("gm17", "verifying key G1 γ", vk.G1.Gamma); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG2("gm17", "verifying key β", vk.G2.Beta); err != nil {
+		return err
+	}
+	return Utils.Bn.CheckG2("gm17", "verifying key G2 γ", vk.G2.Gamma)
+}
+
+// Validate checks that all the points of the Proof are in G1 and G2
+func (proof Proof) Validate() error {
+	if err := Utils.Bn.CheckG1("gm17", "proof piA", proof.PiA); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG2("gm17", "proof piB", proof.PiB); err != nil {
+		return err
+	}
+	return Utils.Bn.CheckG1("gm17", "proof piC", proof.PiC)
+}
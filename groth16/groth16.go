@@ -3,7 +3,10 @@
 package groth16
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/arnaucube/go-snark/bn128"
@@ -12,15 +15,18 @@ import (
 	"github.com/arnaucube/go-snark/r1csqap"
 )
 
+// Toxic holds the secrets of the Trusted Setup, which allow to forge proofs and must be destroyed
+type Toxic struct {
+	T      *big.Int // trusted setup secret
+	Kalpha *big.Int
+	Kbeta  *big.Int
+	Kgamma *big.Int
+	Kdelta *big.Int
+}
+
 // Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed
 type Setup struct {
-	Toxic struct {
-		T      *big.Int // trusted setup secret
-		Kalpha *big.Int
-		Kbeta  *big.Int
-		Kgamma *big.Int
-		Kdelta *big.Int
-	}
+	Toxic Toxic
 
 	// public
 	Pk struct { // Proving Key
@@ -41,19 +47,45 @@ type Setup struct {
 		}
 		PowersTauDelta [][3]*big.Int // powers of τ encrypted in G1 curve, divided by δ
 	}
-	Vk struct {
-		IC [][3]*big.Int
-		G1 struct {
-			Alpha [3]*big.Int
-		}
-		G2 struct {
-			Beta  [3][2]*big.Int
-			Gamma [3][2]*big.Int
-			Delta [3][2]*big.Int
-		}
+	Vk VerifyingKey
+}
+
+// VerifyingKey is the data structure of the Groth16 verifying key, the public part of the Setup used by the verifiers
+type VerifyingKey struct {
+	IC [][3]*big.Int
+	G1 struct {
+		Alpha [3]*big.Int
+	}
+	G2 struct {
+		Beta  [3][2]*big.Int
+		Gamma [3][2]*big.Int
+		Delta [3][2]*big.Int
+	}
+}
+
+// PreparedVerifyingKey is the VerifyingKey with the lines of the Miller loop of its fixed G2 points precomputed, to
+// verify many proofs against the same key
+type PreparedVerifyingKey struct {
+	VerifyingKey
+	G2Precomp struct {
+		Beta  bn128.AteG2Precomp
+		Gamma bn128.AteG2Precomp
+		Delta bn128.AteG2Precomp
 	}
 }
 
+// PrepareVerifyingKey validates the points of the VerifyingKey and precomputes its G2 points
+func PrepareVerifyingKey(vk VerifyingKey) (PreparedVerifyingKey, error) {
+	if err := vk.Validate(); err != nil {
+		return PreparedVerifyingKey{}, err
+	}
+	pvk := PreparedVerifyingKey{VerifyingKey: vk}
+	pvk.G2Precomp.Beta = Utils.Bn.PreComputeG2(vk.G2.Beta)
+	pvk.G2Precomp.Gamma = Utils.Bn.PreComputeG2(vk.G2.Gamma)
+	pvk.G2Precomp.Delta = Utils.Bn.PreComputeG2(vk.G2.Delta)
+	return pvk, nil
+}
+
 // Proof contains the parameters to proof the zkSNARK
 type Proof struct {
 	PiA [3]*big.Int
@@ -87,45 +119,41 @@ func prepareUtils() utils {
 	}
 }
 
-// GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit. The Setup.Toxic sub data structure must be destroyed
-func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, alphas, betas, gammas [][]*big.Int) (Setup, error) {
+// GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit, reading the secrets from randReader, or from
+// crypto/rand if it is nil. The Setup.Toxic sub data structure must be destroyed
+func GenerateTrustedSetup(randReader io.Reader, witnessLength int, circuit circuitcompiler.Circuit, alphas, betas, gammas [][]*big.Int) (Setup, error) {
 	var setup Setup
 	var err error
+	if randReader == nil {
+		randReader = rand.Reader
+	}
 
 	// generate random t value
-	setup.Toxic.T, err = Utils.FqR.Rand()
+	setup.Toxic.T, err = Utils.FqR.RandNonZeroFrom(randReader)
 	if err != nil {
 		return Setup{}, err
 	}
 
-	setup.Toxic.Kalpha, err = Utils.FqR.Rand()
+	setup.Toxic.Kalpha, err = Utils.FqR.RandNonZeroFrom(randReader)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kbeta, err = Utils.FqR.Rand()
+	setup.Toxic.Kbeta, err = Utils.FqR.RandNonZeroFrom(randReader)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kgamma, err = Utils.FqR.Rand()
+	setup.Toxic.Kgamma, err = Utils.FqR.RandNonZeroFrom(randReader)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kdelta, err = Utils.FqR.Rand()
+	setup.Toxic.Kdelta, err = Utils.FqR.RandNonZeroFrom(randReader)
 	if err != nil {
 		return Setup{}, err
 	}
 
 	// z pol
-	zpol := []*big.Int{big.NewInt(int64(1))}
-	for i := 1; i < len(alphas)-1; i++ {
-		zpol = Utils.PF.Mul(
-			zpol,
-			[]*big.Int{
-				Utils.FqR.Neg(
-					big.NewInt(int64(i))),
-				big.NewInt(int64(1)),
-			})
-	}
+	// the number of points where the constraints are interpolated is the length of the polynomials
+	zpol := Utils.PF.ZeroPolynomial(len(alphas[0]))
 	setup.Pk.Z = zpol
 	zt := Utils.PF.Eval(zpol, setup.Toxic.T)
 	invDelta := Utils.FqR.Inverse(setup.Toxic.Kdelta)
@@ -134,37 +162,37 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 	// encrypt t values with curve generators
 	// powers of tau divided by delta
 	var ptd [][3]*big.Int
-	ini := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, ztinvDelta)
+	ini := Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, ztinvDelta)
 	ptd = append(ptd, ini)
 	tEncr := setup.Toxic.T
 	for i := 1; i < len(zpol); i++ {
-		ptd = append(ptd, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, Utils.FqR.Mul(tEncr, ztinvDelta)))
+		ptd = append(ptd, Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, Utils.FqR.Mul(tEncr, ztinvDelta)))
 		tEncr = Utils.FqR.Mul(tEncr, setup.Toxic.T)
 	}
 	// powers of τ encrypted in G1 curve, divided by δ
 	// (G1 * τ) / δ
 	setup.Pk.PowersTauDelta = ptd
 
-	setup.Pk.G1.Alpha = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kalpha)
-	setup.Pk.G1.Beta = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kbeta)
-	setup.Pk.G1.Delta = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kdelta)
-	setup.Pk.G2.Beta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kbeta)
-	setup.Pk.G2.Delta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kdelta)
+	setup.Pk.G1.Alpha = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, setup.Toxic.Kalpha)
+	setup.Pk.G1.Beta = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, setup.Toxic.Kbeta)
+	setup.Pk.G1.Delta = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, setup.Toxic.Kdelta)
+	setup.Pk.G2.Beta = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kbeta)
+	setup.Pk.G2.Delta = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kdelta)
 
-	setup.Vk.G1.Alpha = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kalpha)
-	setup.Vk.G2.Beta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kbeta)
-	setup.Vk.G2.Gamma = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kgamma)
-	setup.Vk.G2.Delta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kdelta)
+	setup.Vk.G1.Alpha = Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, setup.Toxic.Kalpha)
+	setup.Vk.G2.Beta = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kbeta)
+	setup.Vk.G2.Gamma = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kgamma)
+	setup.Vk.G2.Delta = Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, setup.Toxic.Kdelta)
 
 	for i := 0; i < len(circuit.Signals); i++ {
 		// Pk.G1.At: {a(τ)} from 0 to m
 		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
-		a := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, at)
+		a := Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, at)
 		setup.Pk.G1.At = append(setup.Pk.G1.At, a)
 
 		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
-		g1bt := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bt)
-		g2bt := Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, bt)
+		g1bt := Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, bt)
+		g2bt := Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, bt)
 		// G1.BACGamma: {( βui(x)+αvi(x)+wi(x) ) / δ } from l+1 to m in G1
 		setup.Pk.G1.BACGamma = append(setup.Pk.G1.BACGamma, g1bt)
 		// G2.BACGamma: {( βui(x)+αvi(x)+wi(x) ) / δ } from l+1 to m in G2
@@ -190,7 +218,7 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 				ct,
 			),
 		)
-		g1c := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, c)
+		g1c := Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, c)
 
 		// Pk.BACDelta: {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to l
 		setup.Pk.BACDelta = append(setup.Pk.BACDelta, g1c)
@@ -210,7 +238,7 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 				ct,
 			),
 		)
-		g1ic := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, ic)
+		g1ic := Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, ic)
 		// used in verifier
 		setup.Vk.IC = append(setup.Vk.IC, g1ic)
 	}
@@ -218,18 +246,22 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 	return setup, nil
 }
 
-// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness
-func GenerateProofs(circuit circuitcompiler.Circuit, setup Setup, w []*big.Int, px []*big.Int) (Proof, error) {
+// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness, reading the
+// random r and s from randReader, or from crypto/rand if it is nil
+func GenerateProofs(randReader io.Reader, circuit circuitcompiler.Circuit, setup Setup, w []*big.Int, px []*big.Int) (Proof, error) {
+	if randReader == nil {
+		randReader = rand.Reader
+	}
 	var proof Proof
 	proof.PiA = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
 	proof.PiB = Utils.Bn.Fq6.Zero()
 	proof.PiC = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
 
-	r, err := Utils.FqR.Rand()
+	r, err := Utils.FqR.RandFrom(randReader)
 	if err != nil {
 		return Proof{}, err
 	}
-	s, err := Utils.FqR.Rand()
+	s, err := Utils.FqR.RandFrom(randReader)
 	if err != nil {
 		return Proof{}, err
 	}
@@ -238,65 +270,182 @@ func GenerateProofs(circuit circuitcompiler.Circuit, setup Setup, w []*big.Int,
 	piBG1 := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
 
 	for i := 0; i < circuit.NVars; i++ {
-		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalar(setup.Pk.G1.At[i], w[i]))
-		piBG1 = Utils.Bn.G1.Add(piBG1, Utils.Bn.G1.MulScalar(setup.Pk.G1.BACGamma[i], w[i]))
-		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalar(setup.Pk.G2.BACGamma[i], w[i]))
+		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.At[i], w[i]))
+		piBG1 = Utils.Bn.G1.Add(piBG1, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.BACGamma[i], w[i]))
+		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalarConstantTime(setup.Pk.G2.BACGamma[i], w[i]))
 	}
 	for i := circuit.NPublic + 1; i < circuit.NVars; i++ {
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(setup.Pk.BACDelta[i], w[i]))
+		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.BACDelta[i], w[i]))
 	}
 
 	// piA = (Σ from 0 to m (pk.A * w[i])) + pk.Alpha1 + r * δ
 	proof.PiA = Utils.Bn.G1.Add(proof.PiA, setup.Pk.G1.Alpha)
-	deltaR := Utils.Bn.G1.MulScalar(setup.Pk.G1.Delta, r)
+	deltaR := Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Delta, r)
 	proof.PiA = Utils.Bn.G1.Add(proof.PiA, deltaR)
 
 	// piBG1 = (Σ from 0 to m (pk.B1 * w[i])) + pk.g1.Beta + s * δ
 	// piB = piB2 = (Σ from 0 to m (pk.B2 * w[i])) + pk.g2.Beta + s * δ
 	piBG1 = Utils.Bn.G1.Add(piBG1, setup.Pk.G1.Beta)
 	proof.PiB = Utils.Bn.G2.Add(proof.PiB, setup.Pk.G2.Beta)
-	deltaSG1 := Utils.Bn.G1.MulScalar(setup.Pk.G1.Delta, s)
+	deltaSG1 := Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Delta, s)
 	piBG1 = Utils.Bn.G1.Add(piBG1, deltaSG1)
-	deltaSG2 := Utils.Bn.G2.MulScalar(setup.Pk.G2.Delta, s)
+	deltaSG2 := Utils.Bn.G2.MulScalarConstantTime(setup.Pk.G2.Delta, s)
 	proof.PiB = Utils.Bn.G2.Add(proof.PiB, deltaSG2)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z) // maybe move this calculation to a previous step
 
 	// piC = (Σ from l+1 to m (w[i] * (pk.g1.Beta + pk.g1.Alpha + pk.C)) + h(tau)) / δ) + piA*s + r*piB - r*s*δ
 	for i := 0; i < len(hx); i++ {
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(setup.Pk.PowersTauDelta[i], hx[i]))
+		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.PowersTauDelta[i], hx[i]))
 	}
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(proof.PiA, s))
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(piBG1, r))
+	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(proof.PiA, s))
+	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(piBG1, r))
 	negRS := Utils.FqR.Neg(Utils.FqR.Mul(r, s))
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(setup.Pk.G1.Delta, negRS))
+	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(setup.Pk.G1.Delta, negRS))
 
 	return proof, nil
 }
 
-// VerifyProof verifies over the BN128 the Pairings of the Proof
-func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, publicSignals []*big.Int, debug bool) bool {
-
-	icPubl := setup.Vk.IC[0]
-	for i := 0; i < len(publicSignals); i++ {
-		icPubl = Utils.Bn.G1.Add(icPubl, Utils.Bn.G1.MulScalar(setup.Vk.IC[i+1], publicSignals[i]))
+// RerandomizeProof returns a new Proof of the same statement from the Proof, using only the VerifyingKey, so the new
+// Proof can not be linked to the original one. With random θ and r, piA' = piA / θ, piB' = θ (piB + r δ) and
+// piC' = piC + r piA, keeping e(piA', piB') = e(piA, piB) e(r piA, δ).
+func RerandomizeProof(vk VerifyingKey, proof Proof) (Proof, error) {
+	if err := vk.Validate(); err != nil {
+		return Proof{}, err
+	}
+	if err := proof.Validate(); err != nil {
+		return Proof{}, err
+	}
+	theta, err := Utils.FqR.RandNonZero()
+	if err != nil {
+		return Proof{}, err
+	}
+	r, err := Utils.FqR.Rand()
+	if err != nil {
+		return Proof{}, err
 	}
 
-	if !Utils.Bn.Fq12.Equal(
-		Utils.Bn.Pairing(proof.PiA, proof.PiB),
-		Utils.Bn.Fq12.Mul(
-			Utils.Bn.Pairing(setup.Vk.G1.Alpha, setup.Vk.G2.Beta),
-			Utils.Bn.Fq12.Mul(
-				Utils.Bn.Pairing(icPubl, setup.Vk.G2.Gamma),
-				Utils.Bn.Pairing(proof.PiC, setup.Vk.G2.Delta)))) {
+	var p Proof
+	p.PiA = Utils.Bn.G1.MulScalarConstantTime(proof.PiA, Utils.FqR.Inverse(theta))
+	p.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalarConstantTime(vk.G2.Delta, r))
+	p.PiB = Utils.Bn.G2.MulScalarConstantTime(p.PiB, theta)
+	p.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalarConstantTime(proof.PiA, r))
+	return p, nil
+}
+
+// VerifyProof verifies over the BN128 the Pairings of the Proof, returning an error if the verifying key, the Proof or
+// the public signals are malformed
+func VerifyProof(circuit circuitcompiler.Circuit, setup Setup, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	pvk, err := PrepareVerifyingKey(setup.Vk)
+	if err != nil {
+		return false, err
+	}
+	verified, err := pvk.Verify(proof, publicSignals)
+	if err != nil {
+		return false, err
+	}
+	if !verified {
 		if debug {
 			fmt.Println("❌ groth16 verification not passed")
 		}
-		return false
+		return false, nil
 	}
 	if debug {
 		fmt.Println("✓ groth16 verification passed")
 	}
 
-	return true
+	return true, nil
+}
+
+// Verify verifies the Proof against the PreparedVerifyingKey, checking e(piA, piB) == e(α, β) * e(Σ IC_i s_i, γ) * e(piC, δ).
+// It returns an error if the Proof or the public signals are malformed.
+func (pvk PreparedVerifyingKey) Verify(proof Proof, publicSignals []*big.Int) (bool, error) {
+	if err := proof.Validate(); err != nil {
+		return false, err
+	}
+	if err := Utils.Bn.CheckPublicSignals("groth16", publicSignals, len(pvk.VerifyingKey.IC)-1); err != nil {
+		return false, err
+	}
+	return batchPairingCheck(pvk, []Proof{proof}, [][]*big.Int{publicSignals}, []*big.Int{big.NewInt(int64(1))}), nil
+}
+
+// batchPairingCheck checks the random linear combination with the scalars r of the pairing equations of the Proofs,
+// Π e(r_i piA_i, piB_i) == e(Σ r_i α, β) e(Σ r_i IC_i, γ) e(Σ r_i piC_i, δ), with a single Miller loop and final exponentiation
+func batchPairingCheck(vk PreparedVerifyingKey, proofs []Proof, publicSignals [][]*big.Int, r []*big.Int) bool {
+	var g1s [][3]*big.Int
+	var g2s []bn128.AteG2Precomp
+	sumR := big.NewInt(int64(0))
+	// the IC points are combined once, with the public signals weighted by the scalars of their proofs
+	icScalars := make([]*big.Int, len(vk.IC))
+	for j := range icScalars {
+		icScalars[j] = big.NewInt(int64(0))
+	}
+	piC := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i, proof := range proofs {
+		g1s = append(g1s, Utils.Bn.G1.MulScalarGLV(proof.PiA, r[i]))
+		g2s = append(g2s, Utils.Bn.PreComputeG2(proof.PiB))
+		sumR = Utils.FqR.Add(sumR, r[i])
+		icScalars[0] = Utils.FqR.Add(icScalars[0], r[i])
+		for j, s := range publicSignals[i] {
+			icScalars[j+1] = Utils.FqR.Add(icScalars[j+1], Utils.FqR.Mul(r[i], s))
+		}
+		piC = Utils.Bn.G1.Add(piC, Utils.Bn.G1.MulScalarGLV(proof.PiC, r[i]))
+	}
+	icPubl := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for j := range vk.IC {
+		icPubl = Utils.Bn.G1.Add(icPubl, Utils.Bn.G1.MulScalarGLV(vk.IC[j], icScalars[j]))
+	}
+
+	g1s = append(g1s,
+		Utils.Bn.G1.Neg(Utils.Bn.G1.MulScalarGLV(vk.G1.Alpha, sumR)),
+		Utils.Bn.G1.Neg(icPubl),
+		Utils.Bn.G1.Neg(piC))
+	g2s = append(g2s, vk.G2Precomp.Beta, vk.G2Precomp.Gamma, vk.G2Precomp.Delta)
+	return Utils.Bn.PairingCheckPrecomp(g1s, g2s)
+}
+
+// BatchVerify verifies at once the Proofs against the same verifying key, where publicSignals[i] are the public signals
+// of proofs[i], checking a random linear combination of their pairing equations. When the batch fails, each Proof is
+// verified on its own, returning the indexes of the invalid ones, so an empty result means that all the Proofs are valid.
+func BatchVerify(vk VerifyingKey, proofs []Proof, publicSignals [][]*big.Int) ([]int, error) {
+	if len(proofs) != len(publicSignals) {
+		return nil, errors.New("groth16: number of proofs and public signals mismatch")
+	}
+	pvk, err := PrepareVerifyingKey(vk)
+	if err != nil {
+		return nil, err
+	}
+	for i := range proofs {
+		if err := proofs[i].Validate(); err != nil {
+			return nil, proofError(i, err)
+		}
+		if err := Utils.Bn.CheckPublicSignals("groth16", publicSignals[i], len(vk.IC)-1); err != nil {
+			return nil, proofError(i, err)
+		}
+	}
+	if len(proofs) == 0 {
+		return nil, nil
+	}
+
+	// the weights are not zero, as a zero weight would drop the pairing equation of its Proof from the check
+	var r []*big.Int
+	for range proofs {
+		ri, err := Utils.FqR.RandNonZero()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, ri)
+	}
+	if batchPairingCheck(pvk, proofs, publicSignals, r) {
+		return nil, nil
+	}
+
+	var invalid []int
+	one := []*big.Int{big.NewInt(int64(1))}
+	for i := range proofs {
+		if !batchPairingCheck(pvk, proofs[i:i+1], publicSignals[i:i+1], one) {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid, nil
 }
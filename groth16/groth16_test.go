@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields/fieldstest"
 	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/stretchr/testify/assert"
 )
@@ -70,14 +71,17 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// ---
 	// calculate trusted setup
 	fmt.Println("groth")
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(nil, len(w), *circuit, alphas, betas, gammas)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
+	// Z(x) has a root at each constraint
+	assert.Equal(t, len(a)+1, len(setup.Pk.Z))
+
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(7))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -85,7 +89,7 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// check length of polynomials H(x) and Z(x)
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 
-	proof, err := GenerateProofs(*circuit, setup, w, px)
+	proof, err := GenerateProofs(nil, *circuit, setup, w, px)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -97,11 +101,229 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(*circuit, setup, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(*circuit, setup, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+}
+
+// testProofs returns the Setup and the proofs of y = x^3 + x + 5 for x = 3, 2, 3
+func testProofs(t *testing.T) (Setup, []Proof, [][]*big.Int) {
+	// y = x^3 + x + 5
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	alphas, betas, gammas, _ := Utils.PF.R1CSToQAP(a, b, c)
+	setup, err := GenerateTrustedSetup(nil, circuit.NVars, *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+
+	// x = 3, y = 35 and x = 2, y = 15
+	var proofs []Proof
+	var publicSignals [][]*big.Int
+	for _, x := range []int64{3, 2, 3} {
+		y := big.NewInt(x*x*x + x + 5)
+		w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(x)}, []*big.Int{y})
+		assert.Nil(t, err)
+		_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+		proof, err := GenerateProofs(nil, *circuit, setup, w, px)
+		assert.Nil(t, err)
+		proofs = append(proofs, proof)
+		publicSignals = append(publicSignals, []*big.Int{y})
+	}
+	return setup, proofs, publicSignals
+}
+
+func TestGroth16PreparedVerifyingKey(t *testing.T) {
+	setup, proofs, publicSignals := testProofs(t)
+	pvk, err := PrepareVerifyingKey(setup.Vk)
+	assert.Nil(t, err)
+	for i := range proofs {
+		verified, err := pvk.Verify(proofs[i], publicSignals[i])
+		assert.Nil(t, err)
+		assert.True(t, verified)
+	}
+	verified, err := pvk.Verify(proofs[0], []*big.Int{big.NewInt(int64(34))})
+	assert.Nil(t, err)
+	assert.False(t, verified)
+	verified, err = pvk.Verify(proofs[1], publicSignals[0])
+	assert.Nil(t, err)
+	assert.False(t, verified)
+	_, err = pvk.Verify(proofs[0], nil)
+	assert.NotNil(t, err)
+}
+
+func TestGroth16VerifyMalformed(t *testing.T) {
+	setup, proofs, publicSignals := testProofs(t)
+	g1, g2 := Utils.Bn.G1, Utils.Bn.G2
+
+	// point of the twist outside G2
+	var notInG2 [3][2]*big.Int
+	for i := 1; ; i++ {
+		x := [2]*big.Int{big.NewInt(int64(i)), big.NewInt(int64(0))}
+		y, ok := Utils.Bn.Fq2.Sqrt(Utils.Bn.Fq2.Add(Utils.Bn.Fq2.Mul(Utils.Bn.Fq2.Square(x), x), Utils.Bn.TwistCoefB))
+		if ok {
+			notInG2 = [3][2]*big.Int{x, y, Utils.Bn.Fq2.One()}
+			break
+		}
+	}
+	assert.True(t, g2.IsOnCurve(notInG2))
+	notOnCurve := [3]*big.Int{proofs[0].PiA[0], g1.F.Add(proofs[0].PiA[1], g1.F.One()), proofs[0].PiA[2]}
+
+	malformed := proofs[0]
+	malformed.PiA = notOnCurve
+	_, err := VerifyProof(circuitcompiler.Circuit{}, setup, malformed, publicSignals[0], false)
+	assert.Equal(t, "groth16: proof piA not on the curve", err.Error())
+
+	malformed = proofs[0]
+	malformed.PiB = notInG2
+	_, err = VerifyProof(circuitcompiler.Circuit{}, setup, malformed, publicSignals[0], false)
+	assert.Equal(t, "groth16: proof piB not in the subgroup of order r", err.Error())
+
+	malformed = proofs[0]
+	malformed.PiC = [3]*big.Int{proofs[0].PiC[0], nil, proofs[0].PiC[2]}
+	_, err = VerifyProof(circuitcompiler.Circuit{}, setup, malformed, publicSignals[0], false)
+	assert.Equal(t, "groth16: proof piC not on the curve", err.Error())
+
+	// keys
+	wrongSetup := setup
+	wrongSetup.Vk.G2.Delta = notInG2
+	_, err = VerifyProof(circuitcompiler.Circuit{}, wrongSetup, proofs[0], publicSignals[0], false)
+	assert.Equal(t, "groth16: verifying key δ not in the subgroup of order r", err.Error())
+	wrongSetup = setup
+	wrongSetup.Vk.IC = append([][3]*big.Int{}, setup.Vk.IC...)
+	wrongSetup.Vk.IC[1] = notOnCurve
+	_, err = VerifyProof(circuitcompiler.Circuit{}, wrongSetup, proofs[0], publicSignals[0], false)
+	assert.Equal(t, "groth16: verifying key IC[1] not on the curve", err.Error())
+	_, err = PrepareVerifyingKey(wrongSetup.Vk)
+	assert.NotNil(t, err)
+
+	// public signals, which must be inside the Finite Field over R: y + r would pass the pairing check
+	_, err = VerifyProof(circuitcompiler.Circuit{}, setup, proofs[0], []*big.Int{new(big.Int).Add(publicSignals[0][0], Utils.FqR.Q)}, false)
+	assert.Equal(t, "groth16: public signal 0 not inside the Finite Field", err.Error())
+	_, err = VerifyProof(circuitcompiler.Circuit{}, setup, proofs[0], []*big.Int{big.NewInt(int64(-1))}, false)
+	assert.NotNil(t, err)
+	_, err = VerifyProof(circuitcompiler.Circuit{}, setup, proofs[0], []*big.Int{publicSignals[0][0], publicSignals[0][0]}, false)
+	assert.Equal(t, "groth16: wrong number of public signals, expected 1, got 2", err.Error())
+
+	// batch
+	_, err = BatchVerify(setup.Vk, []Proof{proofs[0], malformed}, publicSignals[:2])
+	assert.Equal(t, "groth16: proof 1: proof piC not on the curve", err.Error())
+	_, err = BatchVerify(wrongSetup.Vk, proofs, publicSignals)
+	assert.NotNil(t, err)
+}
+
+func TestGroth16BatchVerify(t *testing.T) {
+	setup, proofs, publicSignals := testProofs(t)
+
+	before := time.Now()
+	invalid, err := BatchVerify(setup.Vk, proofs, publicSignals)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(invalid))
+	fmt.Println("batch verify of 3 proofs time elapsed:", time.Since(before))
+
+	// wrong public signal of the second proof
+	wrongPublicSignals := [][]*big.Int{publicSignals[0], {big.NewInt(int64(16))}, publicSignals[2]}
+	invalid, err = BatchVerify(setup.Vk, proofs, wrongPublicSignals)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, invalid)
+
+	// swapped proofs
+	swapped := []Proof{proofs[1], proofs[0], proofs[2]}
+	invalid, err = BatchVerify(setup.Vk, swapped, publicSignals)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, invalid)
+
+	_, err = BatchVerify(setup.Vk, proofs, publicSignals[:2])
+	assert.NotNil(t, err)
+	_, err = BatchVerify(setup.Vk, proofs[:1], [][]*big.Int{{big.NewInt(int64(35)), big.NewInt(int64(1))}})
+	assert.NotNil(t, err)
+}
+
+func TestGroth16RerandomizeProof(t *testing.T) {
+	setup, proofs, publicSignals := testProofs(t)
+
+	proof, err := RerandomizeProof(setup.Vk, proofs[0])
+	assert.Nil(t, err)
+	assert.False(t, Utils.Bn.G1.Equal(proof.PiA, proofs[0].PiA))
+	assert.False(t, Utils.Bn.G2.Equal(proof.PiB, proofs[0].PiB))
+	assert.False(t, Utils.Bn.G1.Equal(proof.PiC, proofs[0].PiC))
+	verified, err := VerifyProof(circuitcompiler.Circuit{}, setup, proof, publicSignals[0], false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	// the rerandomized proof is still bound to its statement
+	verified, err = VerifyProof(circuitcompiler.Circuit{}, setup, proof, publicSignals[1], false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// a rerandomized proof can be rerandomized again
+	proof2, err := RerandomizeProof(setup.Vk, proof)
+	assert.Nil(t, err)
+	assert.False(t, Utils.Bn.G1.Equal(proof2.PiA, proof.PiA))
+	invalid, err := BatchVerify(setup.Vk, []Proof{proofs[0], proof, proof2}, [][]*big.Int{publicSignals[0], publicSignals[0], publicSignals[0]})
+	assert.Nil(t, err)
+	assert.Empty(t, invalid)
+
+	malformed := proofs[0]
+	malformed.PiC = [3]*big.Int{proofs[0].PiC[0], nil, proofs[0].PiC[2]}
+	_, err = RerandomizeProof(setup.Vk, malformed)
+	assert.Equal(t, "groth16: proof piC not on the curve", err.Error())
+}
+
+func TestGroth16SeededSetupAndProofs(t *testing.T) {
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	alphas, betas, gammas, _ := Utils.PF.R1CSToQAP(a, b, c)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+
+	generate := func(seed string) (Setup, Proof) {
+		rnd := fieldstest.NewSeededReader([]byte(seed))
+		setup, err := GenerateTrustedSetup(rnd, circuit.NVars, *circuit, alphas, betas, gammas)
+		assert.Nil(t, err)
+		proof, err := GenerateProofs(rnd, *circuit, setup, w, px)
+		assert.Nil(t, err)
+		return setup, proof
+	}
+
+	// the same seed gives the same keys and proofs
+	setup1, proof1 := generate("seed")
+	setup2, proof2 := generate("seed")
+	assert.Equal(t, setup1, setup2)
+	assert.Equal(t, proof1, proof2)
+	verified, err := VerifyProof(*circuit, setup1, proof1, []*big.Int{big.NewInt(int64(35))}, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	setup3, proof3 := generate("other seed")
+	assert.NotEqual(t, setup1.Toxic, setup3.Toxic)
+	assert.False(t, Utils.Bn.G1.Equal(proof1.PiA, proof3.PiA))
 }
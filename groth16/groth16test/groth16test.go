@@ -0,0 +1,53 @@
+// Package groth16test provides utilities for testing the Groth16 zkSNARK. Its functions use the secrets of the Trusted
+// Setup to forge proofs, so they must only be used in tests, to build fixtures or to check the zero-knowledge of the
+// proofs, and never in production.
+package groth16test
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/groth16"
+)
+
+// SimulateProof returns a valid Proof for the public signals without a witness, using the Toxic secrets of the Setup
+// of the VerifyingKey. With random a and b, piA = a G1, piB = b G2 and
+// piC = (a b G1 - α β G1 - γ Σ IC_i s_i) / δ, which is distributed as the proofs generated from a witness. a and b are
+// read from randReader, or from crypto/rand if it is nil, as in groth16.GenerateProofs.
+func SimulateProof(randReader io.Reader, toxic groth16.Toxic, vk groth16.VerifyingKey, publicSignals []*big.Int) (groth16.Proof, error) {
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	bn, fqR := groth16.Utils.Bn, groth16.Utils.FqR
+	if toxic.Kalpha == nil || toxic.Kbeta == nil || toxic.Kgamma == nil || toxic.Kdelta == nil {
+		return groth16.Proof{}, errors.New("groth16test: toxic without α, β, γ or δ")
+	}
+	if fqR.IsZero(toxic.Kdelta) {
+		return groth16.Proof{}, errors.New("groth16test: toxic δ is zero")
+	}
+	if len(publicSignals)+1 != len(vk.IC) {
+		return groth16.Proof{}, errors.New("groth16test: wrong number of public signals")
+	}
+	a, err := fqR.RandFrom(randReader)
+	if err != nil {
+		return groth16.Proof{}, err
+	}
+	b, err := fqR.RandFrom(randReader)
+	if err != nil {
+		return groth16.Proof{}, err
+	}
+
+	icPubl := vk.IC[0]
+	for i, s := range publicSignals {
+		icPubl = bn.G1.Add(icPubl, bn.G1.MulScalar(vk.IC[i+1], s))
+	}
+	var proof groth16.Proof
+	proof.PiA = bn.G1.MulScalar(bn.G1.G, a)
+	proof.PiB = bn.G2.MulScalar(bn.G2.G, b)
+	c := bn.G1.MulScalar(bn.G1.G, fqR.Sub(fqR.Mul(a, b), fqR.Mul(toxic.Kalpha, toxic.Kbeta)))
+	c = bn.G1.Sub(c, bn.G1.MulScalar(icPubl, toxic.Kgamma))
+	proof.PiC = bn.G1.MulScalar(c, fqR.Inverse(toxic.Kdelta))
+	return proof, nil
+}
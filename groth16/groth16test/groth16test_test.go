@@ -0,0 +1,63 @@
+package groth16test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields/fieldstest"
+	"github.com/arnaucube/go-snark/groth16"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateProof(t *testing.T) {
+	// y = x^3 + x + 5
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	alphas, betas, gammas, _ := groth16.Utils.PF.R1CSToQAP(a, b, c)
+	setup, err := groth16.GenerateTrustedSetup(nil, circuit.NVars, *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	_, _, _, px := groth16.Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+	proof, err := groth16.GenerateProofs(nil, *circuit, setup, w, px)
+	assert.Nil(t, err)
+
+	// the simulated proofs verify as the proofs from a witness, also for false statements
+	for _, y := range []int64{35, 34} {
+		simulated, err := SimulateProof(nil, setup.Toxic, setup.Vk, []*big.Int{big.NewInt(y)})
+		assert.Nil(t, err)
+		assert.False(t, groth16.Utils.Bn.G1.Equal(proof.PiA, simulated.PiA))
+		verified, err := groth16.VerifyProof(*circuit, setup, simulated, []*big.Int{big.NewInt(y)}, false)
+		assert.Nil(t, err)
+		assert.True(t, verified)
+		verified, err = groth16.VerifyProof(*circuit, setup, simulated, []*big.Int{big.NewInt(y + 2)}, false)
+		assert.Nil(t, err)
+		assert.False(t, verified)
+	}
+
+	// the same randomness gives the same simulated proof
+	simulated1, err := SimulateProof(fieldstest.NewSeededReader([]byte("seed")), setup.Toxic, setup.Vk, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	simulated2, err := SimulateProof(fieldstest.NewSeededReader([]byte("seed")), setup.Toxic, setup.Vk, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	assert.Equal(t, simulated1, simulated2)
+
+	_, err = SimulateProof(nil, groth16.Toxic{}, setup.Vk, []*big.Int{big.NewInt(int64(35))})
+	assert.NotNil(t, err)
+	_, err = SimulateProof(nil, setup.Toxic, setup.Vk, nil)
+	assert.NotNil(t, err)
+}
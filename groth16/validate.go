@@ -0,0 +1,45 @@
+package groth16
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Validate checks that all the points of the VerifyingKey are in G1 and G2
+func (vk VerifyingKey) Validate() error {
+	if len(vk.IC) == 0 {
+		return errors.New("groth16: verifying key without IC")
+	}
+	for i, ic := range vk.IC {
+		if err := Utils.Bn.CheckG1("groth16", "verifying key IC["+strconv.Itoa(i)+"]", ic); err != nil {
+			return err
+		}
+	}
+	if err := Utils.Bn.CheckG1("groth16", "verifying key α", vk.G1.Alpha); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG2("groth16", "verifying key β", vk.G2.Beta); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG2("groth16", "verifying key γ", vk.G2.Gamma); err != nil {
+		return err
+	}
+	return Utils.Bn.CheckG2("groth16", "verifying key δ", vk.G2.Delta)
+}
+
+// Validate checks that all the points of the Proof are in G1 and G2
+func (proof Proof) Validate() error {
+	if err := Utils.Bn.CheckG1("groth16", "proof piA", proof.PiA); err != nil {
+		return err
+	}
+	if err := Utils.Bn.CheckG2("groth16", "proof piB", proof.PiB); err != nil {
+		return err
+	}
+	return Utils.Bn.CheckG1("groth16", "proof piC", proof.PiC)
+}
+
+// proofError returns the error of the proof i of a batch
+func proofError(i int, err error) error {
+	return errors.New("groth16: proof " + strconv.Itoa(i) + ": " + strings.TrimPrefix(err.Error(), "groth16: "))
+}
@@ -0,0 +1,22 @@
+package keccak
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(Hash([]byte{})))
+	assert.Equal(t, "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45", hex.EncodeToString(Hash([]byte("abc"))))
+	assert.Equal(t, "b6e489e6b37224a50bebfddbe7d89fa8fdcaa84304a70bd13f79b5d9f7951e9e", hex.EncodeToString(Hash([]byte("mimc"))))
+	// the data is concatenated
+	assert.Equal(t, Hash([]byte("abc")), Hash([]byte("a"), []byte("bc")))
+
+	// messages longer than the rate
+	long := []byte(strings.Repeat("a", 200))
+	assert.Equal(t, Hash(long), Hash(long[:136], long[136:]))
+	assert.Equal(t, Size, len(Hash(long)))
+}
@@ -0,0 +1,272 @@
+// Package kzg implements the KZG polynomial commitment scheme (https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf)
+// over BN128, committing to the polynomials of the r1csqap.PolynomialField over R with the powers of a secret τ, and
+// proving their evaluations with a single G1 point that is verified with a pairing check.
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/ceremony"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/r1csqap"
+)
+
+// SRS is the structured reference string of the commitments, the powers of the secret τ encrypted in the G1 and G2 curves
+type SRS struct {
+	G1 [][3]*big.Int    // {τ^i G1} from 0 to the maximum degree of the committed polynomials
+	G2 [][3][2]*big.Int // {τ^i G2} from 0 to the maximum number of points of the multi point openings
+}
+
+// Proof is the opening of a polynomial p at a point z, with the evaluation p(z) and the commitment W to the
+// quotient (p(x) - p(z)) / (x - z)
+type Proof struct {
+	Eval *big.Int
+	W    [3]*big.Int
+}
+
+// MultiProof is the opening of a polynomial p at the points z_i, with the evaluations p(z_i) and the commitment W to
+// the quotient (p(x) - I(x)) / Z(x), where I interpolates the evaluations and Z(x) = Π (x - z_i)
+type MultiProof struct {
+	Evals []*big.Int
+	W     [3]*big.Int
+}
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+	PF  r1csqap.PolynomialField
+}
+
+// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the commitments
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	fqR := fields.NewFq(bn.R)
+	return utils{
+		Bn:  bn,
+		FqR: fqR,
+		PF:  r1csqap.NewPolynomialField(fqR),
+	}
+}
+
+// NewSRS generates the SRS from the secret τ, for polynomials up to the given degree and multi point openings up to
+// degreeG2 points. τ is toxic waste, and must be destroyed once the SRS is generated.
+func NewSRS(tau *big.Int, degree, degreeG2 int) (*SRS, error) {
+	if degree < 1 || degreeG2 < 1 {
+		return nil, errors.New("kzg: degree must be at least 1")
+	}
+	if Utils.FqR.IsZero(tau) {
+		return nil, errors.New("kzg: τ can not be zero")
+	}
+	srs := &SRS{}
+	tauI := big.NewInt(int64(1))
+	for i := 0; i <= degree || i <= degreeG2; i++ {
+		if i <= degree {
+			srs.G1 = append(srs.G1, affineG1(Utils.Bn.G1.MulScalarConstantTime(Utils.Bn.G1.G, tauI)))
+		}
+		if i <= degreeG2 {
+			srs.G2 = append(srs.G2, Utils.Bn.G2.Affine(Utils.Bn.G2.MulScalarConstantTime(Utils.Bn.G2.G, tauI)))
+		}
+		tauI = Utils.FqR.Mul(tauI, tau)
+	}
+	return srs, nil
+}
+
+// NewSRSFromTranscript takes the SRS from the accumulated powers of τ of a powers of tau ceremony, for polynomials up
+// to the degree 2*Size-2 and multi point openings up to Size-1 points. The Transcript must have been verified.
+func NewSRSFromTranscript(t *ceremony.Transcript) (*SRS, error) {
+	acc := t.Accumulator
+	if t.Size < 2 || len(acc.TauG1) != 2*t.Size-1 || len(acc.TauG2) != t.Size {
+		return nil, errors.New("kzg: wrong accumulator length")
+	}
+	srs := &SRS{}
+	srs.G1 = append(srs.G1, acc.TauG1...)
+	srs.G2 = append(srs.G2, acc.TauG2...)
+	return srs, nil
+}
+
+// LoadSRS loads the SRS from the json file of a powers of tau Transcript, verifying its chain of contributions
+func LoadSRS(path string) (*SRS, error) {
+	t, err := ceremony.ReadTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	return NewSRSFromTranscript(t)
+}
+
+func affineG1(p [3]*big.Int) [3]*big.Int {
+	if Utils.Bn.G1.IsZero(p) {
+		return p
+	}
+	a := Utils.Bn.G1.Affine(p)
+	return [3]*big.Int{a[0], a[1], Utils.Bn.G1.F.One()}
+}
+
+// Commit returns the commitment to the polynomial p, p(τ) G1 = Σ p[i] τ^i G1. The coefficients can be secret, so they
+// are multiplied with MulScalarConstantTime, whose sequence of operations does not depend on them.
+func (srs SRS) Commit(p []*big.Int) ([3]*big.Int, error) {
+	if err := srs.checkDegree(p); err != nil {
+		return [3]*big.Int{}, err
+	}
+	res := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := range p {
+		res = Utils.Bn.G1.Add(res, Utils.Bn.G1.MulScalarConstantTime(srs.G1[i], p[i]))
+	}
+	return affineG1(res), nil
+}
+
+// checkDegree returns an error if the polynomial p has more coefficients than the powers of τ of the SRS
+func (srs SRS) checkDegree(p []*big.Int) error {
+	if len(p) > len(srs.G1) {
+		return errors.New("kzg: polynomial of degree " + strconv.Itoa(len(p)-1) + " bigger than the SRS degree " + strconv.Itoa(len(srs.G1)-1))
+	}
+	return nil
+}
+
+// Open returns the Proof of the evaluation of the polynomial p at the point z
+func (srs SRS) Open(p []*big.Int, z *big.Int) (Proof, error) {
+	if err := srs.checkDegree(p); err != nil {
+		return Proof{}, err
+	}
+	y := Utils.PF.Eval(p, z)
+	q, _ := Utils.PF.Div(Utils.PF.Sub(p, []*big.Int{y}), []*big.Int{Utils.FqR.Neg(z), big.NewInt(int64(1))})
+	w, err := srs.Commit(q)
+	if err != nil {
+		return Proof{}, err
+	}
+	return Proof{Eval: y, W: w}, nil
+}
+
+func inField(x *big.Int) bool {
+	return x != nil && x.Sign() >= 0 && x.Cmp(Utils.FqR.Q) < 0
+}
+
+// Verify verifies the Proof of the evaluation at the point z of the polynomial committed in c, checking
+// e(c - p(z) G1 + z W, G2) == e(W, τ G2)
+func (srs SRS) Verify(c [3]*big.Int, z *big.Int, proof Proof) bool {
+	if len(srs.G1) < 1 || len(srs.G2) < 2 || !inField(z) || !inField(proof.Eval) ||
+		!Utils.Bn.G1.IsOnCurve(c) || !Utils.Bn.G1.IsOnCurve(proof.W) {
+		return false
+	}
+	lhs := Utils.Bn.G1.Sub(c, Utils.Bn.G1.MulScalarGLV(srs.G1[0], proof.Eval))
+	lhs = Utils.Bn.G1.Add(lhs, Utils.Bn.G1.MulScalarGLV(proof.W, z))
+	return Utils.Bn.PairingCheck(
+		[][3]*big.Int{lhs, Utils.Bn.G1.Neg(proof.W)},
+		[][3][2]*big.Int{srs.G2[0], srs.G2[1]})
+}
+
+// BatchVerify verifies at once the Proofs of the evaluations at the points zs[i] of the polynomials committed in
+// cs[i], checking a random linear combination of their pairing equations with a single pairing check
+func (srs SRS) BatchVerify(cs [][3]*big.Int, zs []*big.Int, proofs []Proof) (bool, error) {
+	if len(cs) != len(zs) || len(cs) != len(proofs) {
+		return false, errors.New("kzg: number of commitments, points and proofs mismatch")
+	}
+	if len(srs.G1) < 1 || len(srs.G2) < 2 {
+		return false, errors.New("kzg: SRS too small")
+	}
+	lhs := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	w := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	sumEvals := big.NewInt(int64(0))
+	for i := range proofs {
+		if !inField(zs[i]) || !inField(proofs[i].Eval) ||
+			!Utils.Bn.G1.IsOnCurve(cs[i]) || !Utils.Bn.G1.IsOnCurve(proofs[i].W) {
+			return false, nil
+		}
+		// the weight is not zero, as a zero weight would drop the pairing equation of the Proof from the check
+		r, err := Utils.FqR.RandNonZero()
+		if err != nil {
+			return false, err
+		}
+		// Σ r_i (c_i - p_i(z_i) G1 + z_i W_i) and Σ r_i W_i
+		sumEvals = Utils.FqR.Add(sumEvals, Utils.FqR.Mul(r, proofs[i].Eval))
+		lhs = Utils.Bn.G1.Add(lhs, Utils.Bn.G1.MulScalarGLV(cs[i], r))
+		lhs = Utils.Bn.G1.Add(lhs, Utils.Bn.G1.MulScalarGLV(proofs[i].W, Utils.FqR.Mul(r, zs[i])))
+		w = Utils.Bn.G1.Add(w, Utils.Bn.G1.MulScalarGLV(proofs[i].W, r))
+	}
+	lhs = Utils.Bn.G1.Sub(lhs, Utils.Bn.G1.MulScalarGLV(srs.G1[0], sumEvals))
+	return Utils.Bn.PairingCheck(
+		[][3]*big.Int{lhs, Utils.Bn.G1.Neg(w)},
+		[][3][2]*big.Int{srs.G2[0], srs.G2[1]}), nil
+}
+
+// checkPoints returns an error if the points zs are not distinct elements of the Finite Field over R, or there are
+// more of them than the multi point openings of the SRS
+func (srs SRS) checkPoints(zs []*big.Int) error {
+	if len(zs) == 0 {
+		return errors.New("kzg: no points")
+	}
+	if len(zs) >= len(srs.G2) {
+		return errors.New("kzg: " + strconv.Itoa(len(zs)) + " points, more than the SRS maximum of " + strconv.Itoa(len(srs.G2)-1))
+	}
+	seen := make(map[string]bool)
+	for i, z := range zs {
+		if !inField(z) {
+			return errors.New("kzg: point " + strconv.Itoa(i) + " not inside the Finite Field")
+		}
+		if seen[z.String()] {
+			return errors.New("kzg: point " + strconv.Itoa(i) + " repeated")
+		}
+		seen[z.String()] = true
+	}
+	return nil
+}
+
+// OpenMulti returns the MultiProof of the evaluations of the polynomial p at the distinct points zs
+func (srs SRS) OpenMulti(p []*big.Int, zs []*big.Int) (MultiProof, error) {
+	if err := srs.checkDegree(p); err != nil {
+		return MultiProof{}, err
+	}
+	if err := srs.checkPoints(zs); err != nil {
+		return MultiProof{}, err
+	}
+	var ys []*big.Int
+	for _, z := range zs {
+		ys = append(ys, Utils.PF.Eval(p, z))
+	}
+	q, _ := Utils.PF.Div(Utils.PF.Sub(p, Utils.PF.LagrangeInterpolationAt(zs, ys)), Utils.PF.VanishingPolynomial(zs))
+	w, err := srs.Commit(q)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	return MultiProof{Evals: ys, W: w}, nil
+}
+
+// VerifyMulti verifies the MultiProof of the evaluations at the points zs of the polynomial committed in c, checking
+// e(c - I(τ) G1, G2) == e(W, Z(τ) G2)
+func (srs SRS) VerifyMulti(c [3]*big.Int, zs []*big.Int, proof MultiProof) bool {
+	if srs.checkPoints(zs) != nil || len(proof.Evals) != len(zs) ||
+		!Utils.Bn.G1.IsOnCurve(c) || !Utils.Bn.G1.IsOnCurve(proof.W) {
+		return false
+	}
+	for _, y := range proof.Evals {
+		if !inField(y) {
+			return false
+		}
+	}
+	iPol := Utils.PF.LagrangeInterpolationAt(zs, proof.Evals)
+	if len(iPol) > len(srs.G1) {
+		return false
+	}
+	iTau := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := range iPol {
+		iTau = Utils.Bn.G1.Add(iTau, Utils.Bn.G1.MulScalarGLV(srs.G1[i], iPol[i]))
+	}
+	zPol := Utils.PF.VanishingPolynomial(zs)
+	zTau := Utils.Bn.G2.Zero()
+	for i := range zPol {
+		zTau = Utils.Bn.G2.Add(zTau, Utils.Bn.G2.MulScalarGLS(srs.G2[i], zPol[i]))
+	}
+	return Utils.Bn.PairingCheck(
+		[][3]*big.Int{Utils.Bn.G1.Sub(c, iTau), Utils.Bn.G1.Neg(proof.W)},
+		[][3][2]*big.Int{srs.G2[0], zTau})
+}
@@ -0,0 +1,187 @@
+package kzg
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnaucube/go-snark/ceremony"
+	"github.com/stretchr/testify/assert"
+)
+
+func randPolynomial(t *testing.T, n int) []*big.Int {
+	var p []*big.Int
+	for i := 0; i < n; i++ {
+		c, err := Utils.FqR.Rand()
+		assert.Nil(t, err)
+		p = append(p, c)
+	}
+	return p
+}
+
+func newTestSRS(t *testing.T, degree, degreeG2 int) *SRS {
+	tau, err := Utils.FqR.Rand()
+	assert.Nil(t, err)
+	srs, err := NewSRS(tau, degree, degreeG2)
+	assert.Nil(t, err)
+	return srs
+}
+
+func TestCommitOpenVerify(t *testing.T) {
+	srs := newTestSRS(t, 8, 4)
+	assert.Equal(t, 9, len(srs.G1))
+	assert.Equal(t, 5, len(srs.G2))
+
+	p := randPolynomial(t, 9)
+	c, err := srs.Commit(p)
+	assert.Nil(t, err)
+	z := big.NewInt(int64(1234))
+	proof, err := srs.Open(p, z)
+	assert.Nil(t, err)
+	assert.Equal(t, Utils.PF.Eval(p, z), proof.Eval)
+	assert.True(t, srs.Verify(c, z, proof))
+
+	// the commitment is additive
+	p2 := randPolynomial(t, 5)
+	c2, err := srs.Commit(p2)
+	assert.Nil(t, err)
+	cSum, err := srs.Commit(Utils.PF.Add(p, p2))
+	assert.Nil(t, err)
+	assert.True(t, Utils.Bn.G1.Equal(cSum, Utils.Bn.G1.Add(c, c2)))
+
+	// wrong evaluation, point and commitment
+	assert.False(t, srs.Verify(c, z, Proof{Eval: Utils.FqR.Add(proof.Eval, big.NewInt(int64(1))), W: proof.W}))
+	assert.False(t, srs.Verify(c, big.NewInt(int64(1235)), proof))
+	assert.False(t, srs.Verify(c2, z, proof))
+	// malformed inputs
+	assert.False(t, srs.Verify(c, z, Proof{Eval: new(big.Int).Add(proof.Eval, Utils.FqR.Q), W: proof.W}))
+	assert.False(t, srs.Verify(c, z, Proof{Eval: proof.Eval, W: [3]*big.Int{proof.W[0], big.NewInt(int64(1)), proof.W[2]}}))
+
+	// constant polynomial
+	pc := []*big.Int{big.NewInt(int64(7))}
+	cc, err := srs.Commit(pc)
+	assert.Nil(t, err)
+	proofC, err := srs.Open(pc, z)
+	assert.Nil(t, err)
+	assert.True(t, srs.Verify(cc, z, proofC))
+
+	// polynomial bigger than the SRS
+	_, err = srs.Commit(randPolynomial(t, 10))
+	assert.NotNil(t, err)
+	// the quotient of a polynomial one coefficient bigger than the SRS fits in it, but the polynomial can not be committed
+	_, err = srs.Open(randPolynomial(t, 10), z)
+	assert.Equal(t, "kzg: polynomial of degree 9 bigger than the SRS degree 8", err.Error())
+	_, err = srs.OpenMulti(randPolynomial(t, 10), []*big.Int{z})
+	assert.NotNil(t, err)
+
+	_, err = NewSRS(big.NewInt(int64(0)), 8, 4)
+	assert.NotNil(t, err)
+	_, err = NewSRS(big.NewInt(int64(3)), 0, 4)
+	assert.NotNil(t, err)
+}
+
+func TestBatchVerify(t *testing.T) {
+	srs := newTestSRS(t, 6, 1)
+	var cs [][3]*big.Int
+	var zs []*big.Int
+	var proofs []Proof
+	for i := 0; i < 3; i++ {
+		p := randPolynomial(t, 7-i)
+		c, err := srs.Commit(p)
+		assert.Nil(t, err)
+		z, err := Utils.FqR.Rand()
+		assert.Nil(t, err)
+		proof, err := srs.Open(p, z)
+		assert.Nil(t, err)
+		cs = append(cs, c)
+		zs = append(zs, z)
+		proofs = append(proofs, proof)
+	}
+	ok, err := srs.BatchVerify(cs, zs, proofs)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// an invalid proof makes the batch fail
+	wrong := []Proof{proofs[0], {Eval: Utils.FqR.Add(proofs[1].Eval, big.NewInt(int64(1))), W: proofs[1].W}, proofs[2]}
+	ok, err = srs.BatchVerify(cs, zs, wrong)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = srs.BatchVerify(cs, []*big.Int{zs[1], zs[0], zs[2]}, proofs)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = srs.BatchVerify(cs[:2], zs, proofs)
+	assert.NotNil(t, err)
+}
+
+func TestOpenMulti(t *testing.T) {
+	srs := newTestSRS(t, 8, 3)
+	p := randPolynomial(t, 9)
+	c, err := srs.Commit(p)
+	assert.Nil(t, err)
+
+	zs := []*big.Int{big.NewInt(int64(1)), big.NewInt(int64(5)), Utils.FqR.Neg(big.NewInt(int64(2)))}
+	proof, err := srs.OpenMulti(p, zs)
+	assert.Nil(t, err)
+	for i, z := range zs {
+		assert.Equal(t, Utils.PF.Eval(p, z), proof.Evals[i])
+	}
+	assert.True(t, srs.VerifyMulti(c, zs, proof))
+
+	// a subset of the points
+	proof2, err := srs.OpenMulti(p, zs[:2])
+	assert.Nil(t, err)
+	assert.True(t, srs.VerifyMulti(c, zs[:2], proof2))
+	assert.False(t, srs.VerifyMulti(c, zs, proof2))
+
+	// wrong evaluation and points
+	wrong := MultiProof{Evals: []*big.Int{proof.Evals[0], proof.Evals[2], proof.Evals[1]}, W: proof.W}
+	assert.False(t, srs.VerifyMulti(c, zs, wrong))
+	assert.False(t, srs.VerifyMulti(c, []*big.Int{zs[0], zs[1], big.NewInt(int64(3))}, proof))
+
+	// repeated points, and more points than the SRS supports
+	_, err = srs.OpenMulti(p, []*big.Int{zs[0], zs[0]})
+	assert.NotNil(t, err)
+	_, err = srs.OpenMulti(p, append(zs, big.NewInt(int64(9))))
+	assert.NotNil(t, err)
+	_, err = srs.OpenMulti(p, nil)
+	assert.NotNil(t, err)
+}
+
+func TestSRSFromTranscript(t *testing.T) {
+	transcript, err := ceremony.NewTranscript(4)
+	assert.Nil(t, err)
+	assert.Nil(t, transcript.Contribute())
+
+	dir, err := ioutil.TempDir("", "kzg")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "transcript.json")
+	assert.Nil(t, ceremony.WriteTranscript(path, transcript))
+
+	srs, err := LoadSRS(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(srs.G1))
+	assert.Equal(t, 4, len(srs.G2))
+
+	p := randPolynomial(t, 7)
+	c, err := srs.Commit(p)
+	assert.Nil(t, err)
+	z := big.NewInt(int64(42))
+	proof, err := srs.Open(p, z)
+	assert.Nil(t, err)
+	assert.True(t, srs.Verify(c, z, proof))
+	zs := []*big.Int{big.NewInt(int64(1)), big.NewInt(int64(2)), big.NewInt(int64(3))}
+	multiProof, err := srs.OpenMulti(p, zs)
+	assert.Nil(t, err)
+	assert.True(t, srs.VerifyMulti(c, zs, multiProof))
+
+	// a transcript without contributions is not loaded
+	empty, err := ceremony.NewTranscript(4)
+	assert.Nil(t, err)
+	assert.Nil(t, ceremony.WriteTranscript(path, empty))
+	_, err = LoadSRS(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,158 @@
+package plonk
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+)
+
+// Gate is a PLONK gate, which constrains the values a, b and c of the variables of its wires A, B and C to
+// q_M a b + q_L a + q_R b + q_O c + q_C = 0
+type Gate struct {
+	QM, QL, QR, QO, QC *big.Int
+	A, B, C            int
+}
+
+// Arithmetization is the PLONK form of a compiled Circuit. Its variables are the witness of the Circuit followed by
+// the intermediate variables of the linear combinations of the R1CS, and the first NPublic Gates hold the public
+// signals. The wires of different Gates over the same variable are the copy constraints, enforced by the permutation.
+type Arithmetization struct {
+	NWitness int
+	NVars    int
+	NPublic  int
+	Gates    []Gate
+}
+
+// linear is the linear combination coef * v + k, where coef is zero when there is only the constant k
+type linear struct {
+	v    int
+	coef *big.Int
+	k    *big.Int
+}
+
+// Arithmetize converts the R1CS constraints of the compiled Circuit into Gates. Each constraint (A·w) * (B·w) = C·w
+// takes a multiplication Gate, after reducing each linear combination with more than one variable to a single
+// intermediate variable with a chain of addition Gates. The signal "one" of the witness is taken as the constant 1.
+func Arithmetize(circuit circuitcompiler.Circuit) (Arithmetization, error) {
+	a, b, c := circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C
+	if len(a) == 0 || len(b) != len(a) || len(c) != len(a) {
+		return Arithmetization{}, errors.New("plonk: circuit without R1CS")
+	}
+	nWitness := len(a[0])
+	if circuit.NPublic >= nWitness {
+		return Arithmetization{}, errors.New("plonk: more public signals than witness variables")
+	}
+	ar := Arithmetization{
+		NWitness: nWitness,
+		NVars:    nWitness,
+		NPublic:  circuit.NPublic,
+	}
+	zero := big.NewInt(int64(0))
+	one := big.NewInt(int64(1))
+	// a = x_i, with x_i added by the public inputs polynomial
+	for i := 1; i <= circuit.NPublic; i++ {
+		ar.Gates = append(ar.Gates, Gate{QM: zero, QL: one, QR: zero, QO: zero, QC: zero, A: i})
+	}
+	for i := range a {
+		if len(a[i]) != nWitness || len(b[i]) != nWitness || len(c[i]) != nWitness {
+			return Arithmetization{}, errors.New("plonk: R1CS constraint " + strconv.Itoa(i) + " of wrong length")
+		}
+		la := ar.reduce(a[i])
+		lb := ar.reduce(b[i])
+		lc := ar.reduce(c[i])
+		// (ca a + ka) (cb b + kb) - (cc c + kc) = 0
+		ar.Gates = append(ar.Gates, Gate{
+			QM: Utils.FqR.Mul(la.coef, lb.coef),
+			QL: Utils.FqR.Mul(la.coef, lb.k),
+			QR: Utils.FqR.Mul(la.k, lb.coef),
+			QO: Utils.FqR.Neg(lc.coef),
+			QC: Utils.FqR.Sub(Utils.FqR.Mul(la.k, lb.k), lc.k),
+			A:  la.v,
+			B:  lb.v,
+			C:  lc.v,
+		})
+	}
+	return ar, nil
+}
+
+// reduce returns the linear combination of the R1CS row as a single variable, adding the Gates of the intermediate
+// variables c = q_L a + q_R b
+func (ar *Arithmetization) reduce(row []*big.Int) linear {
+	l := linear{v: 0, coef: big.NewInt(int64(0)), k: Utils.FqR.Affine(row[0])}
+	for j := 1; j < len(row); j++ {
+		coef := Utils.FqR.Affine(row[j])
+		if Utils.FqR.IsZero(coef) {
+			continue
+		}
+		if Utils.FqR.IsZero(l.coef) {
+			l.v, l.coef = j, coef
+			continue
+		}
+		out := ar.NVars
+		ar.NVars++
+		ar.Gates = append(ar.Gates, Gate{
+			QM: big.NewInt(int64(0)),
+			QL: l.coef,
+			QR: coef,
+			QO: Utils.FqR.Neg(big.NewInt(int64(1))),
+			QC: big.NewInt(int64(0)),
+			A:  l.v,
+			B:  j,
+			C:  out,
+		})
+		l.v, l.coef = out, big.NewInt(int64(1))
+	}
+	return l
+}
+
+// Witness extends the witness of the Circuit with the values of the intermediate variables
+func (ar Arithmetization) Witness(w []*big.Int) ([]*big.Int, error) {
+	if len(w) != ar.NWitness {
+		return nil, errors.New("plonk: witness of length " + strconv.Itoa(len(w)) + ", expected " + strconv.Itoa(ar.NWitness))
+	}
+	ext := make([]*big.Int, ar.NVars)
+	for i := range ext {
+		if i < len(w) {
+			ext[i] = Utils.FqR.Affine(w[i])
+		} else {
+			ext[i] = big.NewInt(int64(0))
+		}
+	}
+	// the intermediate variables are defined by their addition Gate before being used, also as the wire C of the
+	// multiplication Gate of a constraint
+	defined := make([]bool, ar.NVars)
+	for _, g := range ar.Gates {
+		if g.C >= ar.NWitness && !defined[g.C] {
+			ext[g.C] = Utils.FqR.Add(Utils.FqR.Mul(g.QL, ext[g.A]), Utils.FqR.Mul(g.QR, ext[g.B]))
+			defined[g.C] = true
+		}
+	}
+	return ext, nil
+}
+
+// eval returns q_M a b + q_L a + q_R b + q_O c + q_C over the values of the extended witness
+func (g Gate) eval(ext []*big.Int) *big.Int {
+	a, b, c := ext[g.A], ext[g.B], ext[g.C]
+	r := Utils.FqR.Mul(g.QM, Utils.FqR.Mul(a, b))
+	r = Utils.FqR.Add(r, Utils.FqR.Mul(g.QL, a))
+	r = Utils.FqR.Add(r, Utils.FqR.Mul(g.QR, b))
+	r = Utils.FqR.Add(r, Utils.FqR.Mul(g.QO, c))
+	return Utils.FqR.Add(r, g.QC)
+}
+
+// check returns an error with the first Gate not satisfied by the extended witness, where the public Gates are
+// satisfied by their own public signal
+func (ar Arithmetization) check(ext []*big.Int) error {
+	for i, g := range ar.Gates {
+		v := g.eval(ext)
+		if i < ar.NPublic {
+			v = Utils.FqR.Sub(v, ext[g.A])
+		}
+		if !Utils.FqR.IsZero(v) {
+			return errors.New("plonk: the witness does not satisfy the gate " + strconv.Itoa(i))
+		}
+	}
+	return nil
+}
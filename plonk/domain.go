@@ -0,0 +1,143 @@
+package plonk
+
+import (
+	"errors"
+	"math/big"
+)
+
+// domain is the multiplicative subgroup H = {ω^i} of order n (a power of two) of the Finite Field over R, where the
+// Gates are interpolated
+type domain struct {
+	n        int
+	omega    *big.Int
+	omegaInv *big.Int
+	nInv     *big.Int
+}
+
+// newDomain returns the domain of order n, taking ω = g^((r-1)/n) from the generator g = 5 of the multiplicative
+// group of the Finite Field, whose order r-1 is divisible by 2^28
+func newDomain(n int) (domain, error) {
+	if n < 2 || n > 1<<28 || n&(n-1) != 0 {
+		return domain{}, errors.New("plonk: domain size must be a power of two up to 2^28")
+	}
+	nBig := big.NewInt(int64(n))
+	e := new(big.Int).Sub(Utils.FqR.Q, big.NewInt(int64(1)))
+	e.Div(e, nBig)
+	omega := Utils.FqR.Exp(big.NewInt(int64(5)), e)
+	// ω^(n/2) = -1, so ω is of order n
+	if !Utils.FqR.Equal(Utils.FqR.Exp(omega, big.NewInt(int64(n/2))), Utils.FqR.Neg(big.NewInt(int64(1)))) {
+		return domain{}, errors.New("plonk: no root of unity of order n")
+	}
+	return domain{
+		n:        n,
+		omega:    omega,
+		omegaInv: Utils.FqR.Inverse(omega),
+		nInv:     Utils.FqR.Inverse(nBig),
+	}, nil
+}
+
+// element returns ω^i
+func (d domain) element(i int) *big.Int {
+	return Utils.FqR.Exp(d.omega, big.NewInt(int64(i)))
+}
+
+// elements returns {ω^i} from 0 to n-1
+func (d domain) elements() []*big.Int {
+	r := []*big.Int{big.NewInt(int64(1))}
+	for i := 1; i < d.n; i++ {
+		r = append(r, Utils.FqR.Mul(r[i-1], d.omega))
+	}
+	return r
+}
+
+// fft returns the evaluations over {w^i} of the polynomial p, of len(p) coefficients, where len(p) is a power of two
+// and w is a root of unity of order len(p)
+func fft(p []*big.Int, w *big.Int) []*big.Int {
+	n := len(p)
+	if n == 1 {
+		return []*big.Int{p[0]}
+	}
+	var even, odd []*big.Int
+	for i := 0; i < n; i += 2 {
+		even = append(even, p[i])
+		odd = append(odd, p[i+1])
+	}
+	w2 := Utils.FqR.Square(w)
+	e := fft(even, w2)
+	o := fft(odd, w2)
+	r := make([]*big.Int, n)
+	x := big.NewInt(int64(1))
+	for i := 0; i < n/2; i++ {
+		t := Utils.FqR.Mul(x, o[i])
+		r[i] = Utils.FqR.Add(e[i], t)
+		r[i+n/2] = Utils.FqR.Sub(e[i], t)
+		x = Utils.FqR.Mul(x, w)
+	}
+	return r
+}
+
+// evaluate returns the evaluations over H of the polynomial p of degree lower than n
+func (d domain) evaluate(p []*big.Int) []*big.Int {
+	coefs := make([]*big.Int, d.n)
+	for i := range coefs {
+		if i < len(p) {
+			coefs[i] = p[i]
+		} else {
+			coefs[i] = big.NewInt(int64(0))
+		}
+	}
+	return fft(coefs, d.omega)
+}
+
+// interpolate returns the polynomial of degree lower than n that takes the n values v over H
+func (d domain) interpolate(v []*big.Int) []*big.Int {
+	r := fft(v, d.omegaInv)
+	for i := range r {
+		r[i] = Utils.FqR.Mul(r[i], d.nInv)
+	}
+	return r
+}
+
+// lagrange returns L_i(ζ) = ω^i (ζ^n - 1) / (n (ζ - ω^i)), the Lagrange polynomial of H that is 1 at ω^i, at a point
+// ζ outside H
+func (d domain) lagrange(i int, zeta, zh *big.Int) *big.Int {
+	wi := d.element(i)
+	den := Utils.FqR.Mul(big.NewInt(int64(d.n)), Utils.FqR.Sub(zeta, wi))
+	return Utils.FqR.Div(Utils.FqR.Mul(wi, zh), den)
+}
+
+// divideByVanishing returns p(x) / (x^n - 1) and whether the remainder is zero
+func (d domain) divideByVanishing(p []*big.Int) ([]*big.Int, bool) {
+	if len(p) <= d.n {
+		for _, c := range p {
+			if !Utils.FqR.IsZero(c) {
+				return nil, false
+			}
+		}
+		return []*big.Int{}, true
+	}
+	rem := make([]*big.Int, len(p))
+	copy(rem, p)
+	q := make([]*big.Int, len(p)-d.n)
+	for i := len(p) - 1; i >= d.n; i-- {
+		q[i-d.n] = rem[i]
+		rem[i-d.n] = Utils.FqR.Add(rem[i-d.n], rem[i])
+	}
+	for _, c := range rem[:d.n] {
+		if !Utils.FqR.IsZero(c) {
+			return nil, false
+		}
+	}
+	return q, true
+}
+
+// blind returns p(x) + b(x) (x^n - 1), which takes the same values as p over H
+func (d domain) blind(p []*big.Int, b []*big.Int) []*big.Int {
+	zh := make([]*big.Int, d.n+1)
+	for i := range zh {
+		zh[i] = big.NewInt(int64(0))
+	}
+	zh[0] = Utils.FqR.Neg(big.NewInt(int64(1)))
+	zh[d.n] = big.NewInt(int64(1))
+	return Utils.PF.Add(p, Utils.PF.Mul(b, zh))
+}
@@ -0,0 +1,503 @@
+// Package plonk implements the PLONK zkSNARK (https://eprint.iacr.org/2019/953.pdf) over BN128, with the KZG
+// polynomial commitments of the kzg package, so a single universal SRS is used by all the circuits up to its size
+// instead of a trusted setup for each circuit.
+package plonk
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/kzg"
+	"github.com/arnaucube/go-snark/r1csqap"
+	"github.com/arnaucube/go-snark/transcript"
+)
+
+// ProvingKey is the data needed by the prover: the Arithmetization of the circuit, the selector and permutation
+// polynomials, the SRS, and the VerifyingKey to which the proofs are bound
+type ProvingKey struct {
+	Vk              VerifyingKey
+	Arithmetization Arithmetization
+	QM, QL, QR      []*big.Int
+	QO, QC          []*big.Int
+	S1, S2, S3      []*big.Int // permutation polynomials Sσ1, Sσ2, Sσ3
+	SRS             kzg.SRS
+}
+
+// VerifyingKey is the data needed by the verifier: the commitments to the selector and permutation polynomials, the
+// size of the domain and the part of the SRS used by the pairing check
+type VerifyingKey struct {
+	N          int      // number of Gates, a power of two
+	NPublic    int      // number of public signals
+	K1, K2     *big.Int // the cosets k1 H and k2 H hold the positions of the wires B and C
+	QM, QL, QR [3]*big.Int
+	QO, QC     [3]*big.Int
+	S1, S2, S3 [3]*big.Int
+	SRS        kzg.SRS // {G1}, {G2, τ G2}
+}
+
+// Proof is the PLONK proof: the commitments to the wire polynomials, the permutation accumulator and the parts of
+// the quotient, the evaluations at the challenge ζ, and the KZG openings at ζ and ζω
+type Proof struct {
+	A, B, C        [3]*big.Int
+	Z              [3]*big.Int
+	TLo, TMid, THi [3]*big.Int
+	WZeta          [3]*big.Int
+	WZetaOmega     [3]*big.Int
+	Evals          struct {
+		A, B, C *big.Int
+		S1, S2  *big.Int
+		ZOmega  *big.Int // z(ζω)
+		R       *big.Int // linearisation polynomial r(ζ)
+	}
+}
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+	PF  r1csqap.PolynomialField
+}
+
+// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the plonk operations
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	fqR := fields.NewFq(bn.R)
+	return utils{
+		Bn:  bn,
+		FqR: fqR,
+		PF:  r1csqap.NewPolynomialField(fqR),
+	}
+}
+
+// cosetShifts returns the smallest k1, k2 > 1 such that H, k1 H and k2 H are disjoint, that is k1^n != 1, k2^n != 1
+// and (k2/k1)^n != 1
+func cosetShifts(n int) (*big.Int, *big.Int) {
+	nBig := big.NewInt(int64(n))
+	notInH := func(k *big.Int) bool {
+		return !Utils.FqR.Equal(Utils.FqR.Exp(k, nBig), big.NewInt(int64(1)))
+	}
+	k1 := big.NewInt(int64(2))
+	for !notInH(k1) {
+		k1 = Utils.FqR.Add(k1, big.NewInt(int64(1)))
+	}
+	k2 := Utils.FqR.Add(k1, big.NewInt(int64(1)))
+	for !notInH(k2) || !notInH(Utils.FqR.Div(k2, k1)) {
+		k2 = Utils.FqR.Add(k2, big.NewInt(int64(1)))
+	}
+	return k1, k2
+}
+
+// Setup arithmetizes the compiled Circuit (with its R1CS generated) and derives its keys from the universal SRS, which
+// must support polynomials up to the degree n+2 for circuits of n Gates
+func Setup(circuit circuitcompiler.Circuit, srs *kzg.SRS) (ProvingKey, VerifyingKey, error) {
+	ar, err := Arithmetize(circuit)
+	if err != nil {
+		return ProvingKey{}, VerifyingKey{}, err
+	}
+	n := 4
+	for n < len(ar.Gates) {
+		n *= 2
+	}
+	if len(srs.G1) < n+3 || len(srs.G2) < 2 {
+		return ProvingKey{}, VerifyingKey{}, errors.New("plonk: circuit of " + strconv.Itoa(n) + " gates bigger than the SRS of degree " + strconv.Itoa(len(srs.G1)-1))
+	}
+	d, err := newDomain(n)
+	if err != nil {
+		return ProvingKey{}, VerifyingKey{}, err
+	}
+	k1, k2 := cosetShifts(n)
+
+	// the padding Gates have all their selectors zero, and their wires over the variable 0
+	zero := big.NewInt(int64(0))
+	gates := append([]Gate{}, ar.Gates...)
+	for len(gates) < n {
+		gates = append(gates, Gate{QM: zero, QL: zero, QR: zero, QO: zero, QC: zero})
+	}
+	var qm, ql, qr, qo, qc []*big.Int
+	for _, g := range gates {
+		qm = append(qm, g.QM)
+		ql = append(ql, g.QL)
+		qr = append(qr, g.QR)
+		qo = append(qo, g.QO)
+		qc = append(qc, g.QC)
+	}
+
+	// the wire positions are i, n+i and 2n+i for the wires A, B and C of the Gate i, with the values ω^i, k1 ω^i
+	// and k2 ω^i, and σ moves each position to the next one over the same variable
+	positions := make([][]int, ar.NVars)
+	for i, g := range gates {
+		positions[g.A] = append(positions[g.A], i)
+		positions[g.B] = append(positions[g.B], n+i)
+		positions[g.C] = append(positions[g.C], 2*n+i)
+	}
+	sigma := make([]int, 3*n)
+	for _, p := range positions {
+		for j := range p {
+			sigma[p[j]] = p[(j+1)%len(p)]
+		}
+	}
+	h := d.elements()
+	shifts := []*big.Int{big.NewInt(int64(1)), k1, k2}
+	sigmaEvals := make([]*big.Int, 3*n)
+	for i, s := range sigma {
+		sigmaEvals[i] = Utils.FqR.Mul(shifts[s/n], h[s%n])
+	}
+
+	pk := ProvingKey{
+		Arithmetization: ar,
+		QM:              d.interpolate(qm),
+		QL:              d.interpolate(ql),
+		QR:              d.interpolate(qr),
+		QO:              d.interpolate(qo),
+		QC:              d.interpolate(qc),
+		S1:              d.interpolate(sigmaEvals[:n]),
+		S2:              d.interpolate(sigmaEvals[n : 2*n]),
+		S3:              d.interpolate(sigmaEvals[2*n:]),
+		SRS:             kzg.SRS{G1: srs.G1[:n+3], G2: srs.G2[:2]},
+	}
+	pk.Vk = VerifyingKey{
+		N:       n,
+		NPublic: ar.NPublic,
+		K1:      k1,
+		K2:      k2,
+		SRS:     kzg.SRS{G1: srs.G1[:1], G2: srs.G2[:2]},
+	}
+	for _, c := range []struct {
+		p   []*big.Int
+		dst *[3]*big.Int
+	}{
+		{pk.QM, &pk.Vk.QM}, {pk.QL, &pk.Vk.QL}, {pk.QR, &pk.Vk.QR}, {pk.QO, &pk.Vk.QO}, {pk.QC, &pk.Vk.QC},
+		{pk.S1, &pk.Vk.S1}, {pk.S2, &pk.Vk.S2}, {pk.S3, &pk.Vk.S3},
+	} {
+		*c.dst, err = pk.SRS.Commit(c.p)
+		if err != nil {
+			return ProvingKey{}, VerifyingKey{}, err
+		}
+	}
+	return pk, pk.Vk, nil
+}
+
+// newTranscript returns the Fiat-Shamir transcript of the proof, bound to the VerifyingKey and the public signals
+func newTranscript(vk VerifyingKey, publicSignals []*big.Int) *transcript.Transcript {
+	t := transcript.New("plonk", transcript.SHA256)
+	t.AppendScalars("domain", big.NewInt(int64(vk.N)), vk.K1, vk.K2)
+	t.AppendG1("selectors", vk.QM, vk.QL, vk.QR, vk.QO, vk.QC)
+	t.AppendG1("permutation", vk.S1, vk.S2, vk.S3)
+	t.AppendScalars("public signals", publicSignals...)
+	return t
+}
+
+// randScalars returns n random blinding scalars
+func randScalars(n int) ([]*big.Int, error) {
+	var r []*big.Int
+	for i := 0; i < n; i++ {
+		x, err := Utils.FqR.Rand()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, x)
+	}
+	return r, nil
+}
+
+// scale returns s p(x)
+func scale(p []*big.Int, s *big.Int) []*big.Int {
+	r := make([]*big.Int, len(p))
+	for i := range p {
+		r[i] = Utils.FqR.Mul(p[i], s)
+	}
+	return r
+}
+
+// shift returns p(s x)
+func shift(p []*big.Int, s *big.Int) []*big.Int {
+	r := make([]*big.Int, len(p))
+	si := big.NewInt(int64(1))
+	for i := range p {
+		r[i] = Utils.FqR.Mul(p[i], si)
+		si = Utils.FqR.Mul(si, s)
+	}
+	return r
+}
+
+// permutationFactor returns w(x) + β s(x) + γ
+func permutationFactor(w, s []*big.Int, beta, gamma *big.Int) []*big.Int {
+	return Utils.PF.Add(Utils.PF.Add(w, scale(s, beta)), []*big.Int{gamma})
+}
+
+// Prove generates the Proof of the witness w of the circuit of the ProvingKey
+func Prove(pk ProvingKey, w []*big.Int) (Proof, error) {
+	vk := pk.Vk
+	n := vk.N
+	d, err := newDomain(n)
+	if err != nil {
+		return Proof{}, err
+	}
+	ext, err := pk.Arithmetization.Witness(w)
+	if err != nil {
+		return Proof{}, err
+	}
+	if err := pk.Arithmetization.check(ext); err != nil {
+		return Proof{}, err
+	}
+	publicSignals := ext[1 : vk.NPublic+1]
+	blinding, err := randScalars(9)
+	if err != nil {
+		return Proof{}, err
+	}
+	var proof Proof
+	t := newTranscript(vk, publicSignals)
+
+	// round 1, the wire polynomials
+	wa := make([]*big.Int, n)
+	wb := make([]*big.Int, n)
+	wc := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		g := Gate{}
+		if i < len(pk.Arithmetization.Gates) {
+			g = pk.Arithmetization.Gates[i]
+		}
+		wa[i], wb[i], wc[i] = ext[g.A], ext[g.B], ext[g.C]
+	}
+	a := d.blind(d.interpolate(wa), blinding[0:2])
+	b := d.blind(d.interpolate(wb), blinding[2:4])
+	c := d.blind(d.interpolate(wc), blinding[4:6])
+	for _, x := range []struct {
+		p   []*big.Int
+		dst *[3]*big.Int
+	}{{a, &proof.A}, {b, &proof.B}, {c, &proof.C}} {
+		*x.dst, err = pk.SRS.Commit(x.p)
+		if err != nil {
+			return Proof{}, err
+		}
+	}
+	t.AppendG1("a, b, c", proof.A, proof.B, proof.C)
+
+	// round 2, the permutation accumulator z, with z(ω^0) = 1 and
+	// z(ω^(i+1)) = z(ω^i) Π_j (w_j(ω^i) + β k_j ω^i + γ) / (w_j(ω^i) + β σ_j(ω^i) + γ)
+	beta := t.Challenge("β")
+	gamma := t.Challenge("γ")
+	h := d.elements()
+	s1, s2, s3 := d.evaluate(pk.S1), d.evaluate(pk.S2), d.evaluate(pk.S3)
+	acc := []*big.Int{big.NewInt(int64(1))}
+	for i := 0; i < n-1; i++ {
+		num := big.NewInt(int64(1))
+		den := big.NewInt(int64(1))
+		for j, k := range []*big.Int{big.NewInt(int64(1)), vk.K1, vk.K2} {
+			v := []*big.Int{wa[i], wb[i], wc[i]}[j]
+			s := []*big.Int{s1[i], s2[i], s3[i]}[j]
+			num = Utils.FqR.Mul(num, Utils.FqR.Add(Utils.FqR.Add(v, Utils.FqR.Mul(beta, Utils.FqR.Mul(k, h[i]))), gamma))
+			den = Utils.FqR.Mul(den, Utils.FqR.Add(Utils.FqR.Add(v, Utils.FqR.Mul(beta, s)), gamma))
+		}
+		acc = append(acc, Utils.FqR.Mul(acc[i], Utils.FqR.Div(num, den)))
+	}
+	z := d.blind(d.interpolate(acc), blinding[6:9])
+	proof.Z, err = pk.SRS.Commit(z)
+	if err != nil {
+		return Proof{}, err
+	}
+	t.AppendG1("z", proof.Z)
+
+	// round 3, the quotient t(x) of the gate, permutation and z(ω^0) = 1 constraints by x^n - 1
+	alpha := t.Challenge("α")
+	piEvals := make([]*big.Int, n)
+	for i := range piEvals {
+		piEvals[i] = big.NewInt(int64(0))
+		if i < vk.NPublic {
+			piEvals[i] = Utils.FqR.Neg(publicSignals[i])
+		}
+	}
+	gate := Utils.PF.Mul(Utils.PF.Mul(a, b), pk.QM)
+	gate = Utils.PF.Add(gate, Utils.PF.Mul(a, pk.QL))
+	gate = Utils.PF.Add(gate, Utils.PF.Mul(b, pk.QR))
+	gate = Utils.PF.Add(gate, Utils.PF.Mul(c, pk.QO))
+	gate = Utils.PF.Add(gate, d.interpolate(piEvals))
+	gate = Utils.PF.Add(gate, pk.QC)
+
+	x := []*big.Int{big.NewInt(int64(0)), big.NewInt(int64(1))}
+	perm1 := Utils.PF.Mul(permutationFactor(a, x, beta, gamma), permutationFactor(b, scale(x, vk.K1), beta, gamma))
+	perm1 = Utils.PF.Mul(Utils.PF.Mul(perm1, permutationFactor(c, scale(x, vk.K2), beta, gamma)), z)
+	perm2 := Utils.PF.Mul(permutationFactor(a, pk.S1, beta, gamma), permutationFactor(b, pk.S2, beta, gamma))
+	perm2 = Utils.PF.Mul(Utils.PF.Mul(perm2, permutationFactor(c, pk.S3, beta, gamma)), shift(z, d.omega))
+
+	l0Evals := make([]*big.Int, n)
+	for i := range l0Evals {
+		l0Evals[i] = big.NewInt(int64(0))
+	}
+	l0Evals[0] = big.NewInt(int64(1))
+	l0 := d.interpolate(l0Evals)
+	start := Utils.PF.Mul(Utils.PF.Sub(z, []*big.Int{big.NewInt(int64(1))}), l0)
+
+	numerator := Utils.PF.Add(gate, scale(Utils.PF.Sub(perm1, perm2), alpha))
+	numerator = Utils.PF.Add(numerator, scale(start, Utils.FqR.Square(alpha)))
+	quotient, ok := d.divideByVanishing(numerator)
+	if !ok {
+		return Proof{}, errors.New("plonk: the witness does not satisfy the permutation")
+	}
+	// t(x) = t_lo(x) + x^(n+2) t_mid(x) + x^(2n+4) t_hi(x), with n+2 coefficients each
+	for len(quotient) < 3*(n+2) {
+		quotient = append(quotient, big.NewInt(int64(0)))
+	}
+	tLo, tMid, tHi := quotient[:n+2], quotient[n+2:2*n+4], quotient[2*n+4:]
+	for _, x := range []struct {
+		p   []*big.Int
+		dst *[3]*big.Int
+	}{{tLo, &proof.TLo}, {tMid, &proof.TMid}, {tHi, &proof.THi}} {
+		*x.dst, err = pk.SRS.Commit(x.p)
+		if err != nil {
+			return Proof{}, err
+		}
+	}
+	t.AppendG1("t", proof.TLo, proof.TMid, proof.THi)
+
+	// round 4, the evaluations at ζ and the linearisation polynomial r(x)
+	zeta := t.Challenge("ζ")
+	zetaOmega := Utils.FqR.Mul(zeta, d.omega)
+	proof.Evals.A = Utils.PF.Eval(a, zeta)
+	proof.Evals.B = Utils.PF.Eval(b, zeta)
+	proof.Evals.C = Utils.PF.Eval(c, zeta)
+	proof.Evals.S1 = Utils.PF.Eval(pk.S1, zeta)
+	proof.Evals.S2 = Utils.PF.Eval(pk.S2, zeta)
+	proof.Evals.ZOmega = Utils.PF.Eval(z, zetaOmega)
+	zCoef, s3Coef := linearisationScalars(vk, proof, beta, gamma, alpha, zeta, d)
+	r := Utils.PF.Add(scale(pk.QM, Utils.FqR.Mul(proof.Evals.A, proof.Evals.B)), scale(pk.QL, proof.Evals.A))
+	r = Utils.PF.Add(r, scale(pk.QR, proof.Evals.B))
+	r = Utils.PF.Add(r, scale(pk.QO, proof.Evals.C))
+	r = Utils.PF.Add(r, pk.QC)
+	r = Utils.PF.Add(r, scale(z, zCoef))
+	r = Utils.PF.Add(r, scale(pk.S3, s3Coef))
+	proof.Evals.R = Utils.PF.Eval(r, zeta)
+	t.AppendScalars("evaluations", proof.Evals.A, proof.Evals.B, proof.Evals.C, proof.Evals.S1, proof.Evals.S2, proof.Evals.ZOmega, proof.Evals.R)
+
+	// round 5, the openings of the aggregation with the powers of v of the polynomials at ζ, and of z at ζω
+	v := t.Challenge("v")
+	zetaN2 := Utils.FqR.Exp(zeta, big.NewInt(int64(n+2)))
+	agg := Utils.PF.Add(tLo, scale(tMid, zetaN2))
+	agg = Utils.PF.Add(agg, scale(tHi, Utils.FqR.Square(zetaN2)))
+	vi := big.NewInt(int64(1))
+	for _, p := range [][]*big.Int{r, a, b, c, pk.S1, pk.S2} {
+		vi = Utils.FqR.Mul(vi, v)
+		agg = Utils.PF.Add(agg, scale(p, vi))
+	}
+	openZeta, err := pk.SRS.Open(agg, zeta)
+	if err != nil {
+		return Proof{}, err
+	}
+	openZetaOmega, err := pk.SRS.Open(z, zetaOmega)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof.WZeta = openZeta.W
+	proof.WZetaOmega = openZetaOmega.W
+	return proof, nil
+}
+
+// linearisationScalars returns the scalars of z(x) and Sσ3(x) in the linearisation polynomial,
+// α (ā + βζ + γ)(b̄ + βk1ζ + γ)(c̄ + βk2ζ + γ) + α² L_0(ζ) and -α β z̄ω (ā + βs̄σ1 + γ)(b̄ + βs̄σ2 + γ)
+func linearisationScalars(vk VerifyingKey, proof Proof, beta, gamma, alpha, zeta *big.Int, d domain) (*big.Int, *big.Int) {
+	e := proof.Evals
+	zh := Utils.FqR.Sub(Utils.FqR.Exp(zeta, big.NewInt(int64(vk.N))), big.NewInt(int64(1)))
+	l0 := d.lagrange(0, zeta, zh)
+	factor := func(w, s *big.Int) *big.Int {
+		return Utils.FqR.Add(Utils.FqR.Add(w, Utils.FqR.Mul(beta, s)), gamma)
+	}
+	zCoef := Utils.FqR.Mul(factor(e.A, zeta), factor(e.B, Utils.FqR.Mul(vk.K1, zeta)))
+	zCoef = Utils.FqR.Mul(Utils.FqR.Mul(zCoef, factor(e.C, Utils.FqR.Mul(vk.K2, zeta))), alpha)
+	zCoef = Utils.FqR.Add(zCoef, Utils.FqR.Mul(Utils.FqR.Square(alpha), l0))
+	s3Coef := Utils.FqR.Mul(factor(e.A, e.S1), factor(e.B, e.S2))
+	s3Coef = Utils.FqR.Mul(Utils.FqR.Mul(s3Coef, Utils.FqR.Mul(alpha, beta)), e.ZOmega)
+	return zCoef, Utils.FqR.Neg(s3Coef)
+}
+
+// Verify verifies the Proof for the public signals against the VerifyingKey, recomputing the challenges of the
+// transcript and the evaluation t(ζ) of the quotient, and checking with a single pairing check the openings at ζ of
+// the aggregation of t, r, a, b, c, Sσ1 and Sσ2, and at ζω of z. It returns an error if the VerifyingKey, the Proof or
+// the public signals are malformed.
+func Verify(vk VerifyingKey, proof Proof, publicSignals []*big.Int) (bool, error) {
+	if err := vk.Validate(); err != nil {
+		return false, err
+	}
+	if err := proof.Validate(); err != nil {
+		return false, err
+	}
+	if err := Utils.Bn.CheckPublicSignals("plonk", publicSignals, vk.NPublic); err != nil {
+		return false, err
+	}
+	d, err := newDomain(vk.N)
+	if err != nil {
+		return false, err
+	}
+	e := proof.Evals
+
+	t := newTranscript(vk, publicSignals)
+	t.AppendG1("a, b, c", proof.A, proof.B, proof.C)
+	beta := t.Challenge("β")
+	gamma := t.Challenge("γ")
+	t.AppendG1("z", proof.Z)
+	alpha := t.Challenge("α")
+	t.AppendG1("t", proof.TLo, proof.TMid, proof.THi)
+	zeta := t.Challenge("ζ")
+	t.AppendScalars("evaluations", e.A, e.B, e.C, e.S1, e.S2, e.ZOmega, e.R)
+	v := t.Challenge("v")
+
+	zh := Utils.FqR.Sub(Utils.FqR.Exp(zeta, big.NewInt(int64(vk.N))), big.NewInt(int64(1)))
+	if Utils.FqR.IsZero(zh) {
+		return false, nil
+	}
+	pi := big.NewInt(int64(0))
+	for i, s := range publicSignals {
+		pi = Utils.FqR.Sub(pi, Utils.FqR.Mul(s, d.lagrange(i, zeta, zh)))
+	}
+	l0 := d.lagrange(0, zeta, zh)
+
+	// t(ζ) = (r̄ + PI(ζ) - α (ā + βs̄σ1 + γ)(b̄ + βs̄σ2 + γ)(c̄ + γ) z̄ω - α² L_0(ζ)) / Z_H(ζ)
+	perm := Utils.FqR.Mul(Utils.FqR.Add(Utils.FqR.Add(e.A, Utils.FqR.Mul(beta, e.S1)), gamma),
+		Utils.FqR.Add(Utils.FqR.Add(e.B, Utils.FqR.Mul(beta, e.S2)), gamma))
+	perm = Utils.FqR.Mul(Utils.FqR.Mul(perm, Utils.FqR.Add(e.C, gamma)), Utils.FqR.Mul(e.ZOmega, alpha))
+	tZeta := Utils.FqR.Add(e.R, pi)
+	tZeta = Utils.FqR.Sub(tZeta, perm)
+	tZeta = Utils.FqR.Sub(tZeta, Utils.FqR.Mul(Utils.FqR.Square(alpha), l0))
+	tZeta = Utils.FqR.Div(tZeta, zh)
+
+	// [r] = ā b̄ [qM] + ā [qL] + b̄ [qR] + c̄ [qO] + [qC] + zCoef [z] + s3Coef [Sσ3]
+	zCoef, s3Coef := linearisationScalars(vk, proof, beta, gamma, alpha, zeta, d)
+	r := linearCombination(
+		[][3]*big.Int{vk.QM, vk.QL, vk.QR, vk.QO, vk.QC, proof.Z, vk.S3},
+		[]*big.Int{Utils.FqR.Mul(e.A, e.B), e.A, e.B, e.C, big.NewInt(int64(1)), zCoef, s3Coef})
+
+	// the aggregation with the powers of v, [F] = [tLo] + ζ^(n+2) [tMid] + ζ^(2n+4) [tHi] + v [r] + v² [a] + ... + v⁶ [Sσ2]
+	zetaN2 := Utils.FqR.Exp(zeta, big.NewInt(int64(vk.N+2)))
+	points := [][3]*big.Int{proof.TLo, proof.TMid, proof.THi, r, proof.A, proof.B, proof.C, vk.S1, vk.S2}
+	scalars := []*big.Int{big.NewInt(int64(1)), zetaN2, Utils.FqR.Square(zetaN2)}
+	evals := []*big.Int{e.R, e.A, e.B, e.C, e.S1, e.S2}
+	aggEval := tZeta
+	vi := big.NewInt(int64(1))
+	for _, x := range evals {
+		vi = Utils.FqR.Mul(vi, v)
+		scalars = append(scalars, vi)
+		aggEval = Utils.FqR.Add(aggEval, Utils.FqR.Mul(vi, x))
+	}
+	agg := linearCombination(points, scalars)
+
+	return vk.SRS.BatchVerify(
+		[][3]*big.Int{agg, proof.Z},
+		[]*big.Int{zeta, Utils.FqR.Mul(zeta, d.omega)},
+		[]kzg.Proof{{Eval: aggEval, W: proof.WZeta}, {Eval: e.ZOmega, W: proof.WZetaOmega}})
+}
+
+// linearCombination returns Σ scalars[i] points[i]
+func linearCombination(points [][3]*big.Int, scalars []*big.Int) [3]*big.Int {
+	res := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	for i := range points {
+		res = Utils.Bn.G1.Add(res, Utils.Bn.G1.MulScalarGLV(points[i], scalars[i]))
+	}
+	return res
+}
@@ -0,0 +1,240 @@
+package plonk
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseCircuitFile(t *testing.T, path string) *circuitcompiler.Circuit {
+	circuitFile, err := os.Open(path)
+	assert.Nil(t, err)
+	defer circuitFile.Close()
+	parser := circuitcompiler.NewParser(bufio.NewReader(circuitFile))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+	return circuit
+}
+
+func newTestSRS(t *testing.T, degree int) *kzg.SRS {
+	tau, err := Utils.FqR.Rand()
+	assert.Nil(t, err)
+	srs, err := kzg.NewSRS(tau, degree, 1)
+	assert.Nil(t, err)
+	return srs
+}
+
+func TestDomain(t *testing.T) {
+	d, err := newDomain(8)
+	assert.Nil(t, err)
+	assert.True(t, Utils.FqR.Equal(Utils.FqR.Exp(d.omega, big.NewInt(int64(8))), big.NewInt(int64(1))))
+
+	// the interpolation over H is the inverse of the evaluation over H
+	var v []*big.Int
+	for i := 0; i < 8; i++ {
+		x, err := Utils.FqR.Rand()
+		assert.Nil(t, err)
+		v = append(v, x)
+	}
+	p := d.interpolate(v)
+	assert.Equal(t, v, d.evaluate(p))
+	for i, x := range d.elements() {
+		assert.Equal(t, v[i], Utils.PF.Eval(p, x))
+	}
+
+	// the blinded polynomial takes the same values over H, and is divisible by x^n - 1 once they are subtracted
+	b := d.blind(p, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))})
+	assert.Equal(t, 10, len(b))
+	for i, x := range d.elements() {
+		assert.Equal(t, v[i], Utils.PF.Eval(b, x))
+	}
+	q, ok := d.divideByVanishing(Utils.PF.Sub(b, p))
+	assert.True(t, ok)
+	assert.Equal(t, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(4))}, q)
+	_, ok = d.divideByVanishing(b)
+	assert.False(t, ok)
+
+	// the Lagrange polynomials outside H
+	zeta := big.NewInt(int64(1234))
+	zh := Utils.FqR.Sub(Utils.FqR.Exp(zeta, big.NewInt(int64(8))), big.NewInt(int64(1)))
+	l := make([]*big.Int, 8)
+	for i := range l {
+		l[i] = big.NewInt(int64(0))
+	}
+	l[3] = big.NewInt(int64(1))
+	assert.Equal(t, Utils.PF.Eval(d.interpolate(l), zeta), d.lagrange(3, zeta, zh))
+
+	_, err = newDomain(12)
+	assert.NotNil(t, err)
+}
+
+func TestArithmetize(t *testing.T) {
+	circuit := parseCircuitFile(t, "../circuitexamples/function.circuit")
+	ar, err := Arithmetize(*circuit)
+	assert.Nil(t, err)
+	assert.Equal(t, circuit.NPublic, ar.NPublic)
+	assert.Equal(t, len(circuit.Signals), ar.NWitness)
+
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	ext, err := ar.Witness(w)
+	assert.Nil(t, err)
+	assert.Nil(t, ar.check(ext))
+
+	// a wrong value of the witness does not satisfy the gates
+	w[2] = big.NewInt(int64(4))
+	ext, err = ar.Witness(w)
+	assert.Nil(t, err)
+	assert.NotNil(t, ar.check(ext))
+	_, err = ar.Witness(w[1:])
+	assert.NotNil(t, err)
+
+	// the linear combinations of the optimized circuit take intermediate variables
+	optimized, _, err := circuit.Optimize()
+	assert.Nil(t, err)
+	arOpt, err := Arithmetize(*optimized)
+	assert.Nil(t, err)
+	assert.True(t, arOpt.NVars > arOpt.NWitness)
+	wOpt, err := optimized.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	ext, err = arOpt.Witness(wOpt)
+	assert.Nil(t, err)
+	assert.Nil(t, arOpt.check(ext))
+
+	_, err = Arithmetize(circuitcompiler.Circuit{})
+	assert.NotNil(t, err)
+}
+
+func testPlonk(t *testing.T, circuit circuitcompiler.Circuit, srs *kzg.SRS, privateInputs, publicSignals []*big.Int) {
+	w, err := circuit.CalculateWitness(privateInputs, publicSignals)
+	assert.Nil(t, err)
+	assert.True(t, circuit.IsSatisfied(w))
+
+	pk, vk, err := Setup(circuit, srs)
+	assert.Nil(t, err)
+	fmt.Println("plonk gates:", len(pk.Arithmetization.Gates), "domain:", vk.N)
+	proof, err := Prove(pk, w)
+	assert.Nil(t, err)
+
+	verified, err := Verify(vk, proof, publicSignals)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	// wrong public signals
+	wrongSignals := make([]*big.Int, len(publicSignals))
+	for i := range publicSignals {
+		wrongSignals[i] = Utils.FqR.Add(publicSignals[i], big.NewInt(int64(1)))
+	}
+	verified, err = Verify(vk, proof, wrongSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// tampered proof
+	tampered := proof
+	tampered.Evals.A = Utils.FqR.Add(proof.Evals.A, big.NewInt(int64(1)))
+	verified, err = Verify(vk, tampered, publicSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+	tampered = proof
+	tampered.C = proof.A
+	verified, err = Verify(vk, tampered, publicSignals)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// the proofs are randomized
+	proof2, err := Prove(pk, w)
+	assert.Nil(t, err)
+	assert.False(t, Utils.Bn.G1.Equal(proof.A, proof2.A))
+	verified, err = Verify(vk, proof2, publicSignals)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+}
+
+func TestPlonkFactorCircuit(t *testing.T) {
+	srs := newTestSRS(t, 35)
+	circuit := parseCircuitFile(t, "../circuitexamples/factor.circuit")
+	testPlonk(t, *circuit, srs, []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(5))}, []*big.Int{big.NewInt(int64(15))})
+}
+
+func TestPlonkFunctionCircuit(t *testing.T) {
+	srs := newTestSRS(t, 35)
+	circuit := parseCircuitFile(t, "../circuitexamples/function.circuit")
+	testPlonk(t, *circuit, srs, []*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+
+	// the same SRS is used by the optimized circuit
+	optimized, _, err := circuit.Optimize()
+	assert.Nil(t, err)
+	testPlonk(t, *optimized, srs, []*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+}
+
+func TestPlonkImportCircuit(t *testing.T) {
+	// the imported file is registered as a library, so it is found from any working directory
+	imported, err := ioutil.ReadFile("../circuitexamples/imported-example.circuit")
+	assert.Nil(t, err)
+	circuitcompiler.RegisterLibrary("imported-example.circuit", string(imported))
+	circuit := parseCircuitFile(t, "../circuitexamples/import-example.circuit")
+	testPlonk(t, *circuit, newTestSRS(t, 35), []*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+}
+
+func TestPlonkDivisionCircuit(t *testing.T) {
+	code := `
+	func main(private a, private b, public c, public d):
+		e = a / b
+		f = e - a
+		g = f + 3
+		equals(c, g)
+		h = b * b
+		equals(d, h)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	circuit.GenerateR1CS()
+	// 12 / 4 - 12 + 3 = -6
+	c := Utils.FqR.Neg(big.NewInt(int64(6)))
+	testPlonk(t, *circuit, newTestSRS(t, 35), []*big.Int{big.NewInt(int64(12)), big.NewInt(int64(4))}, []*big.Int{c, big.NewInt(int64(16))})
+}
+
+func TestPlonkErrors(t *testing.T) {
+	circuit := parseCircuitFile(t, "../circuitexamples/function.circuit")
+
+	// SRS too small for the circuit
+	_, _, err := Setup(*circuit, newTestSRS(t, 8))
+	assert.NotNil(t, err)
+
+	pk, vk, err := Setup(*circuit, newTestSRS(t, 35))
+	assert.Nil(t, err)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+
+	// a witness that does not satisfy the circuit
+	wrongW := append([]*big.Int{}, w...)
+	wrongW[1] = big.NewInt(int64(36))
+	_, err = Prove(pk, wrongW)
+	assert.NotNil(t, err)
+
+	proof, err := Prove(pk, w)
+	assert.Nil(t, err)
+	_, err = Verify(vk, proof, nil)
+	assert.Equal(t, "plonk: wrong number of public signals, expected 1, got 0", err.Error())
+	_, err = Verify(vk, proof, []*big.Int{Utils.FqR.Q})
+	assert.Equal(t, "plonk: public signal 0 not inside the Finite Field", err.Error())
+	malformed := proof
+	malformed.Z = [3]*big.Int{proof.Z[0], big.NewInt(int64(1)), proof.Z[2]}
+	_, err = Verify(vk, malformed, []*big.Int{big.NewInt(int64(35))})
+	assert.Equal(t, "plonk: proof z not on the curve", err.Error())
+	malformed = proof
+	malformed.Evals.R = nil
+	_, err = Verify(vk, malformed, []*big.Int{big.NewInt(int64(35))})
+	assert.Equal(t, "plonk: proof r̄ not inside the Finite Field", err.Error())
+}
@@ -0,0 +1,65 @@
+package plonk
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Validate checks the size of the domain and the points of the VerifyingKey
+func (vk VerifyingKey) Validate() error {
+	if _, err := newDomain(vk.N); err != nil {
+		return err
+	}
+	if vk.NPublic < 0 || vk.NPublic > vk.N {
+		return errors.New("plonk: verifying key with wrong number of public signals")
+	}
+	if vk.K1 == nil || vk.K2 == nil {
+		return errors.New("plonk: verifying key without coset shifts")
+	}
+	if len(vk.SRS.G1) < 1 || len(vk.SRS.G2) < 2 {
+		return errors.New("plonk: verifying key without SRS")
+	}
+	points := []struct {
+		name string
+		p    [3]*big.Int
+	}{
+		{"qM", vk.QM}, {"qL", vk.QL}, {"qR", vk.QR}, {"qO", vk.QO}, {"qC", vk.QC},
+		{"Sσ1", vk.S1}, {"Sσ2", vk.S2}, {"Sσ3", vk.S3},
+	}
+	for _, x := range points {
+		if err := Utils.Bn.CheckG1("plonk", "verifying key "+x.name, x.p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks that all the points of the Proof are in G1 and all its evaluations are inside the Finite Field
+func (proof Proof) Validate() error {
+	points := []struct {
+		name string
+		p    [3]*big.Int
+	}{
+		{"a", proof.A}, {"b", proof.B}, {"c", proof.C}, {"z", proof.Z},
+		{"tLo", proof.TLo}, {"tMid", proof.TMid}, {"tHi", proof.THi},
+		{"Wζ", proof.WZeta}, {"Wζω", proof.WZetaOmega},
+	}
+	for _, x := range points {
+		if err := Utils.Bn.CheckG1("plonk", "proof "+x.name, x.p); err != nil {
+			return err
+		}
+	}
+	evals := []struct {
+		name string
+		x    *big.Int
+	}{
+		{"ā", proof.Evals.A}, {"b̄", proof.Evals.B}, {"c̄", proof.Evals.C},
+		{"s̄σ1", proof.Evals.S1}, {"s̄σ2", proof.Evals.S2}, {"z̄ω", proof.Evals.ZOmega}, {"r̄", proof.Evals.R},
+	}
+	for _, x := range evals {
+		if err := Utils.Bn.CheckScalar("plonk", "proof "+x.name, x.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -69,18 +69,21 @@ func (pf PolynomialField) Mul(a, b []*big.Int) []*big.Int {
 // Div divides two polinomials over the Finite Field, returning the result and the remainder
 func (pf PolynomialField) Div(a, b []*big.Int) ([]*big.Int, []*big.Int) {
 	// https://en.wikipedia.org/wiki/Division_algorithm
+	if len(a) < len(b) {
+		return []*big.Int{}, a
+	}
 	r := ArrayOfBigZeros(len(a) - len(b) + 1)
-	rem := a
-	for len(rem) >= len(b) {
-		l := pf.F.Div(rem[len(rem)-1], b[len(b)-1])
-		pos := len(rem) - len(b)
+	rem := make([]*big.Int, len(a))
+	copy(rem, a)
+	invLead := pf.F.Inverse(b[len(b)-1])
+	for pos := len(a) - len(b); pos >= 0; pos-- {
+		l := pf.F.Mul(rem[pos+len(b)-1], invLead)
 		r[pos] = l
-		aux := ArrayOfBigZeros(pos)
-		aux1 := append(aux, l)
-		aux2 := pf.Sub(rem, pf.Mul(b, aux1))
-		rem = aux2[:len(aux2)-1]
+		for j := 0; j < len(b); j++ {
+			rem[pos+j] = pf.F.Sub(rem[pos+j], pf.F.Mul(l, b[j]))
+		}
 	}
-	return r, rem
+	return r, rem[:len(b)-1]
 }
 
 func max(a, b int) int {
@@ -116,25 +119,23 @@ func (pf PolynomialField) Sub(a, b []*big.Int) []*big.Int {
 
 // Eval evaluates the polinomial over the Finite Field at the given value x
 func (pf PolynomialField) Eval(v []*big.Int, x *big.Int) *big.Int {
+	// Horner's method
 	r := big.NewInt(int64(0))
-	for i := 0; i < len(v); i++ {
-		xi := pf.F.Exp(x, big.NewInt(int64(i)))
-		elem := pf.F.Mul(v[i], xi)
-		r = pf.F.Add(r, elem)
+	for i := len(v) - 1; i >= 0; i-- {
+		r = pf.F.Add(pf.F.Mul(r, x), v[i])
 	}
 	return r
 }
 
 // NewPolZeroAt generates a new polynomial that has value zero at the given value
 func (pf PolynomialField) NewPolZeroAt(pointPos, totalPoints int, height *big.Int) []*big.Int {
-	fac := 1
+	fac := big.NewInt(int64(1))
 	for i := 1; i < totalPoints+1; i++ {
 		if i != pointPos {
-			fac = fac * (pointPos - i)
+			fac = pf.F.Mul(fac, big.NewInt(int64(pointPos-i)))
 		}
 	}
-	facBig := big.NewInt(int64(fac))
-	hf := pf.F.Div(height, facBig)
+	hf := pf.F.Div(height, fac)
 	r := []*big.Int{hf}
 	for i := 1; i < totalPoints+1; i++ {
 		if i != pointPos {
@@ -146,15 +147,128 @@ func (pf PolynomialField) NewPolZeroAt(pointPos, totalPoints int, height *big.In
 	return r
 }
 
+// ZeroPolynomial returns the polynomial Z(x) = (x-1)(x-2)...(x-n), which is zero at the n points where the constraints are interpolated
+func (pf PolynomialField) ZeroPolynomial(n int) []*big.Int {
+	return pf.VanishingPolynomial(points(n))
+}
+
+// VanishingPolynomial returns the polynomial Z(x) = (x-zs[0])(x-zs[1])...(x-zs[n-1]), which is zero at the given points
+func (pf PolynomialField) VanishingPolynomial(zs []*big.Int) []*big.Int {
+	z := []*big.Int{big.NewInt(int64(1))}
+	for _, zi := range zs {
+		z = pf.Mul(
+			z,
+			[]*big.Int{
+				pf.F.Neg(zi),
+				big.NewInt(int64(1)),
+			})
+	}
+	return z
+}
+
+// points returns the points 1..n
+func points(n int) []*big.Int {
+	zs := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		zs[i] = big.NewInt(int64(i + 1))
+	}
+	return zs
+}
+
+// lagrangeBasis holds the values to compute the Lagrange polynomials over the points zs
+type lagrangeBasis struct {
+	zs       []*big.Int
+	z        []*big.Int // Z(x) = (x-zs[0])(x-zs[1])...(x-zs[n-1])
+	invDenom []*big.Int // 1 / Π_{j≠i} (zs[i]-zs[j])
+}
+
+// newLagrangeBasis returns the lagrangeBasis over the points 1..n
+func (pf PolynomialField) newLagrangeBasis(n int) lagrangeBasis {
+	// Π_{j≠i} (i-j) = (i-1)! * (-1)^(n-i) * (n-i)!
+	fac := []*big.Int{big.NewInt(int64(1))}
+	for i := 1; i <= n; i++ {
+		fac = append(fac, pf.F.Mul(fac[i-1], big.NewInt(int64(i))))
+	}
+	invDenom := make([]*big.Int, n)
+	for i := 1; i <= n; i++ {
+		d := pf.F.Mul(fac[i-1], fac[n-i])
+		if (n-i)%2 == 1 {
+			d = pf.F.Neg(d)
+		}
+		invDenom[i-1] = pf.F.Inverse(d)
+	}
+	return lagrangeBasis{
+		zs:       points(n),
+		z:        pf.ZeroPolynomial(n),
+		invDenom: invDenom,
+	}
+}
+
+// newLagrangeBasisAt returns the lagrangeBasis over the given distinct points
+func (pf PolynomialField) newLagrangeBasisAt(zs []*big.Int) lagrangeBasis {
+	invDenom := make([]*big.Int, len(zs))
+	for i := range zs {
+		d := big.NewInt(int64(1))
+		for j := range zs {
+			if j != i {
+				d = pf.F.Mul(d, pf.F.Sub(zs[i], zs[j]))
+			}
+		}
+		invDenom[i] = pf.F.Inverse(d)
+	}
+	return lagrangeBasis{
+		zs:       zs,
+		z:        pf.VanishingPolynomial(zs),
+		invDenom: invDenom,
+	}
+}
+
+// interpolate returns the polynomial that takes the values v at the points of the basis, skipping the zero values
+func (pf PolynomialField) interpolate(basis lagrangeBasis, v []*big.Int) []*big.Int {
+	n := len(v)
+	r := ArrayOfBigZeros(n)
+	q := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		if v[i].Sign() == 0 {
+			continue
+		}
+		// q(x) = Z(x) / (x - zs[i]), by synthetic division
+		point := basis.zs[i]
+		q[n-1] = basis.z[n]
+		for j := n - 1; j > 0; j-- {
+			q[j-1] = pf.F.Add(basis.z[j], pf.F.Mul(point, q[j]))
+		}
+		k := pf.F.Mul(v[i], basis.invDenom[i])
+		for j := 0; j < n; j++ {
+			r[j] = pf.F.Add(r[j], pf.F.Mul(k, q[j]))
+		}
+	}
+	return r
+}
+
 // LagrangeInterpolation performs the Lagrange Interpolation / Lagrange Polynomials operation
 func (pf PolynomialField) LagrangeInterpolation(v []*big.Int) []*big.Int {
 	// https://en.wikipedia.org/wiki/Lagrange_polynomial
-	var r []*big.Int
-	for i := 0; i < len(v); i++ {
-		r = pf.Add(r, pf.NewPolZeroAt(i+1, len(v), v[i]))
+	return pf.interpolate(pf.newLagrangeBasis(len(v)), v)
+}
+
+// LagrangeInterpolationAt returns the polynomial of degree lower than len(zs) that takes the values v at the given
+// distinct points zs
+func (pf PolynomialField) LagrangeInterpolationAt(zs, v []*big.Int) []*big.Int {
+	return pf.interpolate(pf.newLagrangeBasisAt(zs), v)
+}
+
+// LagrangePolynomials returns the n Lagrange polynomials over the points 1..n, where the i-th polynomial is one at the
+// point i+1 and zero at the rest of the points
+func (pf PolynomialField) LagrangePolynomials(n int) [][]*big.Int {
+	basis := pf.newLagrangeBasis(n)
+	var l [][]*big.Int
+	for i := 0; i < n; i++ {
+		v := ArrayOfBigZeros(n)
+		v[i] = big.NewInt(int64(1))
+		l = append(l, pf.interpolate(basis, v))
 	}
-	//
-	return r
+	return l
 }
 
 // R1CSToQAP converts the R1CS values to the QAP values
@@ -162,28 +276,21 @@ func (pf PolynomialField) R1CSToQAP(a, b, c [][]*big.Int) ([][]*big.Int, [][]*bi
 	aT := Transpose(a)
 	bT := Transpose(b)
 	cT := Transpose(c)
+	// the Lagrange basis over the points 1..n is the same for all the signals
+	basis := pf.newLagrangeBasis(len(a))
 	var alphas [][]*big.Int
 	for i := 0; i < len(aT); i++ {
-		alphas = append(alphas, pf.LagrangeInterpolation(aT[i]))
+		alphas = append(alphas, pf.interpolate(basis, aT[i]))
 	}
 	var betas [][]*big.Int
 	for i := 0; i < len(bT); i++ {
-		betas = append(betas, pf.LagrangeInterpolation(bT[i]))
+		betas = append(betas, pf.interpolate(basis, bT[i]))
 	}
 	var gammas [][]*big.Int
 	for i := 0; i < len(cT); i++ {
-		gammas = append(gammas, pf.LagrangeInterpolation(cT[i]))
-	}
-	z := []*big.Int{big.NewInt(int64(1))}
-	for i := 1; i < len(alphas)-1; i++ {
-		z = pf.Mul(
-			z,
-			[]*big.Int{
-				pf.F.Neg(
-					big.NewInt(int64(i))),
-				big.NewInt(int64(1)),
-			})
+		gammas = append(gammas, pf.interpolate(basis, cT[i]))
 	}
+	z := pf.ZeroPolynomial(len(a))
 	return alphas, betas, gammas, z
 }
 
@@ -174,3 +174,197 @@ func TestR1CSToQAP(t *testing.T) {
 	assert.Equal(t, abc, hz)
 
 }
+
+func TestR1CSToSAP(t *testing.T) {
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	pf := NewPolynomialField(fields.NewFq(r))
+
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	b3 := big.NewInt(int64(3))
+	b5 := big.NewInt(int64(5))
+	b9 := big.NewInt(int64(9))
+	b27 := big.NewInt(int64(27))
+	b30 := big.NewInt(int64(30))
+	b35 := big.NewInt(int64(35))
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b1, b0},
+		[]*big.Int{b5, b0, b0, b0, b0, b1},
+	}
+	b := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+	}
+	c := [][]*big.Int{
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b0, b0, b0, b1, b0},
+		[]*big.Int{b0, b0, b0, b0, b0, b1},
+		[]*big.Int{b0, b0, b1, b0, b0, b0},
+	}
+	us, ws, zx := pf.R1CSToSAP(a, b, c, 1)
+	// 6 variables of the R1CS, one for each constraint and one for the public signal
+	assert.Equal(t, 11, len(us))
+	assert.Equal(t, 11, len(ws))
+	// 2 constraints for each R1CS constraint and one for the public signal
+	assert.Equal(t, 10, len(zx))
+
+	w := []*big.Int{b1, b3, b35, b9, b27, b30}
+	sw := pf.SAPWitness(a, b, w, 1)
+	assert.Equal(t, 11, len(sw))
+	// y = (1 + 3)^2
+	assert.Equal(t, big.NewInt(int64(16)), sw[10])
+
+	ux, wx, px := pf.CombineSAPPolynomials(sw, us, ws)
+	hx, rem := pf.Div(px, zx)
+	assert.Equal(t, ArrayOfBigZeros(len(rem)), rem)
+	assert.Equal(t, pf.Sub(pf.Mul(ux, ux), wx), pf.Mul(hx, zx))
+
+	// a wrong witness does not give a P(x) divisible by Z(x)
+	w[2] = big.NewInt(int64(36))
+	sw = pf.SAPWitness(a, b, w, 1)
+	_, _, px = pf.CombineSAPPolynomials(sw, us, ws)
+	_, rem = pf.Div(px, zx)
+	assert.NotEqual(t, ArrayOfBigZeros(len(rem)), rem)
+}
+
+func TestZeroPolynomial(t *testing.T) {
+	// new Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	// new Polynomial Field
+	pf := NewPolynomialField(f)
+
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	// 2 constraints over 6 signals, Z(x) = (x-1)(x-2) is zero at each constraint
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+	}
+	b := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+	}
+	c := [][]*big.Int{
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b0, b0, b0, b1, b0},
+	}
+	_, _, _, zx := pf.R1CSToQAP(a, b, c)
+	assert.Equal(t, pf.ZeroPolynomial(len(a)), zx)
+	assert.Equal(t, len(a)+1, len(zx))
+	for i := 1; i <= len(a); i++ {
+		assert.Equal(t, int64(0), pf.Eval(zx, big.NewInt(int64(i))).Int64())
+	}
+	assert.NotEqual(t, int64(0), pf.Eval(zx, big.NewInt(int64(len(a)+1))).Int64())
+}
+
+func TestDiv(t *testing.T) {
+	// new Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	// new Polynomial Field
+	pf := NewPolynomialField(f)
+
+	b1 := big.NewInt(int64(1))
+	b2 := big.NewInt(int64(2))
+	b3 := big.NewInt(int64(3))
+	b7 := big.NewInt(int64(7))
+
+	// a dividend of lower degree than the divisor is the remainder
+	quo, rem := pf.Div([]*big.Int{b3, b1}, []*big.Int{b1, b2, b1})
+	assert.Equal(t, []*big.Int{}, quo)
+	assert.Equal(t, []*big.Int{b3, b1}, rem)
+
+	// a == quo * b + rem, the remainder has one element less than the divisor
+	a := []*big.Int{b7, b3, b2, b1, b3}
+	b := []*big.Int{b2, b1, b1}
+	quo, rem = pf.Div(a, b)
+	assert.Equal(t, len(a)-len(b)+1, len(quo))
+	assert.Equal(t, len(b)-1, len(rem))
+	assert.Equal(t, a, pf.Add(pf.Mul(quo, b), rem))
+	// the dividend is not modified
+	assert.Equal(t, []*big.Int{b7, b3, b2, b1, b3}, a)
+}
+
+func TestEval(t *testing.T) {
+	// new Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	// new Polynomial Field
+	pf := NewPolynomialField(f)
+
+	// 3 + 2x + 5x^3 at x=4 is 331
+	v := []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(2)), big.NewInt(int64(0)), big.NewInt(int64(5))}
+	assert.Equal(t, big.NewInt(int64(331)), pf.Eval(v, big.NewInt(int64(4))))
+	assert.Equal(t, big.NewInt(int64(0)), pf.Eval([]*big.Int{}, big.NewInt(int64(4))))
+
+	// matches the evaluation term by term
+	x := f.Neg(big.NewInt(int64(9)))
+	expected := big.NewInt(int64(0))
+	for i := 0; i < len(v); i++ {
+		expected = f.Add(expected, f.Mul(v[i], f.Exp(x, big.NewInt(int64(i)))))
+	}
+	assert.Equal(t, expected, pf.Eval(v, x))
+}
+
+func TestInterpolationManyPoints(t *testing.T) {
+	// new Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	// new Polynomial Field
+	pf := NewPolynomialField(f)
+
+	// with more than 20 points the products of the Lagrange denominators overflow an int64
+	n := 30
+	b5 := big.NewInt(int64(5))
+	o := pf.NewPolZeroAt(1, n, b5)
+	assert.Equal(t, b5, pf.Eval(o, big.NewInt(int64(1))))
+	assert.Equal(t, int64(0), pf.Eval(o, big.NewInt(int64(n))).Int64())
+
+	var v []*big.Int
+	for i := 0; i < n; i++ {
+		v = append(v, big.NewInt(int64(i*i+7)))
+	}
+	alpha := pf.LagrangeInterpolation(v)
+	assert.Equal(t, n, len(alpha))
+	for i := 0; i < n; i++ {
+		assert.Equal(t, v[i], pf.Eval(alpha, big.NewInt(int64(i+1))))
+	}
+}
+
+func TestLagrangeInterpolationAt(t *testing.T) {
+	// new Finite Field
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(nil, ok)
+	f := fields.NewFq(r)
+	// new Polynomial Field
+	pf := NewPolynomialField(f)
+
+	zs := []*big.Int{big.NewInt(int64(3)), big.NewInt(int64(0)), f.Neg(big.NewInt(int64(7))), big.NewInt(int64(12))}
+	v := []*big.Int{big.NewInt(int64(5)), big.NewInt(int64(0)), big.NewInt(int64(9)), f.Neg(big.NewInt(int64(1)))}
+	p := pf.LagrangeInterpolationAt(zs, v)
+	assert.Equal(t, len(zs), len(p))
+	z := pf.VanishingPolynomial(zs)
+	assert.Equal(t, len(zs)+1, len(z))
+	for i := range zs {
+		assert.True(t, f.Equal(v[i], pf.Eval(p, zs[i])))
+		assert.Equal(t, int64(0), pf.Eval(z, zs[i]).Int64())
+	}
+
+	// the Lagrange polynomials over the points 1..n
+	n := 5
+	l := pf.LagrangePolynomials(n)
+	assert.Equal(t, n, len(l))
+	for i := 0; i < n; i++ {
+		assert.True(t, BigArraysEqual(pf.NewPolZeroAt(i+1, n, big.NewInt(int64(1))), l[i]))
+	}
+}
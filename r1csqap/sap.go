@@ -0,0 +1,96 @@
+package r1csqap
+
+import (
+	"math/big"
+)
+
+// The Square Arithmetic Program (SAP) of a R1CS takes constraints of the form (u·w)^2 = w·w, with each R1CS constraint
+// a * b = c turned into (a + b)^2 = 4c + d and (a - b)^2 = d over a new variable d. Each public signal x_i also takes
+// the constraint (1 + x_i)^2 = y_i over a new variable y_i, so the polynomials of the public signals are linearly
+// independent. The SAP witness is the R1CS witness followed by the values of d and y.
+
+// R1CSToSAP converts the R1CS values to the SAP values, returning the u and w polynomials of each variable of the SAP
+// witness and the polynomial Z(x), which is zero at the points where the constraints are interpolated
+func (pf PolynomialField) R1CSToSAP(a, b, c [][]*big.Int, nPublic int) ([][]*big.Int, [][]*big.Int, []*big.Int) {
+	nVars := len(a[0])
+	nConstraints := len(a)
+	nSAPVars := nVars + nConstraints + nPublic
+	nRows := 2*nConstraints + nPublic
+	u := make([][]*big.Int, nRows)
+	w := make([][]*big.Int, nRows)
+	for i := 0; i < nRows; i++ {
+		u[i] = ArrayOfBigZeros(nSAPVars)
+		w[i] = ArrayOfBigZeros(nSAPVars)
+	}
+	four := big.NewInt(int64(4))
+	one := big.NewInt(int64(1))
+	for i := 0; i < nConstraints; i++ {
+		d := nVars + i
+		for j := 0; j < nVars; j++ {
+			// (a + b)^2 = 4c + d
+			u[2*i][j] = pf.F.Add(a[i][j], b[i][j])
+			w[2*i][j] = pf.F.Mul(four, c[i][j])
+			// (a - b)^2 = d
+			u[2*i+1][j] = pf.F.Sub(a[i][j], b[i][j])
+		}
+		w[2*i][d] = one
+		w[2*i+1][d] = one
+	}
+	for i := 0; i < nPublic; i++ {
+		// (1 + x_i)^2 = y_i
+		row := 2*nConstraints + i
+		u[row][0] = one
+		u[row][i+1] = one
+		w[row][nVars+nConstraints+i] = one
+	}
+
+	uT := Transpose(u)
+	wT := Transpose(w)
+	// the Lagrange basis over the points 1..n is the same for all the variables
+	basis := pf.newLagrangeBasis(nRows)
+	var us [][]*big.Int
+	for i := 0; i < len(uT); i++ {
+		us = append(us, pf.interpolate(basis, uT[i]))
+	}
+	var ws [][]*big.Int
+	for i := 0; i < len(wT); i++ {
+		ws = append(ws, pf.interpolate(basis, wT[i]))
+	}
+	z := pf.ZeroPolynomial(nRows)
+	return us, ws, z
+}
+
+// SAPWitness extends the R1CS witness r with the values of the variables added by R1CSToSAP
+func (pf PolynomialField) SAPWitness(a, b [][]*big.Int, r []*big.Int, nPublic int) []*big.Int {
+	sw := append([]*big.Int{}, r...)
+	for i := range a {
+		// d = (a - b)^2
+		ar := big.NewInt(int64(0))
+		br := big.NewInt(int64(0))
+		for j := range r {
+			ar = pf.F.Add(ar, pf.F.Mul(a[i][j], r[j]))
+			br = pf.F.Add(br, pf.F.Mul(b[i][j], r[j]))
+		}
+		sw = append(sw, pf.F.Square(pf.F.Sub(ar, br)))
+	}
+	for i := 1; i <= nPublic; i++ {
+		// y = (1 + x_i)^2
+		sw = append(sw, pf.F.Square(pf.F.Add(big.NewInt(int64(1)), r[i])))
+	}
+	return sw
+}
+
+// CombineSAPPolynomials combines the u and w polynomials of the SAP with the SAP witness r, also returning the
+// P(x) = u(x)^2 - w(x)
+func (pf PolynomialField) CombineSAPPolynomials(r []*big.Int, up, wp [][]*big.Int) ([]*big.Int, []*big.Int, []*big.Int) {
+	var ux []*big.Int
+	for i := 0; i < len(r); i++ {
+		ux = pf.Add(ux, pf.Mul([]*big.Int{r[i]}, up[i]))
+	}
+	var wx []*big.Int
+	for i := 0; i < len(r); i++ {
+		wx = pf.Add(wx, pf.Mul([]*big.Int{r[i]}, wp[i]))
+	}
+	px := pf.Sub(pf.Mul(ux, ux), wx)
+	return ux, wx, px
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields/fieldstest"
 	"github.com/arnaucube/go-snark/groth16"
 	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/stretchr/testify/assert"
@@ -71,14 +72,14 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// ---
 	// calculate trusted setup
 	fmt.Println("groth")
-	setup, err := groth16.GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := groth16.GenerateTrustedSetup(nil, len(w), *circuit, alphas, betas, gammas)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(7))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -86,7 +87,7 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// check length of polynomials H(x) and Z(x)
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 
-	proof, err := groth16.GenerateProofs(*circuit, setup, w, px)
+	proof, err := groth16.GenerateProofs(nil, *circuit, setup, w, px)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -98,13 +99,17 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, groth16.VerifyProof(*circuit, setup, proof, publicSignalsVerif, true))
+	verified, err := groth16.VerifyProof(*circuit, setup, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !groth16.VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false))
+	verified, err = groth16.VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
 
 func TestZkFromFlatCircuitCode(t *testing.T) {
@@ -173,7 +178,7 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	assert.Equal(t, 8, len(alphas))
 	assert.Equal(t, 8, len(alphas))
 	assert.Equal(t, 8, len(alphas))
-	assert.Equal(t, 7, len(zxQAP))
+	assert.Equal(t, 8, len(zxQAP))
 	assert.True(t, !bytes.Equal(alphas[1][1].Bytes(), big.NewInt(int64(0)).Bytes()))
 
 	ax, bx, cx, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
@@ -183,7 +188,7 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	assert.Equal(t, 13, len(px))
 
 	hxQAP := Utils.PF.DivisorPolynomial(px, zxQAP)
-	assert.Equal(t, 7, len(hxQAP))
+	assert.Equal(t, 6, len(hxQAP))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hxQAP, zxQAP))
@@ -196,22 +201,23 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 
 	div, rem := Utils.PF.Div(px, zxQAP)
 	assert.Equal(t, hxQAP, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(7))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(nil, len(w), *circuit, alphas, betas, gammas)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
-	// zx and setup.Pk.Z should be the same (currently not, the correct one is the calculation used inside GenerateTrustedSetup function), the calculation is repeated. TODO avoid repeating calculation
+	// zx and setup.Pk.Z are the same, Z(x) has a root at each constraint
 	assert.Equal(t, zxQAP, setup.Pk.Z)
+	assert.Equal(t, len(a)+1, len(setup.Pk.Z))
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	assert.Equal(t, hx, hxQAP)
 	// assert.Equal(t, hxQAP, hx)
 	div, rem = Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(7))
 
 	assert.Equal(t, px, Utils.PF.Mul(hxQAP, zxQAP))
 	// hx==px/zx so px==hx*zx
@@ -233,13 +239,27 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(*circuit, setup, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(*circuit, setup, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// malformed proofs and public signals return an error
+	malformed := proof
+	malformed.PiH = [3]*big.Int{proof.PiH[0], Utils.Bn.G1.F.Add(proof.PiH[1], Utils.Bn.G1.F.One()), proof.PiH[2]}
+	_, err = VerifyProof(*circuit, setup, malformed, publicSignalsVerif, false)
+	assert.Equal(t, "snark: proof piH not on the curve", err.Error())
+	_, err = VerifyProof(*circuit, setup, proof, []*big.Int{new(big.Int).Add(b35Verif, Utils.FqR.Q)}, false)
+	assert.Equal(t, "snark: public signal 0 not inside the Finite Field", err.Error())
+	_, err = VerifyProof(*circuit, setup, proof, nil, false)
+	assert.NotNil(t, err)
 }
 
 func TestZkMultiplication(t *testing.T) {
@@ -306,7 +326,7 @@ func TestZkMultiplication(t *testing.T) {
 	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(4))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(nil, len(w), *circuit, alphas, betas, gammas)
 	assert.Nil(t, err)
 	// fmt.Println("\nt:", setup.Toxic.T)
 
@@ -341,13 +361,17 @@ func TestZkMultiplication(t *testing.T) {
 	b12Verif := big.NewInt(int64(12))
 	publicSignalsVerif := []*big.Int{b12Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(*circuit, setup, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(*circuit, setup, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(11))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
 
 func TestMinimalFlow(t *testing.T) {
@@ -403,14 +427,14 @@ func TestMinimalFlow(t *testing.T) {
 	assert.Equal(t, 13, len(px))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(nil, len(w), *circuit, alphas, betas, gammas)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(7))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -430,11 +454,54 @@ func TestMinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(*circuit, setup, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(*circuit, setup, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(*circuit, setup, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+}
+
+func TestSeededTrustedSetup(t *testing.T) {
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	alphas, betas, gammas, _ := Utils.PF.R1CSToQAP(a, b, c)
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	_, _, _, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
+
+	// the same seed gives the same keys, and so the same proofs
+	setup1, err := GenerateTrustedSetup(fieldstest.NewSeededReader([]byte("seed")), len(w), *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+	setup2, err := GenerateTrustedSetup(fieldstest.NewSeededReader([]byte("seed")), len(w), *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+	assert.Equal(t, setup1, setup2)
+	proof1, err := GenerateProofs(*circuit, setup1, w, px)
+	assert.Nil(t, err)
+	proof2, err := GenerateProofs(*circuit, setup2, w, px)
+	assert.Nil(t, err)
+	assert.Equal(t, proof1, proof2)
+	verified, err := VerifyProof(*circuit, setup1, proof1, []*big.Int{big.NewInt(int64(35))}, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	setup3, err := GenerateTrustedSetup(fieldstest.NewSeededReader([]byte("other seed")), len(w), *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+	assert.NotEqual(t, setup1.Toxic, setup3.Toxic)
 }
@@ -0,0 +1,59 @@
+// Package snarktest provides utilities for testing the Pinocchio zkSNARK. Its functions use the secrets of the Trusted
+// Setup to forge proofs, so they must only be used in tests, to build fixtures or to check the zero-knowledge of the
+// proofs, and never in production.
+package snarktest
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	snark "github.com/arnaucube/go-snark"
+)
+
+// SimulateProof returns a valid Proof for the public signals without a witness, using the Toxic secrets of the Setup.
+// With random a, b and h, and Vkx = Σ IC_i s_i: piA = a G1, piB = b G2, piH = h G1,
+// piC = b (Vkx + piA) - h ρC Z(τ) G1, the knowledge commitments piA' = Ka piA, piB' = Kb b G1, piC' = Kc piC, and
+// piK' = Kβ (Vkx + piA + piC + b G1). a, b and h are read from randReader, or from crypto/rand if it is nil.
+func SimulateProof(randReader io.Reader, setup snark.Setup, publicSignals []*big.Int) (snark.Proof, error) {
+	if randReader == nil {
+		randReader = rand.Reader
+	}
+	bn, fqR := snark.Utils.Bn, snark.Utils.FqR
+	toxic := setup.Toxic
+	if toxic.T == nil || toxic.Ka == nil || toxic.Kb == nil || toxic.Kc == nil || toxic.Kbeta == nil || toxic.RhoC == nil {
+		return snark.Proof{}, errors.New("snarktest: setup without toxic")
+	}
+	if len(publicSignals)+1 != len(setup.Vk.IC) {
+		return snark.Proof{}, errors.New("snarktest: wrong number of public signals")
+	}
+	var r [3]*big.Int
+	for i := range r {
+		x, err := fqR.RandFrom(randReader)
+		if err != nil {
+			return snark.Proof{}, err
+		}
+		r[i] = x
+	}
+	a, b, h := r[0], r[1], r[2]
+
+	vkx := setup.Vk.IC[0]
+	for i, s := range publicSignals {
+		vkx = bn.G1.Add(vkx, bn.G1.MulScalar(setup.Vk.IC[i+1], s))
+	}
+	zt := snark.Utils.PF.Eval(setup.Pk.Z, toxic.T)
+
+	var proof snark.Proof
+	proof.PiA = bn.G1.MulScalar(bn.G1.G, a)
+	proof.PiAp = bn.G1.MulScalar(proof.PiA, toxic.Ka)
+	proof.PiB = bn.G2.MulScalar(bn.G2.G, b)
+	bG1 := bn.G1.MulScalar(bn.G1.G, b)
+	proof.PiBp = bn.G1.MulScalar(bG1, toxic.Kb)
+	proof.PiH = bn.G1.MulScalar(bn.G1.G, h)
+	vkxpia := bn.G1.Add(vkx, proof.PiA)
+	proof.PiC = bn.G1.Sub(bn.G1.MulScalar(vkxpia, b), bn.G1.MulScalar(bn.G1.G, fqR.Mul(h, fqR.Mul(toxic.RhoC, zt))))
+	proof.PiCp = bn.G1.MulScalar(proof.PiC, toxic.Kc)
+	proof.PiKp = bn.G1.MulScalar(bn.G1.Add(bn.G1.Add(vkxpia, proof.PiC), bG1), toxic.Kbeta)
+	return proof, nil
+}
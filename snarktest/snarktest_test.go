@@ -0,0 +1,60 @@
+package snarktest
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	snark "github.com/arnaucube/go-snark"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/fields/fieldstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateProof(t *testing.T) {
+	// y = x^3 + x + 5
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	a, b, c := circuit.GenerateR1CS()
+	alphas, betas, gammas, _ := snark.Utils.PF.R1CSToQAP(a, b, c)
+	setup, err := snark.GenerateTrustedSetup(nil, circuit.NVars, *circuit, alphas, betas, gammas)
+	assert.Nil(t, err)
+
+	// the simulated proofs verify as the proofs from a witness, also for false statements
+	for _, y := range []int64{35, 34} {
+		simulated, err := SimulateProof(nil, setup, []*big.Int{big.NewInt(y)})
+		assert.Nil(t, err)
+		verified, err := snark.VerifyProof(*circuit, setup, simulated, []*big.Int{big.NewInt(y)}, false)
+		assert.Nil(t, err)
+		assert.True(t, verified)
+		verified, err = snark.VerifyProof(*circuit, setup, simulated, []*big.Int{big.NewInt(y + 2)}, false)
+		assert.Nil(t, err)
+		assert.False(t, verified)
+	}
+
+	// the same randomness gives the same simulated proof
+	simulated1, err := SimulateProof(fieldstest.NewSeededReader([]byte("seed")), setup, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	simulated2, err := SimulateProof(fieldstest.NewSeededReader([]byte("seed")), setup, []*big.Int{big.NewInt(int64(35))})
+	assert.Nil(t, err)
+	assert.Equal(t, simulated1, simulated2)
+
+	// without the toxic secrets the proofs can not be simulated
+	var public snark.Setup
+	public.Pk = setup.Pk
+	public.Vk = setup.Vk
+	_, err = SimulateProof(nil, public, []*big.Int{big.NewInt(int64(35))})
+	assert.NotNil(t, err)
+	_, err = SimulateProof(nil, setup, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,200 @@
+// Package transcript implements the Fiat-Shamir transcript of the non-interactive protocols, where the prover and the
+// verifier append the same labeled messages and squeeze from them the challenges in the Finite Field over R of BN128.
+//
+// Each message is encoded as
+//
+//	tag (1 byte) || len(label) (4 bytes big-endian) || label || len(payload) (4 bytes big-endian) || payload
+//
+// where the tag is 0x00 for the label of the protocol given to New, 0x01 for bytes, 0x02 for field elements,
+// 0x03 for G1 points, 0x04 for G2 points and 0x05 for the challenges. Field elements are encoded in 32 bytes
+// big-endian after reducing them modulo r. Points are encoded by their affine coordinates in 32 bytes big-endian, x and
+// y for G1 and x0, x1, y0, y1 for G2, with the point at infinity encoded as all its coordinates set to zero.
+//
+// With SHA-256 and Keccak-256 each challenge hashes the state and the messages appended since the previous challenge
+// into the new state d, and returns H(d || 0x00) || H(d || 0x01) modulo r, so the challenges are uniform in the field.
+// With Poseidon the messages are padded with 0x80 and zeros to a multiple of 31 bytes, and each chunk of 31 bytes is
+// a field element absorbed with the state, up to 15 of them for each Poseidon permutation, where the new state is
+// the challenge.
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/gadgets"
+	"github.com/arnaucube/go-snark/internal/keccak"
+)
+
+// Hash is the hash function of a Transcript
+type Hash int
+
+const (
+	// SHA256 is the SHA-256 hash
+	SHA256 Hash = iota
+	// Keccak256 is the Keccak-256 hash, as used by Ethereum
+	Keccak256
+	// Poseidon is the Poseidon hash over the Finite Field over R, cheap to compute inside a circuit
+	Poseidon
+)
+
+const (
+	tagProtocol byte = iota
+	tagBytes
+	tagScalar
+	tagG1
+	tagG2
+	tagChallenge
+)
+
+// chunkSize is the number of bytes of each field element absorbed by Poseidon
+const chunkSize = 31
+
+type utils struct {
+	Bn  bn128.Bn128
+	FqR fields.Fq
+}
+
+// Utils is the data structure holding the BN128 and the FqR Finite Field over R used by the transcripts
+var Utils = prepareUtils()
+
+func prepareUtils() utils {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		panic(err)
+	}
+	return utils{
+		Bn:  bn,
+		FqR: fields.NewFq(bn.R),
+	}
+}
+
+// Transcript is the Fiat-Shamir transcript of a protocol
+type Transcript struct {
+	hash  Hash
+	state []byte   // state of SHA-256 and Keccak-256
+	field *big.Int // state of Poseidon
+	data  []byte   // messages appended since the last challenge
+}
+
+// New returns the Transcript of the protocol with the given label, using the hash h
+func New(label string, h Hash) *Transcript {
+	t := &Transcript{hash: h, field: big.NewInt(int64(0))}
+	t.append(tagProtocol, label, nil)
+	return t
+}
+
+func (t *Transcript) append(tag byte, label string, payload []byte) {
+	var l [4]byte
+	t.data = append(t.data, tag)
+	binary.BigEndian.PutUint32(l[:], uint32(len(label)))
+	t.data = append(t.data, l[:]...)
+	t.data = append(t.data, label...)
+	binary.BigEndian.PutUint32(l[:], uint32(len(payload)))
+	t.data = append(t.data, l[:]...)
+	t.data = append(t.data, payload...)
+}
+
+func bytes32(x *big.Int) []byte {
+	var b [32]byte
+	return x.FillBytes(b[:])
+}
+
+// AppendBytes appends the labeled bytes b
+func (t *Transcript) AppendBytes(label string, b []byte) {
+	t.append(tagBytes, label, b)
+}
+
+// AppendScalars appends the labeled field elements xs, reduced modulo r
+func (t *Transcript) AppendScalars(label string, xs ...*big.Int) {
+	var payload []byte
+	for _, x := range xs {
+		payload = append(payload, bytes32(Utils.FqR.Affine(x))...)
+	}
+	t.append(tagScalar, label, payload)
+}
+
+// AppendG1 appends the labeled G1 points
+func (t *Transcript) AppendG1(label string, points ...[3]*big.Int) {
+	var payload []byte
+	for _, p := range points {
+		if Utils.Bn.G1.IsZero(p) {
+			payload = append(payload, make([]byte, 64)...)
+			continue
+		}
+		a := Utils.Bn.G1.Affine(p)
+		payload = append(payload, bytes32(a[0])...)
+		payload = append(payload, bytes32(a[1])...)
+	}
+	t.append(tagG1, label, payload)
+}
+
+// AppendG2 appends the labeled G2 points
+func (t *Transcript) AppendG2(label string, points ...[3][2]*big.Int) {
+	var payload []byte
+	for _, p := range points {
+		if Utils.Bn.G2.IsZero(p) {
+			payload = append(payload, make([]byte, 128)...)
+			continue
+		}
+		a := Utils.Bn.G2.Affine(p)
+		payload = append(payload, bytes32(a[0][0])...)
+		payload = append(payload, bytes32(a[0][1])...)
+		payload = append(payload, bytes32(a[1][0])...)
+		payload = append(payload, bytes32(a[1][1])...)
+	}
+	t.append(tagG2, label, payload)
+}
+
+// Challenge squeezes the labeled challenge, an element of the Finite Field over R, from the messages appended to
+// the Transcript
+func (t *Transcript) Challenge(label string) *big.Int {
+	t.append(tagChallenge, label, nil)
+	data := t.data
+	t.data = nil
+	if t.hash == Poseidon {
+		return t.absorbPoseidon(data)
+	}
+	t.state = t.sum(t.state, data)
+	wide := append(t.sum(t.state, []byte{0}), t.sum(t.state, []byte{1})...)
+	return new(big.Int).Mod(new(big.Int).SetBytes(wide), Utils.FqR.Q)
+}
+
+// sum returns the hash of the concatenation of a and b
+func (t *Transcript) sum(a, b []byte) []byte {
+	if t.hash == Keccak256 {
+		return keccak.Hash(a, b)
+	}
+	h := sha256.New()
+	h.Write(a)
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+// absorbPoseidon absorbs the padded data into the Poseidon state, returning the new state
+func (t *Transcript) absorbPoseidon(data []byte) *big.Int {
+	padded := append(append([]byte{}, data...), 0x80)
+	for len(padded)%chunkSize != 0 {
+		padded = append(padded, 0)
+	}
+	var chunks []*big.Int
+	for i := 0; i < len(padded); i += chunkSize {
+		chunks = append(chunks, new(big.Int).SetBytes(padded[i:i+chunkSize]))
+	}
+	for len(chunks) > 0 {
+		n := len(chunks)
+		if n > gadgets.PoseidonMaxInputs-1 {
+			n = gadgets.PoseidonMaxInputs - 1
+		}
+		h, err := gadgets.PoseidonHash(append([]*big.Int{t.field}, chunks[:n]...))
+		if err != nil {
+			// the inputs are always less than r and up to PoseidonMaxInputs
+			panic(err)
+		}
+		t.field = h
+		chunks = chunks[n:]
+	}
+	return new(big.Int).Set(t.field)
+}
@@ -0,0 +1,94 @@
+package transcript
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/arnaucube/go-snark/internal/keccak"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTranscript(h Hash, label string, x *big.Int) *Transcript {
+	t := New("test", h)
+	t.AppendBytes("bytes", []byte("hello"))
+	t.AppendScalars(label, x, big.NewInt(int64(2)))
+	t.AppendG1("g1", Utils.Bn.G1.G, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, big.NewInt(int64(3))))
+	t.AppendG2("g2", Utils.Bn.G2.G)
+	return t
+}
+
+func TestTranscript(t *testing.T) {
+	for _, h := range []Hash{SHA256, Keccak256, Poseidon} {
+		// the same messages give the same challenges
+		t1 := testTranscript(h, "x", big.NewInt(int64(1)))
+		t2 := testTranscript(h, "x", big.NewInt(int64(1)))
+		c1 := t1.Challenge("c")
+		assert.Equal(t, c1, t2.Challenge("c"))
+		assert.True(t, c1.Cmp(Utils.FqR.Q) < 0)
+
+		// the next challenges depend on the previous ones
+		c2 := t1.Challenge("c")
+		assert.NotEqual(t, c1, c2)
+		assert.Equal(t, c2, t2.Challenge("c"))
+
+		// the challenges depend on the labels and on the values
+		assert.NotEqual(t, c1, testTranscript(h, "y", big.NewInt(int64(1))).Challenge("c"))
+		assert.NotEqual(t, c1, testTranscript(h, "x", big.NewInt(int64(5))).Challenge("c"))
+		assert.NotEqual(t, c1, testTranscript(h, "x", big.NewInt(int64(1))).Challenge("d"))
+
+		// the field elements are reduced modulo r
+		tr := testTranscript(h, "x", new(big.Int).Add(Utils.FqR.Q, big.NewInt(int64(1))))
+		assert.Equal(t, c1, tr.Challenge("c"))
+
+		// the messages are not ambiguous
+		ta := New("test", h)
+		ta.AppendBytes("a", []byte("bc"))
+		tb := New("test", h)
+		tb.AppendBytes("ab", []byte("c"))
+		assert.NotEqual(t, ta.Challenge("c"), tb.Challenge("c"))
+	}
+
+	// each hash gives different challenges
+	assert.NotEqual(t, testTranscript(SHA256, "x", big.NewInt(int64(1))).Challenge("c"),
+		testTranscript(Keccak256, "x", big.NewInt(int64(1))).Challenge("c"))
+	assert.NotEqual(t, testTranscript(SHA256, "x", big.NewInt(int64(1))).Challenge("c"),
+		testTranscript(Poseidon, "x", big.NewInt(int64(1))).Challenge("c"))
+
+	// the point at infinity is encoded with zero coordinates
+	tz := New("test", SHA256)
+	tz.AppendG1("p", [3]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(1)), big.NewInt(int64(0))})
+	tz2 := New("test", SHA256)
+	tz2.AppendG1("p", [3]*big.Int{big.NewInt(int64(0)), big.NewInt(int64(0)), big.NewInt(int64(0))})
+	assert.Equal(t, tz.Challenge("c"), tz2.Challenge("c"))
+}
+
+func TestTranscriptEncoding(t *testing.T) {
+	// the documented encoding of the messages
+	data := []byte{0x00, 0, 0, 0, 4, 't', 'e', 's', 't', 0, 0, 0, 0}
+	data = append(data, 0x01, 0, 0, 0, 1, 'b', 0, 0, 0, 2, 0xca, 0xfe)
+	data = append(data, 0x02, 0, 0, 0, 1, 'x', 0, 0, 0, 32)
+	data = append(data, make([]byte, 31)...)
+	data = append(data, 7)
+	data = append(data, 0x05, 0, 0, 0, 1, 'c', 0, 0, 0, 0)
+
+	for _, h := range []Hash{SHA256, Keccak256} {
+		tr := New("test", h)
+		tr.AppendBytes("b", []byte{0xca, 0xfe})
+		tr.AppendScalars("x", big.NewInt(int64(7)))
+		c := tr.Challenge("c")
+
+		var d, wide []byte
+		if h == SHA256 {
+			s := sha256.Sum256(data)
+			d = s[:]
+			s0 := sha256.Sum256(append(append([]byte{}, d...), 0))
+			s1 := sha256.Sum256(append(append([]byte{}, d...), 1))
+			wide = append(s0[:], s1[:]...)
+		} else {
+			d = keccak.Hash(data)
+			wide = append(keccak.Hash(d, []byte{0}), keccak.Hash(d, []byte{1})...)
+		}
+		assert.Equal(t, new(big.Int).Mod(new(big.Int).SetBytes(wide), Utils.FqR.Q), c)
+	}
+}
@@ -0,0 +1,66 @@
+package snark
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// validateVk checks that all the points of the verifying key of the Setup are in G1 and G2
+func validateVk(setup Setup) error {
+	vk := setup.Vk
+	for i, ic := range vk.IC {
+		if err := Utils.Bn.CheckG1("snark", "verifying key IC["+strconv.Itoa(i)+"]", ic); err != nil {
+			return err
+		}
+	}
+	g1s := []struct {
+		name string
+		p    [3]*big.Int
+	}{
+		{"verifying key Vkb", vk.Vkb},
+		{"verifying key G1Kbg", vk.G1Kbg},
+	}
+	for _, g := range g1s {
+		if err := Utils.Bn.CheckG1("snark", g.name, g.p); err != nil {
+			return err
+		}
+	}
+	g2s := []struct {
+		name string
+		p    [3][2]*big.Int
+	}{
+		{"verifying key Vka", vk.Vka},
+		{"verifying key Vkc", vk.Vkc},
+		{"verifying key G2Kbg", vk.G2Kbg},
+		{"verifying key G2Kg", vk.G2Kg},
+		{"verifying key Vkz", vk.Vkz},
+	}
+	for _, g := range g2s {
+		if err := Utils.Bn.CheckG2("snark", g.name, g.p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks that all the points of the Proof are in G1 and G2
+func (proof Proof) Validate() error {
+	g1s := []struct {
+		name string
+		p    [3]*big.Int
+	}{
+		{"proof piA", proof.PiA},
+		{"proof piA'", proof.PiAp},
+		{"proof piB'", proof.PiBp},
+		{"proof piC", proof.PiC},
+		{"proof piC'", proof.PiCp},
+		{"proof piH", proof.PiH},
+		{"proof piK'", proof.PiKp},
+	}
+	for _, g := range g1s {
+		if err := Utils.Bn.CheckG1("snark", g.name, g.p); err != nil {
+			return err
+		}
+	}
+	return Utils.Bn.CheckG2("snark", "proof piB", proof.PiB)
+}